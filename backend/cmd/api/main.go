@@ -12,7 +12,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sitcon-tw/2026-game/internal/repository"
 	"github.com/sitcon-tw/2026-game/internal/router"
+	"github.com/sitcon-tw/2026-game/internal/service/adminaccounts"
 	"github.com/sitcon-tw/2026-game/internal/service/couponsettlement"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/internal/service/gameconfig"
+	"github.com/sitcon-tw/2026-game/internal/service/leaderboard"
+	"github.com/sitcon-tw/2026-game/internal/service/maintenance"
+	"github.com/sitcon-tw/2026-game/internal/service/scheduler"
 	"github.com/sitcon-tw/2026-game/pkg/config"
 	"github.com/sitcon-tw/2026-game/pkg/db"
 	"github.com/sitcon-tw/2026-game/pkg/logger"
@@ -52,19 +58,6 @@ func main() {
 		_ = logger.Sync()
 	}()
 
-	levels, err := config.Levels()
-	if err != nil {
-		logger.Fatal("Failed to load level CSV from LEVEL_CSV_URL", zap.Error(err))
-	}
-	logger.Info("Level config loaded", zap.Int("level_ranges", len(levels)))
-
-	sheetMusic, err := config.SheetMusic()
-	if err != nil {
-		logger.Fatal("Failed to load sheet music CSV from SHEET_MUSIC_CSV_URL", zap.Error(err))
-	}
-	logger.Info("Sheet music loaded", zap.Int("notes", len(sheetMusic)))
-	logger.Info("Game config preload completed")
-
 	otelShutdown, err := telemetry.Init(context.Background(), logger)
 	if err != nil {
 		logger.Error("Failed to initialize OpenTelemetry; continuing without tracing", zap.Error(err))
@@ -86,11 +79,29 @@ func main() {
 	defer db.Close()
 
 	repo := repository.New(db, logger)
+	if err = adminaccounts.Bootstrap(context.Background(), repo, logger); err != nil {
+		logger.Fatal("Failed to bootstrap admin account", zap.Error(err))
+	}
 	appCtx, cancelApp := context.WithCancel(context.Background())
 	defer cancelApp()
-	couponsettlement.New(repo, logger).StartScheduler(appCtx)
+	bus := events.New(db, logger)
+	bus.Listen(appCtx)
+	gameConfig := gameconfig.New(repo, logger, bus)
+	if err = gameConfig.Init(appCtx); err != nil {
+		logger.Fatal("Failed to load game config", zap.Error(err))
+	}
+	gameConfig.Start(appCtx)
+	settlement := couponsettlement.New(repo, logger, bus)
+	sched := scheduler.New(repo, logger)
+	settlement.RegisterJobs(sched)
+	maintenance.RegisterJobs(sched, repo, logger)
+	if err = sched.Start(appCtx); err != nil {
+		logger.Fatal("Failed to start scheduler", zap.Error(err))
+	}
+	board := leaderboard.New(repo, logger, bus)
+	board.Start(appCtx)
 
-	handler := initRoutes(repo, logger)
+	handler := initRoutes(repo, logger, board, bus, settlement, sched, gameConfig)
 	if config.Env().OTelEnabled {
 		handler = otelhttp.NewHandler(
 			handler,
@@ -121,12 +132,21 @@ func main() {
 	}
 }
 
-func initRoutes(repo repository.Repository, logger *zap.Logger) http.Handler {
+func initRoutes(
+	repo repository.Repository,
+	logger *zap.Logger,
+	board *leaderboard.Service,
+	bus *events.Bus,
+	settlement *couponsettlement.Service,
+	sched *scheduler.Scheduler,
+	gameConfig *gameconfig.Service,
+) http.Handler {
 	r := chi.NewRouter()
 	sessionRateLimit := middleware.NewSessionRateLimit()
 
 	// logger
 	r.Use(chimiddleware.RealIP)
+	r.Use(chimiddleware.RequestID)
 	r.Use(middleware.Logger(logger))
 	r.Use(middleware.TraceHandler())
 
@@ -149,14 +169,15 @@ func initRoutes(repo repository.Repository, logger *zap.Logger) http.Handler {
 	// Routes
 	r.Route("/api", func(r chi.Router) {
 		r.Mount("/users", router.UserRoutes(repo, logger, sessionRateLimit))
-		r.Mount("/activities", router.ActivityRoutes(repo, logger, sessionRateLimit))
+		r.Mount("/activities", router.ActivityRoutes(repo, logger, bus, sessionRateLimit))
 		r.Mount("/discount-coupons", router.DiscountRoutes(repo, logger, sessionRateLimit))
 		r.Mount("/announcements", router.AnnouncementRoutes(repo, logger))
-		r.Mount("/admin", router.AdminRoutes(repo, logger, sessionRateLimit))
+		r.Mount("/admin", router.AdminRoutes(repo, logger, settlement, sched, gameConfig, sessionRateLimit))
 
-		r.Mount("/friendships", router.FriendRoutes(repo, logger, sessionRateLimit))
-		r.Mount("/games", router.GameRoutes(repo, logger, sessionRateLimit))
+		r.Mount("/friendships", router.FriendRoutes(repo, logger, bus, sessionRateLimit))
+		r.Mount("/games", router.GameRoutes(repo, logger, board, bus, sessionRateLimit))
 		r.Mount("/group", router.GroupRoutes(repo, logger, sessionRateLimit))
+		r.Mount("/health", router.HealthRoutes(logger, gameConfig))
 	})
 
 	// Swagger API docs
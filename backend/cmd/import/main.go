@@ -33,6 +33,12 @@ const (
 	targetAll           importTarget = "all"
 )
 
+// The seeded coupon rule for joining the tour group, and the activity it is bound to on import.
+const (
+	tourGroupRuleID       = "tour-group-challenge"
+	tourGroupActivityName = "導遊團"
+)
+
 const (
 	sourceAuto dataSource = "auto"
 	sourceFake dataSource = "fake"
@@ -194,6 +200,22 @@ SET token = EXCLUDED.token,
 		}
 	}
 
+	// Migrations run before the first import, so the seeded tour-group rule cannot find its
+	// activity there. Bind it here unless an admin already picked one.
+	const bindTourGroupRule = `
+UPDATE coupon_rules
+SET activity_id = a.id, updated_at = NOW()
+FROM (SELECT id FROM activities WHERE type = $2 AND name = $3 ORDER BY created_at LIMIT 1) a
+WHERE coupon_rules.id = $1 AND coupon_rules.activity_id IS NULL`
+
+	tag, err := tx.Exec(ctx, bindTourGroupRule, tourGroupRuleID, models.ActivitiesTypeChallenge, tourGroupActivityName)
+	if err != nil {
+		return fmt.Errorf("bind coupon rule %s: %w", tourGroupRuleID, err)
+	}
+	if tag.RowsAffected() > 0 {
+		log.Info("bound coupon rule to activity", zap.String("rule", tourGroupRuleID))
+	}
+
 	return tx.Commit(ctx)
 }
 
@@ -366,18 +388,55 @@ func importAnnouncements(ctx context.Context, pool *pgxpool.Pool, log *zap.Logge
 	}()
 
 	const stmt = `
-INSERT INTO announcements (id, content, created_at)
-VALUES ($1, $2, $3)
+INSERT INTO announcements (id, title, content, localizations, severity, pinned, audience, audience_group,
+                           audience_min_level, publish_at, expire_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 ON CONFLICT (id) DO UPDATE
-SET content = EXCLUDED.content,
-    created_at = EXCLUDED.created_at`
+SET title = EXCLUDED.title,
+    content = EXCLUDED.content,
+    localizations = EXCLUDED.localizations,
+    severity = EXCLUDED.severity,
+    pinned = EXCLUDED.pinned,
+    audience = EXCLUDED.audience,
+    audience_group = EXCLUDED.audience_group,
+    audience_min_level = EXCLUDED.audience_min_level,
+    publish_at = EXCLUDED.publish_at,
+    expire_at = EXCLUDED.expire_at,
+    created_at = EXCLUDED.created_at,
+    updated_at = EXCLUDED.updated_at`
 
 	now := time.Now().UTC()
 	for i := range items {
 		if items[i].CreatedAt.IsZero() {
 			items[i].CreatedAt = now
 		}
-		if _, err = tx.Exec(ctx, stmt, items[i].ID, items[i].Content, items[i].CreatedAt); err != nil {
+		if items[i].UpdatedAt.IsZero() {
+			items[i].UpdatedAt = items[i].CreatedAt
+		}
+		if items[i].Severity == "" {
+			items[i].Severity = models.AnnouncementSeverityInfo
+		}
+		if items[i].Audience == "" {
+			items[i].Audience = models.AnnouncementAudienceAll
+		}
+		if items[i].Localizations == nil {
+			items[i].Localizations = map[string]models.AnnouncementLocalization{}
+		}
+		if _, err = tx.Exec(ctx, stmt,
+			items[i].ID,
+			items[i].Title,
+			items[i].Content,
+			items[i].Localizations,
+			items[i].Severity,
+			items[i].Pinned,
+			items[i].Audience,
+			items[i].AudienceGroup,
+			items[i].AudienceMinLevel,
+			items[i].PublishAt,
+			items[i].ExpireAt,
+			items[i].CreatedAt,
+			items[i].UpdatedAt,
+		); err != nil {
 			return fmt.Errorf("insert announcements[%d] (%s): %w", i, items[i].ID, err)
 		}
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
@@ -74,6 +75,7 @@ func (h *Handler) ActivityCheckIn(w http.ResponseWriter, r *http.Request) {
 	status := "already checked in"
 	if inserted {
 		status = "check-in recorded"
+		h.Events.Publish(r.Context(), events.Event{Kind: events.KindCheckIn, UserIDs: []string{user.ID}})
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -126,7 +128,7 @@ func (h *Handler) applyActivityCheckInRewards(ctx context.Context, tx pgx.Tx, us
 	if err := h.Repo.IncrementUnlockLevelBy(ctx, tx, userID, increment); err != nil {
 		return newCheckinErr(http.StatusInternalServerError, err, "failed to update user unlock level")
 	}
-	if err := h.issueCheckInCoupon(ctx, tx, userID); err != nil {
+	if _, err := h.Coupons.Evaluate(ctx, tx, userID); err != nil {
 		return newCheckinErr(http.StatusInternalServerError, err, "failed to issue coupon")
 	}
 	return nil
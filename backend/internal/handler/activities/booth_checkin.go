@@ -5,23 +5,26 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
-	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/internal/service/qrtoken"
 	"github.com/sitcon-tw/2026-game/pkg/helpers"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
+var errAlreadyVisited = errors.New("already visited")
+
 type boothCheckInRequest struct {
 	UserQRCode string `json:"user_qr_code"`
 }
 
 // BoothCheckIn handles POST /activities/booth/user/check-ins.
 // @Summary      攤位掃描使用者 QR code 打卡
-// @Description  可掃描使用者的活動工作人員（攤位/闖關）使用活動專用登入後掃描使用者 QR code。首次打卡成功會依活動類型增加 unlock_level：booth +2、challenge +3。
+// @Description  可掃描使用者的活動工作人員（攤位/闖關）使用活動專用登入後掃描使用者以 purpose=booth_check_in 取得的 one-time QR code（每個 QR code 只能使用一次）。首次打卡成功會依活動類型增加 unlock_level：booth +2、challenge +3。
 // @Tags         activities
 // @Accept       json
 // @Produce      json
@@ -29,6 +32,7 @@ type boothCheckInRequest struct {
 // @Success      200  {object}  checkinResponse
 // @Failure      400  {object}  res.ErrorResponse "bad request"
 // @Failure      401  {object}  res.ErrorResponse "unauthorized booth"
+// @Failure      409  {object}  res.ErrorResponse "qr code already used"
 // @Failure      500  {object}  res.ErrorResponse
 // @Router       /activities/booth/user/check-ins [post]
 func (h *Handler) BoothCheckIn(w http.ResponseWriter, r *http.Request) {
@@ -68,7 +72,7 @@ func (h *Handler) BoothCheckIn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.processBoothVisit(r.Context(), tx, targetUserID, booth.ID, booth.Name, booth.Type); err != nil {
+	if err = h.processBoothVisit(r.Context(), tx, targetUserID, booth.ID, booth.Type); err != nil {
 		var ce *checkinError
 		if errors.As(err, &ce) {
 			res.Fail(w, r, ce.status, ce.cause, ce.message)
@@ -82,6 +86,7 @@ func (h *Handler) BoothCheckIn(w http.ResponseWriter, r *http.Request) {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
 		return
 	}
+	h.Events.Publish(r.Context(), events.Event{Kind: events.KindCheckIn, UserIDs: []string{targetUserID}})
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
@@ -89,35 +94,20 @@ func (h *Handler) BoothCheckIn(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) resolveUserIDFromQRCode(r *http.Request, tx pgx.Tx, qrCode string) (string, error) {
-	userID, err := helpers.VerifyAndExtractUserIDFromOneTimeQRToken(
-		qrCode,
-		time.Now().UTC(),
-		func(uid string) (string, error) {
-			u, lookupErr := h.Repo.GetUserByID(r.Context(), tx, uid)
-			if lookupErr != nil {
-				if errors.Is(lookupErr, repository.ErrNotFound) {
-					return "", repository.ErrNotFound
-				}
-				return "", lookupErr
-			}
-			return u.QRCodeToken, nil
-		},
-	)
+	user, err := qrtoken.Consume(r.Context(), h.Repo, tx, qrCode, helpers.QRPurposeBoothCheckIn)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
+		switch {
+		case errors.Is(err, qrtoken.ErrUserNotFound):
 			return "", newCheckinErr(http.StatusBadRequest, nil, "user not found")
+		case errors.Is(err, qrtoken.ErrTokenUsed):
+			return "", newCheckinErr(http.StatusConflict, err, "qr code already used")
+		case errors.Is(err, qrtoken.ErrInvalidToken):
+			return "", err
+		default:
+			return "", newCheckinErr(http.StatusInternalServerError, err, "failed to fetch user")
 		}
-		return "", err
 	}
-
-	if _, err = h.Repo.GetUserByID(r.Context(), tx, userID); err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return "", newCheckinErr(http.StatusBadRequest, nil, "user not found")
-		}
-		return "", newCheckinErr(http.StatusInternalServerError, err, "failed to fetch user")
-	}
-
-	return userID, nil
+	return user.ID, nil
 }
 
 func (h *Handler) processBoothVisit(
@@ -125,7 +115,6 @@ func (h *Handler) processBoothVisit(
 	tx pgx.Tx,
 	userID string,
 	boothID string,
-	boothName string,
 	boothType models.ActivitiesTypes,
 ) error {
 	inserted, err := h.Repo.AddVisited(ctx, tx, userID, boothID)
@@ -133,7 +122,7 @@ func (h *Handler) processBoothVisit(
 		return newCheckinErr(http.StatusInternalServerError, err, "failed to record visit")
 	}
 	if !inserted {
-		return newCheckinErr(http.StatusBadRequest, nil, "already visited")
+		return newCheckinErr(http.StatusBadRequest, errAlreadyVisited, "already visited")
 	}
 
 	increment, ok := unlockIncrementByActivityType(boothType)
@@ -144,11 +133,16 @@ func (h *Handler) processBoothVisit(
 	if err = h.Repo.IncrementUnlockLevelBy(ctx, tx, userID, increment); err != nil {
 		return newCheckinErr(http.StatusInternalServerError, err, "failed to update user unlock level")
 	}
-	if err = h.issueCheckInCoupon(ctx, tx, userID); err != nil {
+	if _, err = h.Coupons.Evaluate(ctx, tx, userID); err != nil {
 		return newCheckinErr(http.StatusInternalServerError, err, "failed to issue coupon")
 	}
-	if err = h.issueTourGroupChallengeCoupon(ctx, tx, userID, boothName, boothType); err != nil {
-		return newCheckinErr(http.StatusInternalServerError, err, "failed to issue coupon")
+	if err = audit.Record(ctx, h.Repo, tx, audit.Entry{
+		Action:     audit.ActionBoothCheckIn,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      map[string]string{"activity_id": boothID},
+	}); err != nil {
+		return newCheckinErr(http.StatusInternalServerError, err, "failed to write audit event")
 	}
 
 	return nil
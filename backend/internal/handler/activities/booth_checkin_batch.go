@@ -0,0 +1,198 @@
+package activities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/internal/service/qrtoken"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+	"go.uber.org/zap"
+)
+
+// Per-scan outcomes of a batch upload.
+const (
+	batchStatusRecorded       = "recorded"
+	batchStatusAlreadyVisited = "already_visited"
+	batchStatusDuplicate      = "duplicate"
+	batchStatusTooLate        = "too_late"
+	batchStatusOutsideBundle  = "outside_bundle"
+	batchStatusInvalidToken   = "invalid_token"
+	batchStatusTokenUsed      = "token_used"
+	batchStatusUserNotFound   = "user_not_found"
+	batchStatusFailed         = "failed"
+)
+
+type boothBatchScan struct {
+	UserQRCode string    `json:"user_qr_code"`
+	ScannedAt  time.Time `json:"scanned_at"`
+}
+
+type boothCheckInBatchRequest struct {
+	BundleID        string           `json:"bundle_id"`
+	BundleExpiresAt time.Time        `json:"bundle_expires_at"`
+	Signature       string           `json:"signature"`
+	Scans           []boothBatchScan `json:"scans"`
+}
+
+type boothBatchResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	UserID string `json:"user_id,omitempty"`
+}
+
+type boothCheckInBatchResponse struct {
+	Recorded int                `json:"recorded"`
+	Results  []boothBatchResult `json:"results"`
+}
+
+// BoothCheckInBatch handles POST /activities/booth/user/check-ins/batch.
+// @Summary      攤位上傳離線打卡佇列
+// @Description  上傳攤位離線時排入佇列的掃描。bundle_id 與 bundle_expires_at 取自 /activities/booth/offline-bundle，signature 為以上傳包的 batch_key 為金鑰、對每筆掃描依序串接「user_qr_code\n scanned_at 的 unix 秒數\n」計算的 HMAC-SHA256（hex）。每筆掃描以掃描當下時間驗證 user QR token（qru2，過渡期間亦接受 qru1），同一使用者只計一次；scanned_at 須落在上傳包有效期間內，且距今不超過 max_lateness_seconds。每筆結果獨立處理：recorded、already_visited、duplicate、too_late、outside_bundle、invalid_token、token_used、user_not_found、failed。
+// @Tags         activities
+// @Accept       json
+// @Produce      json
+// @Param        request  body      boothCheckInBatchRequest  true  "Signed offline scan queue"
+// @Success      200  {object}  boothCheckInBatchResponse
+// @Failure      400  {object}  res.ErrorResponse "bad request"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized booth | invalid signature"
+// @Failure      410  {object}  res.ErrorResponse "bundle expired"
+// @Router       /activities/booth/user/check-ins/batch [post]
+func (h *Handler) BoothCheckInBatch(w http.ResponseWriter, r *http.Request) {
+	booth, ok := middleware.BoothFromContext(r.Context())
+	if !ok || booth == nil {
+		res.Fail(w, r, http.StatusUnauthorized, nil, "unauthorized booth")
+		return
+	}
+
+	var req boothCheckInBatchRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+	cfg := config.Env()
+	if req.BundleID == "" || req.BundleExpiresAt.IsZero() || req.Signature == "" {
+		res.Fail(w, r, http.StatusBadRequest, nil, "missing bundle")
+		return
+	}
+	if len(req.Scans) == 0 || len(req.Scans) > cfg.BoothOfflineMaxBatch {
+		res.Fail(w, r, http.StatusBadRequest, nil, "invalid batch size")
+		return
+	}
+
+	scans := make([]helpers.BoothBatchScan, 0, len(req.Scans))
+	for _, scan := range req.Scans {
+		scans = append(scans, helpers.BoothBatchScan{UserQRCode: scan.UserQRCode, ScannedAt: scan.ScannedAt})
+	}
+	bundleKey := helpers.BoothBundleKey(cfg.BoothOfflineSecret, booth.ID, req.BundleID, req.BundleExpiresAt)
+	if !helpers.VerifyBoothBatch(bundleKey, scans, req.Signature) {
+		res.Fail(w, r, http.StatusUnauthorized, nil, "invalid signature")
+		return
+	}
+
+	now := time.Now().UTC()
+	if now.After(req.BundleExpiresAt.Add(cfg.BoothOfflineMaxLateness)) {
+		res.Fail(w, r, http.StatusGone, nil, "bundle expired")
+		return
+	}
+
+	resp := boothCheckInBatchResponse{Results: make([]boothBatchResult, 0, len(req.Scans))}
+	seen := make(map[string]struct{}, len(req.Scans))
+	var recordedUserIDs []string
+	for i, scan := range req.Scans {
+		result := h.applyBatchScan(r.Context(), booth, req.BundleExpiresAt, scan, now, seen)
+		result.Index = i
+		if result.Status == batchStatusRecorded {
+			resp.Recorded++
+			recordedUserIDs = append(recordedUserIDs, result.UserID)
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if len(recordedUserIDs) > 0 {
+		h.Events.Publish(r.Context(), events.Event{Kind: events.KindCheckIn, UserIDs: recordedUserIDs})
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// applyBatchScan records one queued scan in its own transaction, so a failing scan does not
+// undo the others. seen holds users already handled in this batch.
+func (h *Handler) applyBatchScan(
+	ctx context.Context,
+	booth *models.Activities,
+	bundleExpiresAt time.Time,
+	scan boothBatchScan,
+	now time.Time,
+	seen map[string]struct{},
+) boothBatchResult {
+	cfg := config.Env()
+	bundleIssuedAt := bundleExpiresAt.Add(-cfg.BoothOfflineBundleTTL)
+	if scan.ScannedAt.Before(bundleIssuedAt) || scan.ScannedAt.After(bundleExpiresAt) ||
+		scan.ScannedAt.After(now.Add(helpers.QRTokenTTL())) {
+		return boothBatchResult{Status: batchStatusOutsideBundle}
+	}
+	if now.Sub(scan.ScannedAt) > cfg.BoothOfflineMaxLateness {
+		return boothBatchResult{Status: batchStatusTooLate}
+	}
+
+	tx, err := h.Repo.StartTransaction(ctx)
+	if err != nil {
+		h.Logger.Error("booth batch: start tx failed", zap.Error(err))
+		return boothBatchResult{Status: batchStatusFailed}
+	}
+	defer h.Repo.DeferRollback(ctx, tx)
+
+	user, err := qrtoken.ConsumeAt(
+		ctx,
+		h.Repo,
+		tx,
+		scan.UserQRCode,
+		helpers.QRPurposeBoothCheckIn,
+		scan.ScannedAt,
+		cfg.BoothOfflineMaxLateness,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, qrtoken.ErrInvalidToken):
+			return boothBatchResult{Status: batchStatusInvalidToken}
+		case errors.Is(err, qrtoken.ErrTokenUsed):
+			return boothBatchResult{Status: batchStatusTokenUsed}
+		case errors.Is(err, qrtoken.ErrUserNotFound):
+			return boothBatchResult{Status: batchStatusUserNotFound}
+		default:
+			h.Logger.Error("booth batch: consume qr token failed", zap.Error(err))
+			return boothBatchResult{Status: batchStatusFailed}
+		}
+	}
+
+	if _, dup := seen[user.ID]; dup {
+		return boothBatchResult{Status: batchStatusDuplicate, UserID: user.ID}
+	}
+	seen[user.ID] = struct{}{}
+
+	if err = h.processBoothVisit(ctx, tx, user.ID, booth.ID, booth.Type); err != nil {
+		if errors.Is(err, errAlreadyVisited) {
+			return boothBatchResult{Status: batchStatusAlreadyVisited, UserID: user.ID}
+		}
+		h.Logger.Error("booth batch: process visit failed", zap.Error(err))
+		return boothBatchResult{Status: batchStatusFailed, UserID: user.ID}
+	}
+
+	if err = h.Repo.CommitTransaction(ctx, tx); err != nil {
+		h.Logger.Error("booth batch: commit tx failed", zap.Error(err))
+		return boothBatchResult{Status: batchStatusFailed, UserID: user.ID}
+	}
+	return boothBatchResult{Status: batchStatusRecorded, UserID: user.ID}
+}
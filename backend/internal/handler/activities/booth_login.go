@@ -4,26 +4,34 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/devicesessions"
 	"github.com/sitcon-tw/2026-game/pkg/helpers"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
+type boothLoginRequest struct {
+	DeviceLabel string `json:"device_label"`
+}
+
 // BoothLogin handles POST /activities/booth/session.
 // @Summary      攤位登入
-// @Description  可掃描使用者的活動（攤位/闖關）使用此 API 登入系統，成功後會在 cookie 設定 booth_token，之後即可使用 /activities/booth/ 底下的功能。
+// @Description  可掃描使用者的活動（攤位/闖關）使用此 API 登入系統，成功後會在 cookie 設定 booth_token（一組新的 session，而非活動 token 本身），之後即可使用 /activities/booth/ 底下的功能。session 於 BOOTH_SESSION_TTL 後過期，需重新登入；管理員可在後台查看並撤銷。body 可省略，device_label 用來辨識裝置，未提供時使用 User-Agent。
 // @Tags         activities
+// @Accept       json
 // @Produce      json
+// @Param        Authorization  header  string             true   "Bearer {token}"
+// @Param        request        body    boothLoginRequest  false  "Device label"
 // @Success      200  {object}  models.Activities
-// @Failure      400  {object}  res.ErrorResponse "missing token"
+// @Failure      400  {object}  res.ErrorResponse "missing token | invalid request body"
 // @Failure      401  {object}  res.ErrorResponse "unauthorized booth"
 // @Failure      500  {object}  res.ErrorResponse
-// @Param        Authorization  header  string  true  "Bearer {token}"
 // @Router       /activities/booth/session [post]
 func (h *Handler) BoothLogin(w http.ResponseWriter, r *http.Request) {
 	token := helpers.BearerToken(r.Header.Get("Authorization"))
@@ -32,6 +40,14 @@ func (h *Handler) BoothLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var req boothLoginRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+
 	tx, err := h.Repo.StartTransaction(r.Context())
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
@@ -45,19 +61,62 @@ func (h *Handler) BoothLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sessionToken, session, err := devicesessions.OpenBooth(
+		r.Context(), h.Repo, tx, booth.ID, devicesessions.DeviceLabel(req.DeviceLabel, r.UserAgent()),
+	)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create booth session")
+		return
+	}
+
 	err = h.Repo.CommitTransaction(r.Context(), tx)
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
 		return
 	}
 
-	http.SetCookie(w, helpers.NewCookie("booth_token", booth.Token, 30*24*time.Hour))
+	http.SetCookie(w, helpers.NewCookie("booth_token", sessionToken, time.Until(session.ExpiresAt)))
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(booth)
 }
 
+// BoothLogout handles DELETE /activities/booth/session.
+// @Summary      攤位登出
+// @Description  撤銷目前 booth_token cookie 對應的 session 並清除 cookie。
+// @Tags         activities
+// @Success      204  "No Content"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /activities/booth/session [delete]
+func (h *Handler) BoothLogout(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie("booth_token")
+	if err != nil || cookie.Value == "" {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("missing booth session"), "unauthorized")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	if err = h.Repo.DeleteBoothSessionByHash(r.Context(), tx, helpers.HashSecret(cookie.Value)); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to revoke booth session")
+		return
+	}
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	http.SetCookie(w, helpers.NewCookie("booth_token", "", -time.Hour))
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // requireScannerActivityByToken loads an activity by login token and ensures it can scan users.
 func (h *Handler) requireScannerActivityByToken(ctx context.Context, tx pgx.Tx, token string) (*models.Activities, error) {
 	booth, err := h.Repo.GetActivityByToken(ctx, tx, token)
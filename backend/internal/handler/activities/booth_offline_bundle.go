@@ -0,0 +1,68 @@
+package activities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+// offlineBundleResponse lets a booth verify qru2 tokens offline with QRPublicKey, a base64url
+// Ed25519 key, and sign the queued scans with BatchKey.
+type offlineBundleResponse struct {
+	BundleID           string    `json:"bundle_id"`
+	BoothID            string    `json:"booth_id"`
+	Purpose            string    `json:"purpose"`
+	IssuedAt           time.Time `json:"issued_at"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	MaxLatenessSeconds int       `json:"max_lateness_seconds"`
+	MaxBatchSize       int       `json:"max_batch_size"`
+	BatchKey           string    `json:"batch_key"`
+	QRPublicKey        string    `json:"qr_public_key"`
+	QRStepSeconds      int       `json:"qr_step_seconds"`
+	QRWindowSteps      int       `json:"qr_window_steps"`
+}
+
+// BoothOfflineBundle handles GET /activities/booth/offline-bundle.
+// @Summary      取得攤位離線上傳包
+// @Description  攤位在網路不穩時使用。qru2 token 的格式為 qru2.<purpose>.<base64url(userID)>.<nonce>.<step>.<base64url(signature)>，signature 是伺服器以 Ed25519 對最後一個「.」之前內容的簽章。攤位離線時以 qr_public_key 驗證簽章，確認 purpose 為 booth_check_in、step 與 floor(掃描時間 / qr_step_seconds) 相差不超過 qr_window_steps，並在本機略過重複的 nonce，再把 user_qr_code 與掃描時間排入佇列；公鑰只能驗證、無法產生 token。恢復連線後以 batch_key 簽署佇列並上傳到 /activities/booth/user/check-ins/batch，伺服器會依掃描時間再驗證一次每個 token 並確認 nonce 只使用一次。batch_key 只綁定此攤位、bundle_id 與 expires_at，不含任何使用者的金鑰。上傳包於 expires_at 後失效，佇列須在掃描後 max_lateness_seconds 內上傳。需要攤位的 token cookie。
+// @Tags         activities
+// @Produce      json
+// @Success      200  {object}  offlineBundleResponse
+// @Failure      401  {object}  res.ErrorResponse "unauthorized booth"
+// @Router       /activities/booth/offline-bundle [get]
+func (h *Handler) BoothOfflineBundle(w http.ResponseWriter, r *http.Request) {
+	booth, ok := middleware.BoothFromContext(r.Context())
+	if !ok || booth == nil {
+		res.Fail(w, r, http.StatusUnauthorized, nil, "unauthorized booth")
+		return
+	}
+
+	cfg := config.Env()
+	now := time.Now().UTC().Truncate(time.Second)
+	bundleID := uuid.NewString()
+	expiresAt := now.Add(cfg.BoothOfflineBundleTTL)
+	resp := offlineBundleResponse{
+		BundleID:           bundleID,
+		BoothID:            booth.ID,
+		Purpose:            string(helpers.QRPurposeBoothCheckIn),
+		IssuedAt:           now,
+		ExpiresAt:          expiresAt,
+		MaxLatenessSeconds: int(cfg.BoothOfflineMaxLateness.Seconds()),
+		MaxBatchSize:       cfg.BoothOfflineMaxBatch,
+		BatchKey:           helpers.BoothBundleKey(cfg.BoothOfflineSecret, booth.ID, bundleID, expiresAt),
+		QRPublicKey:        base64.RawURLEncoding.EncodeToString(cfg.UserQRPublicKey()),
+		QRStepSeconds:      int(helpers.QRTokenTTL().Seconds()),
+		QRWindowSteps:      helpers.QRTokenWindowSteps(),
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
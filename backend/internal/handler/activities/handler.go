@@ -2,16 +2,20 @@ package activities
 
 import (
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/couponrules"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
 	"go.uber.org/zap"
 )
 
 // Handler handles activity-related requests.
 type Handler struct {
-	Repo   repository.Repository
-	Logger *zap.Logger
+	Repo    repository.Repository
+	Logger  *zap.Logger
+	Events  *events.Bus
+	Coupons *couponrules.Engine
 }
 
 // New wires required dependencies for the activities handler.
-func New(repo repository.Repository, logger *zap.Logger) *Handler {
-	return &Handler{Repo: repo, Logger: logger}
+func New(repo repository.Repository, logger *zap.Logger, bus *events.Bus) *Handler {
+	return &Handler{Repo: repo, Logger: logger, Events: bus, Coupons: couponrules.New(repo)}
 }
@@ -0,0 +1,386 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+//nolint:golines // keep struct tags aligned
+type activityRequest struct {
+	Type        models.ActivitiesTypes `json:"type"`
+	Name        string                 `json:"name"`
+	Floor       *string                `json:"floor"`
+	Link        *string                `json:"link"`
+	Description *string                `json:"description"`
+}
+
+// adminActivity exposes the login token that models.Activities hides from public responses.
+//
+//nolint:golines // keep struct tags aligned
+type adminActivity struct {
+	ID          string                 `json:"id"`
+	Token       string                 `json:"token"`
+	Type        models.ActivitiesTypes `json:"type"`
+	QRCodeToken string                 `json:"qrcode_token"`
+	Name        string                 `json:"name"`
+	Floor       *string                `json:"floor,omitempty"`
+	Link        *string                `json:"link,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+var errMissingName = errors.New("name is required")
+
+// ListActivities handles GET /admin/activities.
+// @Summary      列出活動
+// @Description  需要 admin_token cookie。回傳所有活動，包含攤位登入用的 token 與 qrcode_token。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   adminActivity
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities [get]
+func (h *Handler) ListActivities(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	activities, err := h.Repo.ListActivities(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list activities")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	resp := make([]adminActivity, 0, len(activities))
+	for i := range activities {
+		resp = append(resp, toAdminActivity(&activities[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// CreateActivity handles POST /admin/activities.
+// @Summary      建立活動
+// @Description  需要 admin_token cookie。type 為 booth、check 或 challenge。token 與 qrcode_token 由系統產生。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      activityRequest  true  "Activity"
+// @Success      201  {object}  adminActivity
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid activity type | name is required"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities [post]
+func (h *Handler) CreateActivity(w http.ResponseWriter, r *http.Request) {
+	activity, ok := decodeActivityRequest(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	if err = h.Repo.CreateActivity(r.Context(), tx, activity); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create activity")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionActivityCreate,
+		TargetType: audit.TargetActivity,
+		TargetID:   activity.ID,
+		After:      toAdminActivity(activity),
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(toAdminActivity(activity))
+}
+
+// UpdateActivity handles PUT /admin/activities/{id}.
+// @Summary      更新活動
+// @Description  需要 admin_token cookie。覆寫 type、name、floor、link、description；省略 floor、link、description 會清空該欄位。token 與 qrcode_token 不變，請改用 rotation API。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string           true  "Activity ID"
+// @Param        request  body      activityRequest  true  "Activity"
+// @Success      200  {object}  adminActivity
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid activity type | name is required"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "activity not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities/{id} [put]
+func (h *Handler) UpdateActivity(w http.ResponseWriter, r *http.Request) {
+	activity, ok := decodeActivityRequest(w, r)
+	if !ok {
+		return
+	}
+	activity.ID = chi.URLParam(r, "id")
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	before, err := h.Repo.GetActivityByID(r.Context(), tx, activity.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "activity not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to find activity")
+		}
+		return
+	}
+
+	if err = h.Repo.UpdateActivity(r.Context(), tx, activity); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "activity not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to update activity")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionActivityUpdate,
+		TargetType: audit.TargetActivity,
+		TargetID:   activity.ID,
+		Before:     toAdminActivity(before),
+		After:      toAdminActivity(activity),
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(toAdminActivity(activity))
+}
+
+// DeleteActivity handles DELETE /admin/activities/{id}.
+// @Summary      刪除活動
+// @Description  需要 admin_token cookie。已有使用者打卡的活動不能刪除，以免影響使用者進度。
+// @Tags         admin
+// @Param        id   path      string  true  "Activity ID"
+// @Success      204
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "activity not found"
+// @Failure      409  {object}  res.ErrorResponse "activity already visited"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities/{id} [delete]
+func (h *Handler) DeleteActivity(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	id := chi.URLParam(r, "id")
+	before, err := h.Repo.GetActivityByID(r.Context(), tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "activity not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to find activity")
+		}
+		return
+	}
+
+	if err = h.Repo.DeleteActivity(r.Context(), tx, id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			res.Fail(w, r, http.StatusNotFound, err, "activity not found")
+		case errors.Is(err, repository.ErrInUse):
+			res.Fail(w, r, http.StatusConflict, err, "activity already visited")
+		default:
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to delete activity")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionActivityDelete,
+		TargetType: audit.TargetActivity,
+		TargetID:   id,
+		Before:     toAdminActivity(before),
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateActivityToken handles POST /admin/activities/{id}/token-rotations.
+// @Summary      重新產生活動登入 token
+// @Description  需要 admin_token cookie。產生新的攤位登入 token，使用舊 token 的攤位會被登出。
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Activity ID"
+// @Success      200  {object}  adminActivity
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "activity not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities/{id}/token-rotations [post]
+func (h *Handler) RotateActivityToken(w http.ResponseWriter, r *http.Request) {
+	h.rotateActivity(w, r, audit.ActionActivityTokenRotate, h.Repo.RotateActivityToken)
+}
+
+// RotateActivityQRCode handles POST /admin/activities/{id}/qrcode-rotations.
+// @Summary      重新產生活動 QR code
+// @Description  需要 admin_token cookie。產生新的 qrcode_token，已印出的舊 QR code 會失效。
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Activity ID"
+// @Success      200  {object}  adminActivity
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "activity not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities/{id}/qrcode-rotations [post]
+func (h *Handler) RotateActivityQRCode(w http.ResponseWriter, r *http.Request) {
+	h.rotateActivity(w, r, audit.ActionActivityQRCodeRotate, h.Repo.RotateActivityQRCode)
+}
+
+func (h *Handler) rotateActivity(
+	w http.ResponseWriter,
+	r *http.Request,
+	action string,
+	rotate func(ctx context.Context, tx pgx.Tx, id string) (*models.Activities, error),
+) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	activity, err := rotate(r.Context(), tx, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "activity not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to rotate activity token")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     action,
+		TargetType: audit.TargetActivity,
+		TargetID:   activity.ID,
+		After:      toAdminActivity(activity),
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(toAdminActivity(activity))
+}
+
+func decodeActivityRequest(w http.ResponseWriter, r *http.Request) (*models.Activities, bool) {
+	var req activityRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return nil, false
+	}
+
+	switch req.Type {
+	case models.ActivitiesTypeBooth, models.ActivitiesTypeCheck, models.ActivitiesTypeChallenge:
+	default:
+		res.Fail(w, r, http.StatusBadRequest, errInvalidActivityType, errInvalidActivityType.Error())
+		return nil, false
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		res.Fail(w, r, http.StatusBadRequest, errMissingName, errMissingName.Error())
+		return nil, false
+	}
+
+	return &models.Activities{
+		Type:        req.Type,
+		Name:        name,
+		Floor:       optionalText(req.Floor),
+		Link:        optionalText(req.Link),
+		Description: optionalText(req.Description),
+	}, true
+}
+
+// optionalText trims s and maps blank values to nil so cleared fields are stored as NULL.
+func optionalText(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func toAdminActivity(a *models.Activities) adminActivity {
+	return adminActivity{
+		ID:          a.ID,
+		Token:       a.Token,
+		Type:        a.Type,
+		QRCodeToken: a.QRCodeToken,
+		Name:        a.Name,
+		Floor:       a.Floor,
+		Link:        a.Link,
+		Description: a.Description,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+}
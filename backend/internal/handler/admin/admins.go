@@ -0,0 +1,358 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/adminaccounts"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+const maxAdminUsernameLength = 64
+
+var (
+	errInvalidAdminRole = errors.New("role must be superadmin, coupon-manager, viewer or support")
+	errChangeOwnAdmin   = errors.New("cannot change your own admin account")
+)
+
+type createAdminRequest struct {
+	Username string           `json:"username"`
+	Role     models.AdminRole `json:"role"`
+}
+
+type updateAdminRequest struct {
+	Role     models.AdminRole `json:"role"`
+	Disabled bool             `json:"disabled"`
+}
+
+type adminWithKeyResponse struct {
+	Admin    models.Admin `json:"admin"`
+	LoginKey string       `json:"login_key"`
+}
+
+// ListAdmins handles GET /admin/admins.
+// @Summary      列出管理員帳號
+// @Description  需要 admins.manage 權限（superadmin）。回傳所有管理員帳號與角色，不含 login key。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   models.Admin
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/admins [get]
+func (h *Handler) ListAdmins(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	admins, err := h.Repo.ListAdmins(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list admins")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(admins)
+}
+
+// CreateAdmin handles POST /admin/admins.
+// @Summary      建立管理員帳號
+// @Description  需要 admins.manage 權限（superadmin）。role 為 superadmin、coupon-manager、viewer 或 support。login key 由系統產生且只會在此回傳一次，伺服器僅保存雜湊。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      createAdminRequest  true  "Admin account"
+// @Success      201  {object}  adminWithKeyResponse
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | username is required | invalid role"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      409  {object}  res.ErrorResponse "username already exists"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/admins [post]
+func (h *Handler) CreateAdmin(w http.ResponseWriter, r *http.Request) {
+	var req createAdminRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+	username := strings.TrimSpace(req.Username)
+	if username == "" || len(username) > maxAdminUsernameLength {
+		res.Fail(w, r, http.StatusBadRequest, errors.New("invalid username"), "username is required")
+		return
+	}
+	if !req.Role.Valid() {
+		res.Fail(w, r, http.StatusBadRequest, errInvalidAdminRole, "invalid role")
+		return
+	}
+
+	key, keyHash, err := adminaccounts.NewLoginKey()
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to generate login key")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	admin := &models.Admin{Username: username, Role: req.Role, KeyHash: keyHash}
+	if err = h.Repo.CreateAdmin(r.Context(), tx, admin); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			res.Fail(w, r, http.StatusConflict, err, "username already exists")
+			return
+		}
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create admin")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionAdminCreate,
+		TargetType: audit.TargetAdmin,
+		TargetID:   admin.ID,
+		After:      admin,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(adminWithKeyResponse{Admin: *admin, LoginKey: key})
+}
+
+// UpdateAdmin handles PUT /admin/admins/{id}.
+// @Summary      更新管理員帳號
+// @Description  需要 admins.manage 權限（superadmin）。更新角色與停用狀態；停用時撤銷該帳號所有 session。不能修改自己的帳號。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string              true  "Admin ID"
+// @Param        request  body      updateAdminRequest  true  "Role and disabled flag"
+// @Success      200  {object}  models.Admin
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid role | cannot change your own admin account"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "admin not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/admins/{id} [put]
+func (h *Handler) UpdateAdmin(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if isCurrentAdmin(r, id) {
+		res.Fail(w, r, http.StatusBadRequest, errChangeOwnAdmin, "cannot change your own admin account")
+		return
+	}
+
+	var req updateAdminRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+	if !req.Role.Valid() {
+		res.Fail(w, r, http.StatusBadRequest, errInvalidAdminRole, "invalid role")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	before, err := h.Repo.GetAdminByID(r.Context(), tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "admin not found")
+			return
+		}
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to find admin")
+		return
+	}
+
+	admin := &models.Admin{ID: id, Role: req.Role, Disabled: req.Disabled}
+	if err = h.Repo.UpdateAdmin(r.Context(), tx, admin); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "admin not found")
+			return
+		}
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to update admin")
+		return
+	}
+	if admin.Disabled {
+		if _, err = h.Repo.DeleteAdminSessionsByAdmin(r.Context(), tx, admin.ID); err != nil {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to revoke admin sessions")
+			return
+		}
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionAdminUpdate,
+		TargetType: audit.TargetAdmin,
+		TargetID:   admin.ID,
+		Before:     before,
+		After:      admin,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(admin)
+}
+
+// RotateAdminKey handles POST /admin/admins/{id}/key-rotations.
+// @Summary      重新產生管理員 login key
+// @Description  需要 admins.manage 權限（superadmin）。產生新的 login key（只會在此回傳一次），舊 key 立即失效並撤銷該帳號所有 session。
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Admin ID"
+// @Success      200  {object}  adminWithKeyResponse
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "admin not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/admins/{id}/key-rotations [post]
+func (h *Handler) RotateAdminKey(w http.ResponseWriter, r *http.Request) {
+	key, keyHash, err := adminaccounts.NewLoginKey()
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to generate login key")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	admin, err := h.Repo.SetAdminKeyHash(r.Context(), tx, chi.URLParam(r, "id"), keyHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "admin not found")
+			return
+		}
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to rotate login key")
+		return
+	}
+	if _, err = h.Repo.DeleteAdminSessionsByAdmin(r.Context(), tx, admin.ID); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to revoke admin sessions")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionAdminKeyRotate,
+		TargetType: audit.TargetAdmin,
+		TargetID:   admin.ID,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(adminWithKeyResponse{Admin: *admin, LoginKey: key})
+}
+
+// DeleteAdmin handles DELETE /admin/admins/{id}.
+// @Summary      刪除管理員帳號
+// @Description  需要 admins.manage 權限（superadmin）。刪除帳號並撤銷其所有 session。不能刪除自己的帳號。
+// @Tags         admin
+// @Param        id   path      string  true  "Admin ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  res.ErrorResponse "cannot change your own admin account"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "admin not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/admins/{id} [delete]
+func (h *Handler) DeleteAdmin(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if isCurrentAdmin(r, id) {
+		res.Fail(w, r, http.StatusBadRequest, errChangeOwnAdmin, "cannot change your own admin account")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	before, err := h.Repo.GetAdminByID(r.Context(), tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "admin not found")
+			return
+		}
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to find admin")
+		return
+	}
+
+	if err = h.Repo.DeleteAdmin(r.Context(), tx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "admin not found")
+			return
+		}
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to delete admin")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionAdminDelete,
+		TargetType: audit.TargetAdmin,
+		TargetID:   id,
+		Before:     before,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func isCurrentAdmin(r *http.Request, id string) bool {
+	current, ok := middleware.AdminFromContext(r.Context())
+	return ok && current != nil && current.ID == id
+}
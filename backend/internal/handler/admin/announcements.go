@@ -0,0 +1,321 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+//nolint:golines // keep struct tags aligned
+type announcementRequest struct {
+	Title            string                                     `json:"title"`
+	Content          string                                     `json:"content"`
+	Localizations    map[string]models.AnnouncementLocalization `json:"localizations"`
+	Severity         models.AnnouncementSeverity                `json:"severity"`
+	Pinned           bool                                       `json:"pinned"`
+	Audience         models.AnnouncementAudience                `json:"audience"`
+	AudienceGroup    *string                                    `json:"audience_group"`
+	AudienceMinLevel *int                                       `json:"audience_min_level"`
+	PublishAt        *time.Time                                 `json:"publish_at"`
+	ExpireAt         *time.Time                                 `json:"expire_at"`
+}
+
+var (
+	errMissingContent          = errors.New("content is required")
+	errInvalidSeverity         = errors.New("invalid severity")
+	errInvalidAudience         = errors.New("invalid audience")
+	errMissingAudienceGroup    = errors.New("group audience requires audience_group")
+	errMissingAudienceMinLevel = errors.New("min_level audience requires a non-negative audience_min_level")
+	errInvalidAnnouncementTime = errors.New("expire_at must be after publish_at")
+	errInvalidLocalization     = errors.New("localizations need a locale key and content")
+)
+
+// ListAnnouncements handles GET /admin/announcements.
+// @Summary      列出公告
+// @Description  需要 admin_token cookie。回傳所有公告（包含尚未發布、已過期與指定對象的公告），置頂的在前，其餘依發布時間由新到舊。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   models.Announcement
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/announcements [get]
+func (h *Handler) ListAnnouncements(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	items, err := h.Repo.ListAnnouncements(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list announcements")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(items)
+}
+
+// CreateAnnouncement handles POST /admin/announcements.
+// @Summary      建立公告
+// @Description  需要 admin_token cookie。title、content 為預設語系內容，localizations 以語系（如 en、zh-TW）為 key 提供翻譯。severity 為 info（預設）、warning 或 critical。audience 為 all（預設，所有人含未登入）、group（audience_group 組員）或 min_level（current_level 不低於 audience_min_level 的使用者）。publish_at 之前不顯示（null 表示立即），expire_at 之後不再顯示（null 表示不過期）。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      announcementRequest  true  "Announcement"
+// @Success      201  {object}  models.Announcement
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid announcement"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/announcements [post]
+func (h *Handler) CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	item, ok := decodeAnnouncementRequest(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	if err = h.Repo.CreateAnnouncement(r.Context(), tx, item); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create announcement")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionAnnouncementCreate,
+		TargetType: audit.TargetAnnouncement,
+		TargetID:   item.ID,
+		After:      item,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(item)
+}
+
+// UpdateAnnouncement handles PUT /admin/announcements/{id}.
+// @Summary      更新公告
+// @Description  需要 admin_token cookie。覆寫公告所有欄位（欄位說明同建立公告），created_at 不變。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string               true  "Announcement ID"
+// @Param        request  body      announcementRequest  true  "Announcement"
+// @Success      200  {object}  models.Announcement
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid announcement"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "announcement not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/announcements/{id} [put]
+func (h *Handler) UpdateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	item, ok := decodeAnnouncementRequest(w, r)
+	if !ok {
+		return
+	}
+	item.ID = chi.URLParam(r, "id")
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	before, err := h.Repo.GetAnnouncement(r.Context(), tx, item.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "announcement not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to find announcement")
+		}
+		return
+	}
+
+	if err = h.Repo.UpdateAnnouncement(r.Context(), tx, item); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "announcement not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to update announcement")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionAnnouncementUpdate,
+		TargetType: audit.TargetAnnouncement,
+		TargetID:   item.ID,
+		Before:     before,
+		After:      item,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(item)
+}
+
+// DeleteAnnouncement handles DELETE /admin/announcements/{id}.
+// @Summary      刪除公告
+// @Description  需要 admin_token cookie。
+// @Tags         admin
+// @Param        id   path      string  true  "Announcement ID"
+// @Success      204
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "announcement not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/announcements/{id} [delete]
+func (h *Handler) DeleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	id := chi.URLParam(r, "id")
+	before, err := h.Repo.GetAnnouncement(r.Context(), tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "announcement not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to find announcement")
+		}
+		return
+	}
+
+	if err = h.Repo.DeleteAnnouncement(r.Context(), tx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "announcement not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to delete announcement")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionAnnouncementDelete,
+		TargetType: audit.TargetAnnouncement,
+		TargetID:   id,
+		Before:     before,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeAnnouncementRequest(w http.ResponseWriter, r *http.Request) (*models.Announcement, bool) {
+	var req announcementRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return nil, false
+	}
+
+	item := &models.Announcement{
+		Title:         strings.TrimSpace(req.Title),
+		Content:       strings.TrimSpace(req.Content),
+		Localizations: make(map[string]models.AnnouncementLocalization, len(req.Localizations)),
+		Severity:      req.Severity,
+		Pinned:        req.Pinned,
+		Audience:      req.Audience,
+		PublishAt:     utcPtr(req.PublishAt),
+		ExpireAt:      utcPtr(req.ExpireAt),
+	}
+	if item.Severity == "" {
+		item.Severity = models.AnnouncementSeverityInfo
+	}
+	if item.Audience == "" {
+		item.Audience = models.AnnouncementAudienceAll
+	}
+	for locale, l := range req.Localizations {
+		item.Localizations[strings.TrimSpace(locale)] = models.AnnouncementLocalization{
+			Title:   strings.TrimSpace(l.Title),
+			Content: strings.TrimSpace(l.Content),
+		}
+	}
+	switch item.Audience {
+	case models.AnnouncementAudienceGroup:
+		item.AudienceGroup = optionalText(req.AudienceGroup)
+	case models.AnnouncementAudienceMinLevel:
+		item.AudienceMinLevel = req.AudienceMinLevel
+	}
+
+	if err := validateAnnouncement(item); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, err.Error())
+		return nil, false
+	}
+	return item, true
+}
+
+func validateAnnouncement(item *models.Announcement) error {
+	if item.Content == "" {
+		return errMissingContent
+	}
+	for locale, l := range item.Localizations {
+		if locale == "" || l.Content == "" {
+			return errInvalidLocalization
+		}
+	}
+	switch item.Severity {
+	case models.AnnouncementSeverityInfo, models.AnnouncementSeverityWarning, models.AnnouncementSeverityCritical:
+	default:
+		return errInvalidSeverity
+	}
+	switch item.Audience {
+	case models.AnnouncementAudienceAll:
+	case models.AnnouncementAudienceGroup:
+		if item.AudienceGroup == nil {
+			return errMissingAudienceGroup
+		}
+	case models.AnnouncementAudienceMinLevel:
+		if item.AudienceMinLevel == nil || *item.AudienceMinLevel < 0 {
+			return errMissingAudienceMinLevel
+		}
+	default:
+		return errInvalidAudience
+	}
+	if item.PublishAt != nil && item.ExpireAt != nil && !item.ExpireAt.After(*item.PublishAt) {
+		return errInvalidAnnouncementTime
+	}
+	return nil
+}
@@ -5,7 +5,11 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/couponrules"
+	"github.com/sitcon-tw/2026-game/internal/service/notifications"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
@@ -17,7 +21,7 @@ type assignCouponRequest struct {
 
 // AssignCouponToUser handles POST /admin/discount-coupons/assignments.
 // @Summary      直接發放折扣券給使用者
-// @Description  需要 admin_token cookie。透過 user_id 直接建立 discount coupon 給該使用者。
+// @Description  需要 admin_token cookie。透過 user_id 直接建立 discount coupon 給該使用者。discount_id 有對應的折扣券規則時會占用該規則的庫存與預算，用完時拒絕發放。
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -26,6 +30,7 @@ type assignCouponRequest struct {
 // @Failure      400          {object}  res.ErrorResponse "invalid payload"
 // @Failure      401          {object}  res.ErrorResponse "unauthorized"
 // @Failure      404          {object}  res.ErrorResponse "user not found"
+// @Failure      409          {object}  res.ErrorResponse "coupon out of stock"
 // @Failure      500          {object}  res.ErrorResponse
 // @Router       /admin/discount-coupons/assignments [post]
 func (h *Handler) AssignCouponToUser(w http.ResponseWriter, r *http.Request) {
@@ -57,11 +62,29 @@ func (h *Handler) AssignCouponToUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.Coupons.Reserve(r.Context(), tx, req.DiscountID, req.Price); err != nil {
+		respondReserveError(w, r, err)
+		return
+	}
+
 	coupon, err := h.Repo.InsertDiscountCouponForUser(r.Context(), tx, req.UserID, req.Price, req.DiscountID)
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to assign coupon")
 		return
 	}
+	if err = notifications.CouponIssued(r.Context(), h.Repo, tx, coupon, models.CouponSourceAdmin); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to notify user")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionCouponAssign,
+		TargetType: audit.TargetCoupon,
+		TargetID:   coupon.ID,
+		After:      coupon,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
 
 	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
@@ -72,3 +95,11 @@ func (h *Handler) AssignCouponToUser(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(coupon)
 }
+
+func respondReserveError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, couponrules.ErrOutOfStock) {
+		res.Fail(w, r, http.StatusConflict, err, "coupon out of stock")
+		return
+	}
+	res.Fail(w, r, http.StatusInternalServerError, err, "failed to reserve coupon stock")
+}
@@ -0,0 +1,205 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAuditEventLimit = 100
+	maxAuditEventLimit     = 500
+)
+
+type auditEventsResponse struct {
+	Items []models.AuditEvent `json:"items"`
+	// NextBefore and NextBeforeID are passed as before and before_id to fetch the next page;
+	// omitted on the last page.
+	NextBefore   *time.Time `json:"next_before,omitempty"`
+	NextBeforeID string     `json:"next_before_id,omitempty"`
+}
+
+// ListAuditEvents handles GET /admin/audit-events.
+// @Summary      查詢稽核紀錄
+// @Description  需要 audit.read 權限（superadmin）。依建立時間由新到舊回傳 admin、staff 與攤位的異動紀錄，包含操作者、動作、目標、異動前後 JSON（token 等機密欄位已移除）、request ID 與 trace ID。可用 actor_type、actor_id、action、target_type、target_id 篩選，since／until 為 RFC3339 時間區間（until 不含）。分頁時將回應的 next_before 與 next_before_id 分別帶入 before 與 before_id；同一時間建立的多筆紀錄以 id 排序，不會在分頁邊界遺漏。
+// @Tags         admin
+// @Produce      json
+// @Param        actor_type   query     string  false  "admin | staff | booth"
+// @Param        actor_id     query     string  false  "Actor ID"
+// @Param        action       query     string  false  "Action, e.g. gift_coupon.create"
+// @Param        target_type  query     string  false  "Target type"
+// @Param        target_id    query     string  false  "Target ID"
+// @Param        since        query     string  false  "RFC3339, inclusive"
+// @Param        until        query     string  false  "RFC3339, exclusive"
+// @Param        before       query     string  false  "RFC3339 cursor from next_before"
+// @Param        before_id    query     string  false  "Cursor from next_before_id"
+// @Param        limit        query     int     false  "Page size (default 100, max 500)"
+// @Success      200  {object}  auditEventsResponse
+// @Failure      400  {object}  res.ErrorResponse "invalid filter"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/audit-events [get]
+func (h *Handler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditEventFilter(r)
+	if err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid filter")
+		return
+	}
+	filter.Limit = defaultAuditEventLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, convErr := strconv.Atoi(raw)
+		if convErr != nil || n <= 0 {
+			res.Fail(w, r, http.StatusBadRequest, errors.New("invalid limit"), "invalid limit")
+			return
+		}
+		filter.Limit = min(n, maxAuditEventLimit)
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	resp := auditEventsResponse{Items: make([]models.AuditEvent, 0)}
+	err = h.Repo.ListAuditEvents(r.Context(), tx, filter, func(e models.AuditEvent) error {
+		resp.Items = append(resp.Items, e)
+		return nil
+	})
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list audit events")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	if len(resp.Items) == filter.Limit {
+		last := resp.Items[len(resp.Items)-1]
+		resp.NextBefore = &last.CreatedAt
+		resp.NextBeforeID = last.ID
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// ExportAuditEvents handles GET /admin/audit-events/export.
+// @Summary      匯出稽核紀錄（JSONL）
+// @Description  需要 audit.read 權限（superadmin）。篩選條件同查詢稽核紀錄但不分頁，每行一筆 JSON，依建立時間由新到舊串流輸出。
+// @Tags         admin
+// @Produce      application/x-ndjson
+// @Param        actor_type   query     string  false  "admin | staff | booth"
+// @Param        actor_id     query     string  false  "Actor ID"
+// @Param        action       query     string  false  "Action"
+// @Param        target_type  query     string  false  "Target type"
+// @Param        target_id    query     string  false  "Target ID"
+// @Param        since        query     string  false  "RFC3339, inclusive"
+// @Param        until        query     string  false  "RFC3339, exclusive"
+// @Success      200  {string}  string  "One models.AuditEvent per line"
+// @Failure      400  {object}  res.ErrorResponse "invalid filter"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/audit-events/export [get]
+func (h *Handler) ExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditEventFilter(r)
+	if err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid filter")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	// The status line goes out with the first row; after that a failure can only be logged.
+	started := false
+	encoder := json.NewEncoder(w)
+	err = h.Repo.ListAuditEvents(r.Context(), tx, filter, func(e models.AuditEvent) error {
+		if !started {
+			writeAuditExportHeader(w)
+			started = true
+		}
+		return encoder.Encode(e)
+	})
+	if err != nil {
+		if !started {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to export audit events")
+			return
+		}
+		h.Logger.Error("audit export aborted", zap.Error(err))
+		return
+	}
+	if !started {
+		writeAuditExportHeader(w)
+	}
+
+	_ = h.Repo.CommitTransaction(r.Context(), tx)
+}
+
+func writeAuditExportHeader(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit-events.jsonl"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// parseAuditEventFilter reads the shared filter query parameters; Limit is left to the caller.
+func parseAuditEventFilter(r *http.Request) (repository.AuditEventFilter, error) {
+	q := r.URL.Query()
+	filter := repository.AuditEventFilter{
+		ActorType:  models.AuditActorType(q.Get("actor_type")),
+		ActorID:    q.Get("actor_id"),
+		Action:     q.Get("action"),
+		TargetType: q.Get("target_type"),
+		TargetID:   q.Get("target_id"),
+		BeforeID:   q.Get("before_id"),
+	}
+	switch filter.ActorType {
+	case "", models.AuditActorAdmin, models.AuditActorStaff, models.AuditActorBooth:
+	default:
+		return filter, errors.New("invalid actor_type")
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+		{"before", &filter.Before},
+	} {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return filter, errors.New("invalid " + p.name)
+		}
+		t = t.UTC()
+		*p.dst = &t
+	}
+	if filter.BeforeID != "" {
+		if _, err := uuid.Parse(filter.BeforeID); err != nil || filter.Before == nil {
+			return filter, errors.New("invalid before_id")
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,263 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+//nolint:golines // keep struct tags aligned
+type couponRuleRequest struct {
+	Trigger       models.CouponRuleTrigger `json:"trigger"`
+	Threshold     int                      `json:"threshold"`
+	ActivityTypes []models.ActivitiesTypes `json:"activity_types"`
+	ActivityID    *string                  `json:"activity_id"`
+	Amount        int                      `json:"amount"`
+	StockLimit    *int                     `json:"stock_limit"`
+	Budget        *int                     `json:"budget"`
+	ValidFrom     *time.Time               `json:"valid_from"`
+	ValidUntil    *time.Time               `json:"valid_until"`
+	Description   string                   `json:"description"`
+	SortOrder     int                      `json:"sort_order"`
+	Enabled       bool                     `json:"enabled"`
+}
+
+var (
+	errInvalidCouponTrigger  = errors.New("invalid trigger")
+	errInvalidCouponAmount   = errors.New("amount must be positive")
+	errInvalidCouponLimit    = errors.New("threshold, stock_limit and budget must not be negative")
+	errInvalidCouponWindow   = errors.New("valid_until must be after valid_from")
+	errInvalidActivityType   = errors.New("invalid activity type")
+	errCouponActivityMissing = errors.New("activity trigger requires activity_id")
+)
+
+// ListCouponRules handles GET /admin/coupon-rules.
+// @Summary      列出折扣券規則
+// @Description  需要 admin_token cookie。回傳所有折扣券規則（包含停用的），依 sort_order 排序。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   models.CouponRule
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/coupon-rules [get]
+func (h *Handler) ListCouponRules(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	rules, err := h.Repo.ListCouponRules(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list coupon rules")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	if rules == nil {
+		rules = []models.CouponRule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(rules)
+}
+
+// PutCouponRule handles PUT /admin/coupon-rules/{id}.
+// @Summary      建立或更新折扣券規則
+// @Description  需要 admin_token cookie。id 即發放折扣券的 discount_id。trigger：level（current_level 達 threshold）、activity_visits（造訪 threshold 個 activity_types 類型的活動，空陣列表示全部類型）、activity（造訪 activity_id 指定的活動）、friends（好友數達 threshold）、group_check_ins（group 簽到次數達 threshold）、leaderboard_rank（排行榜結算時名次在 threshold 內）、manual（僅由工作人員發放）。stock_limit 為總發放張數上限、budget 為總發放金額（NT$）上限，null 表示不限；已發放數量（issued_count、issued_amount，包含未兌換的 gift coupon）不會因修改而重設，調降上限不會收回已發放的折扣券。修改後於下一次使用者進度變動時生效。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string             true  "Discount ID"
+// @Param        request  body      couponRuleRequest  true  "Coupon rule"
+// @Success      200  {object}  models.CouponRule
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid rule | activity not found"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/coupon-rules/{id} [put]
+func (h *Handler) PutCouponRule(w http.ResponseWriter, r *http.Request) {
+	var req couponRuleRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+	if err := validateCouponRule(req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	if req.ActivityID != nil {
+		if _, err = h.Repo.GetActivityByID(r.Context(), tx, *req.ActivityID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				res.Fail(w, r, http.StatusBadRequest, err, "activity not found")
+			} else {
+				res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch activity")
+			}
+			return
+		}
+	}
+
+	before, err := h.Repo.GetCouponRule(r.Context(), tx, chi.URLParam(r, "id"))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch coupon rule")
+		return
+	}
+
+	rule := &models.CouponRule{
+		ID:            chi.URLParam(r, "id"),
+		Trigger:       req.Trigger,
+		Threshold:     req.Threshold,
+		ActivityTypes: req.ActivityTypes,
+		ActivityID:    req.ActivityID,
+		Amount:        req.Amount,
+		StockLimit:    req.StockLimit,
+		Budget:        req.Budget,
+		ValidFrom:     utcPtr(req.ValidFrom),
+		ValidUntil:    utcPtr(req.ValidUntil),
+		Description:   req.Description,
+		SortOrder:     req.SortOrder,
+		Enabled:       req.Enabled,
+	}
+	if err = h.Repo.UpsertCouponRule(r.Context(), tx, rule); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to save coupon rule")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionCouponRulePut,
+		TargetType: audit.TargetCouponRule,
+		TargetID:   rule.ID,
+		Before:     before,
+		After:      rule,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteCouponRule handles DELETE /admin/coupon-rules/{id}.
+// @Summary      刪除折扣券規則
+// @Description  需要 admin_token cookie。刪除後不再發放，已發放的折扣券仍可使用。若只想暫停發放，請改用 enabled=false。
+// @Tags         admin
+// @Param        id   path      string  true  "Discount ID"
+// @Success      204
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "coupon rule not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/coupon-rules/{id} [delete]
+func (h *Handler) DeleteCouponRule(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	id := chi.URLParam(r, "id")
+	before, err := h.Repo.GetCouponRule(r.Context(), tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "coupon rule not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch coupon rule")
+		}
+		return
+	}
+
+	if err = h.Repo.DeleteCouponRule(r.Context(), tx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "coupon rule not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to delete coupon rule")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionCouponRuleDelete,
+		TargetType: audit.TargetCouponRule,
+		TargetID:   id,
+		Before:     before,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateCouponRule(req couponRuleRequest) error {
+	switch req.Trigger {
+	case models.CouponTriggerLevel,
+		models.CouponTriggerActivityVisits,
+		models.CouponTriggerFriends,
+		models.CouponTriggerGroupCheckIns,
+		models.CouponTriggerLeaderboardRank,
+		models.CouponTriggerManual:
+	case models.CouponTriggerActivity:
+		if req.ActivityID == nil {
+			return errCouponActivityMissing
+		}
+	default:
+		return errInvalidCouponTrigger
+	}
+	if req.Amount <= 0 {
+		return errInvalidCouponAmount
+	}
+	if req.Threshold < 0 || (req.StockLimit != nil && *req.StockLimit < 0) || (req.Budget != nil && *req.Budget < 0) {
+		return errInvalidCouponLimit
+	}
+	if req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidUntil.After(*req.ValidFrom) {
+		return errInvalidCouponWindow
+	}
+	for _, t := range req.ActivityTypes {
+		switch t {
+		case models.ActivitiesTypeBooth, models.ActivitiesTypeCheck, models.ActivitiesTypeChallenge:
+		default:
+			return errInvalidActivityType
+		}
+	}
+	return nil
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
@@ -15,7 +16,7 @@ type createGiftCouponRequest struct {
 
 // CreateGiftCoupon handles POST /admin/gift-coupons.
 // @Summary      建立 gift coupon
-// @Description  需要 admin_token cookie。建立一張 gift coupon（token 可讓使用者兌換折扣券）。
+// @Description  需要 admin_token cookie。建立一張 gift coupon（token 可讓使用者兌換折扣券）。discount_id 有對應的折扣券規則時，建立當下即占用該規則的庫存與預算，用完時拒絕建立。
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -23,6 +24,7 @@ type createGiftCouponRequest struct {
 // @Success      201          {object}  models.DiscountCouponGift
 // @Failure      400          {object}  res.ErrorResponse "invalid payload"
 // @Failure      401          {object}  res.ErrorResponse "unauthorized"
+// @Failure      409          {object}  res.ErrorResponse "coupon out of stock"
 // @Failure      500          {object}  res.ErrorResponse
 // @Router       /admin/gift-coupons [post]
 func (h *Handler) CreateGiftCoupon(w http.ResponseWriter, r *http.Request) {
@@ -45,11 +47,25 @@ func (h *Handler) CreateGiftCoupon(w http.ResponseWriter, r *http.Request) {
 	}
 	defer h.Repo.DeferRollback(r.Context(), tx)
 
+	if err = h.Coupons.Reserve(r.Context(), tx, req.DiscountID, req.Price); err != nil {
+		respondReserveError(w, r, err)
+		return
+	}
+
 	gift, err := h.Repo.CreateDiscountCouponGift(r.Context(), tx, req.Price, req.DiscountID)
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create gift coupon")
 		return
 	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionGiftCouponCreate,
+		TargetType: audit.TargetGiftCoupon,
+		TargetID:   gift.ID,
+		After:      gift,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
 
 	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
@@ -6,12 +6,13 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
 // DeleteGiftCoupon handles DELETE /admin/gift-coupons/{id}.
 // @Summary      刪除 gift coupon
-// @Description  需要 admin_token cookie。依 gift coupon id 刪除。
+// @Description  需要 admin_token cookie。依 gift coupon id 刪除。尚未兌換的 gift coupon 所占用的規則庫存與預算會一併釋放。
 // @Tags         admin
 // @Produce      json
 // @Param        id           path      string  true  "Gift coupon ID (UUID)"
@@ -35,7 +36,8 @@ func (h *Handler) DeleteGiftCoupon(w http.ResponseWriter, r *http.Request) {
 	}
 	defer h.Repo.DeferRollback(r.Context(), tx)
 
-	if err = h.Repo.DeleteDiscountCouponGiftByID(r.Context(), tx, id); err != nil {
+	gift, err := h.Repo.DeleteDiscountCouponGiftByID(r.Context(), tx, id)
+	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			res.Fail(w, r, http.StatusNotFound, err, "gift coupon not found")
 			return
@@ -44,6 +46,20 @@ func (h *Handler) DeleteGiftCoupon(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.Coupons.Release(r.Context(), tx, gift.DiscountID, gift.Price); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to release coupon stock")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionGiftCouponDelete,
+		TargetType: audit.TargetGiftCoupon,
+		TargetID:   gift.ID,
+		Before:     gift,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
 	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
 		return
@@ -0,0 +1,276 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+type revokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// sessionOwner adapts staff and booth sessions to the shared list/revoke handlers.
+type sessionOwner struct {
+	notFound   string
+	targetType string
+	exists     func(ctx context.Context, tx pgx.Tx, id string) error
+	list       func(ctx context.Context, tx pgx.Tx, id string, now time.Time) ([]models.DeviceSession, error)
+	revoke     func(ctx context.Context, tx pgx.Tx, ownerID string, id string) error
+	revokeAll  func(ctx context.Context, tx pgx.Tx, ownerID string) (int64, error)
+	action     string
+}
+
+func (h *Handler) staffSessionOwner() sessionOwner {
+	return sessionOwner{
+		notFound:   "staff not found",
+		targetType: audit.TargetStaff,
+		exists: func(ctx context.Context, tx pgx.Tx, id string) error {
+			_, err := h.Repo.GetStaffByID(ctx, tx, id)
+			return err
+		},
+		list:      h.Repo.ListStaffSessions,
+		revoke:    h.Repo.DeleteStaffSession,
+		revokeAll: h.Repo.DeleteStaffSessionsByStaff,
+		action:    audit.ActionStaffSessionsRevoke,
+	}
+}
+
+func (h *Handler) boothSessionOwner() sessionOwner {
+	return sessionOwner{
+		notFound:   "activity not found",
+		targetType: audit.TargetActivity,
+		exists: func(ctx context.Context, tx pgx.Tx, id string) error {
+			_, err := h.Repo.GetActivityByID(ctx, tx, id)
+			return err
+		},
+		list:      h.Repo.ListBoothSessions,
+		revoke:    h.Repo.DeleteBoothSession,
+		revokeAll: h.Repo.DeleteBoothSessionsByActivity,
+		action:    audit.ActionActivitySessionsRevoke,
+	}
+}
+
+// ListStaffSessions handles GET /admin/staffs/{id}/sessions.
+// @Summary      列出工作人員登入 session
+// @Description  需要 event.manage 權限。回傳該工作人員尚未過期的登入 session（裝置名稱、建立時間、最後使用時間、過期時間），最近使用的在前。
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Staff ID"
+// @Success      200  {array}   models.DeviceSession
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "staff not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/staffs/{id}/sessions [get]
+func (h *Handler) ListStaffSessions(w http.ResponseWriter, r *http.Request) {
+	h.listSessions(w, r, h.staffSessionOwner())
+}
+
+// RevokeStaffSessions handles DELETE /admin/staffs/{id}/sessions.
+// @Summary      撤銷工作人員所有 session
+// @Description  需要 event.manage 權限。讓該工作人員所有裝置登出，工作人員 token 不變，仍可重新登入。
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Staff ID"
+// @Success      200  {object}  revokeSessionsResponse
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "staff not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/staffs/{id}/sessions [delete]
+func (h *Handler) RevokeStaffSessions(w http.ResponseWriter, r *http.Request) {
+	h.revokeSessions(w, r, h.staffSessionOwner())
+}
+
+// RevokeStaffSession handles DELETE /admin/staffs/{id}/sessions/{sessionID}.
+// @Summary      撤銷工作人員單一 session
+// @Description  需要 event.manage 權限。讓該工作人員的一個裝置登出。
+// @Tags         admin
+// @Param        id         path  string  true  "Staff ID"
+// @Param        sessionID  path  string  true  "Session ID"
+// @Success      204  "No Content"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "session not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/staffs/{id}/sessions/{sessionID} [delete]
+func (h *Handler) RevokeStaffSession(w http.ResponseWriter, r *http.Request) {
+	h.revokeSession(w, r, h.staffSessionOwner())
+}
+
+// ListActivitySessions handles GET /admin/activities/{id}/sessions.
+// @Summary      列出攤位登入 session
+// @Description  需要 event.manage 權限。回傳該活動尚未過期的攤位登入 session（裝置名稱、建立時間、最後使用時間、過期時間），最近使用的在前。
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Activity ID"
+// @Success      200  {array}   models.DeviceSession
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "activity not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities/{id}/sessions [get]
+func (h *Handler) ListActivitySessions(w http.ResponseWriter, r *http.Request) {
+	h.listSessions(w, r, h.boothSessionOwner())
+}
+
+// RevokeActivitySessions handles DELETE /admin/activities/{id}/sessions.
+// @Summary      撤銷攤位所有 session
+// @Description  需要 event.manage 權限。讓該攤位所有裝置登出，活動 token 不變，仍可重新登入；若 token 外流請改用 token-rotations。
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Activity ID"
+// @Success      200  {object}  revokeSessionsResponse
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "activity not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities/{id}/sessions [delete]
+func (h *Handler) RevokeActivitySessions(w http.ResponseWriter, r *http.Request) {
+	h.revokeSessions(w, r, h.boothSessionOwner())
+}
+
+// RevokeActivitySession handles DELETE /admin/activities/{id}/sessions/{sessionID}.
+// @Summary      撤銷攤位單一 session
+// @Description  需要 event.manage 權限。讓該攤位的一個裝置登出。
+// @Tags         admin
+// @Param        id         path  string  true  "Activity ID"
+// @Param        sessionID  path  string  true  "Session ID"
+// @Success      204  "No Content"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "session not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/activities/{id}/sessions/{sessionID} [delete]
+func (h *Handler) RevokeActivitySession(w http.ResponseWriter, r *http.Request) {
+	h.revokeSession(w, r, h.boothSessionOwner())
+}
+
+func (h *Handler) listSessions(w http.ResponseWriter, r *http.Request, owner sessionOwner) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	id := chi.URLParam(r, "id")
+	if !h.requireSessionOwner(w, r, tx, owner, id) {
+		return
+	}
+	sessions, err := owner.list(r.Context(), tx, id, time.Now().UTC())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list sessions")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(sessions)
+}
+
+func (h *Handler) revokeSessions(w http.ResponseWriter, r *http.Request, owner sessionOwner) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	id := chi.URLParam(r, "id")
+	if !h.requireSessionOwner(w, r, tx, owner, id) {
+		return
+	}
+	revoked, err := owner.revokeAll(r.Context(), tx, id)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to revoke sessions")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     owner.action,
+		TargetType: owner.targetType,
+		TargetID:   id,
+		After:      revokeSessionsResponse{Revoked: revoked},
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(revokeSessionsResponse{Revoked: revoked})
+}
+
+func (h *Handler) revokeSession(w http.ResponseWriter, r *http.Request, owner sessionOwner) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	id := chi.URLParam(r, "id")
+	sessionID := chi.URLParam(r, "sessionID")
+	if err = owner.revoke(r.Context(), tx, id, sessionID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "session not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to revoke session")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     owner.action,
+		TargetType: owner.targetType,
+		TargetID:   id,
+		After:      map[string]string{"session_id": sessionID},
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) requireSessionOwner(
+	w http.ResponseWriter,
+	r *http.Request,
+	tx pgx.Tx,
+	owner sessionOwner,
+	id string,
+) bool {
+	if err := owner.exists(r.Context(), tx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, owner.notFound)
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to find session owner")
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/gameconfig"
+	"github.com/sitcon-tw/2026-game/pkg/loader"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+// maxGameConfigUploadBytes leaves room for both CSVs plus JSON escaping.
+const maxGameConfigUploadBytes = 3 * loader.MaxCSVBytes
+
+type gameConfigImportRequest struct {
+	// Source is url, file or upload.
+	Source models.GameConfigSource `json:"source"`
+	// The fields below are only for upload. LevelsCSV is required, plus SheetCSV (the default track)
+	// and/or Tracks.
+	LevelsCSV string                  `json:"levels_csv,omitempty"`
+	SheetCSV  string                  `json:"sheet_csv,omitempty"`
+	Tracks    []gameConfigTrackUpload `json:"tracks,omitempty"`
+}
+
+type gameConfigTrackUpload struct {
+	TrackID       string `json:"track_id"`
+	Tempo         int    `json:"tempo"`
+	TimeSignature string `json:"time_signature"`
+	SheetCSV      string `json:"sheet_csv"`
+}
+
+// GameConfigStatus handles GET /admin/game-config/status.
+// @Summary      取得關卡設定載入狀態
+// @Description  需要 event.manage 權限。回傳此實例目前提供的關卡設定版本與來源，以及無法使用資料庫版本時的錯誤原因（last_error）。公開的 GET /health 不含錯誤原因。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  gameconfig.Status
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Router       /admin/game-config/status [get]
+func (h *Handler) GameConfigStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(h.GameConfig.Status())
+}
+
+// ListGameConfigVersions handles GET /admin/game-config/versions.
+// @Summary      列出關卡設定版本
+// @Description  需要 event.manage 權限。依版本號由新到舊回傳所有關卡與譜面設定版本（來源、建立者、建立與啟用時間、各曲目的 tempo 與拍號），active 為目前提供給玩家的版本。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   models.GameConfigVersion
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/game-config/versions [get]
+func (h *Handler) ListGameConfigVersions(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	versions, err := h.Repo.ListGameConfigVersions(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list game config versions")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(versions)
+}
+
+// ImportGameConfig handles POST /admin/game-config/versions.
+// @Summary      匯入並啟用新的關卡設定
+// @Description  需要 event.manage 權限。source 為 url 時重新下載 LEVEL_CSV_URL 與 SHEET_MUSIC_CSV_URL，file 時讀取 LEVEL_CSV_FILE 與 SHEET_MUSIC_CSV_FILE，upload 時使用 body 的 levels_csv，以及 sheet_csv（default 曲目，tempo 120、4/4）和／或 tracks（具名曲目，各自帶 tempo 與拍號）。來源為 url／file 時另會讀取 SHEET_TRACKS_CSV_URL／SHEET_TRACKS_CSV_FILE 曲目清單。關卡 CSV 可加第五欄 track 指定曲目，未指定則用 default。譜面 CSV 可用 pitch,beat,duration,type 表頭描述拍點、長度與 tap／hold／chord（和弦音高以 + 連接），或沿用無表頭的單欄音名格式（每拍一個 tap）；url／file 來源的譜面若為 .mid／.midi 會自動轉成 CSV 再存。設定通過驗證後存成新版本並立即啟用，所有實例不需重啟即會切換；驗證失敗時目前版本不受影響。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      gameConfigImportRequest  true  "Config source"
+// @Success      201  {object}  models.GameConfigVersion
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid game config"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      502  {object}  res.ErrorResponse "game config source unavailable"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/game-config/versions [post]
+func (h *Handler) ImportGameConfig(w http.ResponseWriter, r *http.Request) {
+	var req gameConfigImportRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGameConfigUploadBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+	tracks, err := uploadedTracks(req)
+	if err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	admin, ok := middleware.AdminFromContext(r.Context())
+	if !ok || admin == nil {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("unauthorized"), "unauthorized")
+		return
+	}
+	version, err := h.GameConfig.Import(
+		r.Context(), req.Source, []byte(req.LevelsCSV), tracks, admin.Username)
+	if err != nil {
+		respondGameConfigError(w, r, err)
+		return
+	}
+	h.recordCommitted(r, audit.Entry{
+		Action:     audit.ActionGameConfigImport,
+		TargetType: audit.TargetGameConfig,
+		TargetID:   strconv.Itoa(version.Version),
+		After:      version,
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(version)
+}
+
+// ActivateGameConfigVersion handles POST /admin/game-config/versions/{version}/activation.
+// @Summary      切換關卡設定版本
+// @Description  需要 event.manage 權限。重新啟用既有版本（例如回滾有問題的匯入），所有實例不需重啟即會切換。
+// @Tags         admin
+// @Produce      json
+// @Param        version  path      int  true  "Config version"
+// @Success      200  {object}  models.GameConfigVersion
+// @Failure      400  {object}  res.ErrorResponse "invalid version | invalid game config"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      403  {object}  res.ErrorResponse "forbidden"
+// @Failure      404  {object}  res.ErrorResponse "game config version not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/game-config/versions/{version}/activation [post]
+func (h *Handler) ActivateGameConfigVersion(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || number <= 0 {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid version")
+		return
+	}
+
+	version, err := h.GameConfig.Activate(r.Context(), number)
+	if err != nil {
+		respondGameConfigError(w, r, err)
+		return
+	}
+	h.recordCommitted(r, audit.Entry{
+		Action:     audit.ActionGameConfigActivate,
+		TargetType: audit.TargetGameConfig,
+		TargetID:   strconv.Itoa(version.Version),
+		After:      version,
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(version)
+}
+
+// uploadedTracks checks that CSV is given exactly when source is upload and collects the tracks.
+func uploadedTracks(req gameConfigImportRequest) ([]models.GameConfigTrack, error) {
+	hasSheets := req.SheetCSV != "" || len(req.Tracks) > 0
+	if req.Source != models.GameConfigSourceUpload {
+		if req.LevelsCSV != "" || hasSheets {
+			return nil, errors.New("levels_csv, sheet_csv and tracks are only allowed for upload")
+		}
+		return nil, nil
+	}
+	if req.LevelsCSV == "" || !hasSheets {
+		return nil, errors.New("upload requires levels_csv and sheet_csv or tracks")
+	}
+
+	tracks := make([]models.GameConfigTrack, 0, len(req.Tracks)+1)
+	if req.SheetCSV != "" {
+		tracks = append(tracks, models.GameConfigTrack{
+			TrackID:       models.DefaultTrackID,
+			Tempo:         loader.DefaultTempo,
+			TimeSignature: loader.DefaultTimeSignature,
+			SheetCSV:      req.SheetCSV,
+		})
+	}
+	for _, t := range req.Tracks {
+		tracks = append(tracks, models.GameConfigTrack(t))
+	}
+	return tracks, nil
+}
+
+func respondGameConfigError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, gameconfig.ErrInvalidConfig):
+		res.Fail(w, r, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, gameconfig.ErrSourceUnavailable):
+		res.Fail(w, r, http.StatusBadGateway, err, err.Error())
+	case errors.Is(err, repository.ErrNotFound):
+		res.Fail(w, r, http.StatusNotFound, err, "game config version not found")
+	default:
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to update game config")
+	}
+}
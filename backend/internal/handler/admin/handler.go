@@ -1,20 +1,49 @@
 package admin
 
 import (
+	"net/http"
+
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/couponrules"
+	"github.com/sitcon-tw/2026-game/internal/service/couponsettlement"
+	"github.com/sitcon-tw/2026-game/internal/service/gameconfig"
+	"github.com/sitcon-tw/2026-game/internal/service/scheduler"
 	"go.uber.org/zap"
 )
 
 // Handler handles admin-only requests.
 type Handler struct {
-	Repo   repository.Repository
-	Logger *zap.Logger
+	Repo       repository.Repository
+	Logger     *zap.Logger
+	Settlement *couponsettlement.Service
+	Scheduler  *scheduler.Scheduler
+	Coupons    *couponrules.Engine
+	GameConfig *gameconfig.Service
 }
 
 // New wires required dependencies for admin handler.
-func New(repo repository.Repository, logger *zap.Logger) *Handler {
+func New(
+	repo repository.Repository,
+	logger *zap.Logger,
+	settlement *couponsettlement.Service,
+	sched *scheduler.Scheduler,
+	gameConfig *gameconfig.Service,
+) *Handler {
 	return &Handler{
-		Repo:   repo,
-		Logger: logger,
+		Repo:       repo,
+		Logger:     logger,
+		Settlement: settlement,
+		Scheduler:  sched,
+		Coupons:    couponrules.New(repo),
+		GameConfig: gameConfig,
+	}
+}
+
+// recordCommitted audits an action whose change a service has already committed. The change
+// cannot be undone at this point, so a failed audit write is logged instead of failing the request.
+func (h *Handler) recordCommitted(r *http.Request, e audit.Entry) {
+	if err := audit.RecordSeparately(r.Context(), h.Repo, e); err != nil {
+		h.Logger.Error("failed to write audit event", zap.String("action", e.Action), zap.Error(err))
 	}
 }
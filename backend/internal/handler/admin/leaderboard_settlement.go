@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/couponsettlement"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+const settlementHistoryLimit = 20
+
+type leaderboardBoardRequest struct {
+	Board string `json:"board"`
+}
+
+// PreviewLeaderboardSettlement handles GET /admin/leaderboard/settlements/preview.
+// @Summary      預覽排行榜結算
+// @Description  需要 admin_token cookie。不發放任何折扣券，回傳目前若執行結算會被納入的使用者、名次、同分人數（tie_size）以及是否符合資格。tie_policy 為 include 時同名次全部納入（可能超過 rank_limit 人），為 exclude 時跨越 rank_limit 的同分群組全部排除。排行榜已凍結時以凍結當下的名次計算。board 預設為 LEADERBOARD_SETTLEMENT_BOARD。
+// @Tags         admin
+// @Produce      json
+// @Param        board  query     string  false  "排行榜" Enums(overall, activities, most_friends)
+// @Success      200  {object}  couponsettlement.Preview
+// @Failure      400  {object}  res.ErrorResponse "invalid board"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/leaderboard/settlements/preview [get]
+func (h *Handler) PreviewLeaderboardSettlement(w http.ResponseWriter, r *http.Request) {
+	board := settlementBoard(r.URL.Query().Get("board"))
+
+	preview, err := h.Settlement.Preview(r.Context(), board)
+	if err != nil {
+		respondSettlementError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(preview)
+}
+
+// SettleLeaderboard handles POST /admin/leaderboard/settlements.
+// @Summary      執行排行榜結算
+// @Description  需要 admin_token cookie。依照預覽的規則發放排行榜折扣券並保存結算紀錄。可重複執行：已持有折扣券的使用者會記錄為 already_issued，不會重複發放，也不會收回先前發出的券。同一時間只允許一個結算執行。body 可省略，board 預設為 LEADERBOARD_SETTLEMENT_BOARD。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      leaderboardBoardRequest  false  "Board to settle"
+// @Success      201  {object}  models.LeaderboardSettlement
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid board"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      409  {object}  res.ErrorResponse "settlement already in progress"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/leaderboard/settlements [post]
+func (h *Handler) SettleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeBoardRequest(r)
+	if err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+
+	settlement, err := h.Settlement.Settle(r.Context(), settlementBoard(req.Board), couponsettlement.TriggerAdmin)
+	if err != nil {
+		respondSettlementError(w, r, err)
+		return
+	}
+	h.recordCommitted(r, audit.Entry{
+		Action:     audit.ActionLeaderboardSettle,
+		TargetType: audit.TargetLeaderboard,
+		TargetID:   settlement.Board,
+		After:      settlement,
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(settlement)
+}
+
+// ListLeaderboardSettlements handles GET /admin/leaderboard/settlements.
+// @Summary      列出排行榜結算紀錄
+// @Description  需要 admin_token cookie。回傳最近 20 次結算紀錄（新到舊），包含每位使用者的名次與發放結果。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   models.LeaderboardSettlement
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/leaderboard/settlements [get]
+func (h *Handler) ListLeaderboardSettlements(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	settlements, err := h.Repo.ListLeaderboardSettlements(r.Context(), tx, settlementHistoryLimit)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list settlements")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	if settlements == nil {
+		settlements = []models.LeaderboardSettlement{}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(settlements)
+}
+
+// FreezeLeaderboard handles POST /admin/leaderboard/freeze.
+// @Summary      凍結排行榜
+// @Description  需要 admin_token cookie。保存目前的名次，之後排行榜與結算都以凍結當下的名次為準，玩家仍可繼續遊玩。COUPON_STOP_TIME 到達時也會自動凍結。body 可省略，board 預設為 LEADERBOARD_SETTLEMENT_BOARD。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      leaderboardBoardRequest  false  "Board to freeze"
+// @Success      201  {object}  models.LeaderboardFreeze
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | invalid board"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      409  {object}  res.ErrorResponse "leaderboard already frozen"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/leaderboard/freeze [post]
+func (h *Handler) FreezeLeaderboard(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeBoardRequest(r)
+	if err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+
+	freeze, err := h.Settlement.Freeze(r.Context(), settlementBoard(req.Board), time.Now())
+	if err != nil {
+		respondSettlementError(w, r, err)
+		return
+	}
+	h.recordCommitted(r, audit.Entry{
+		Action:     audit.ActionLeaderboardFreeze,
+		TargetType: audit.TargetLeaderboard,
+		TargetID:   freeze.Board,
+		After:      freeze,
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(freeze)
+}
+
+// UnfreezeLeaderboard handles DELETE /admin/leaderboard/freeze.
+// @Summary      解除排行榜凍結
+// @Description  需要 admin_token cookie。刪除凍結的名次，排行榜回到即時名次。board 預設為 LEADERBOARD_SETTLEMENT_BOARD。
+// @Tags         admin
+// @Param        board  query     string  false  "排行榜" Enums(overall, activities, most_friends)
+// @Success      204
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "leaderboard is not frozen"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/leaderboard/freeze [delete]
+func (h *Handler) UnfreezeLeaderboard(w http.ResponseWriter, r *http.Request) {
+	board := settlementBoard(r.URL.Query().Get("board"))
+	if err := h.Settlement.Unfreeze(r.Context(), board); err != nil {
+		respondSettlementError(w, r, err)
+		return
+	}
+	h.recordCommitted(r, audit.Entry{
+		Action:     audit.ActionLeaderboardUnfreeze,
+		TargetType: audit.TargetLeaderboard,
+		TargetID:   string(board),
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func settlementBoard(name string) repository.Board {
+	if name == "" {
+		return couponsettlement.Board()
+	}
+	return repository.Board(name)
+}
+
+// decodeBoardRequest accepts an empty body so the configured board can be used by default.
+func decodeBoardRequest(r *http.Request) (leaderboardBoardRequest, error) {
+	var req leaderboardBoardRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return req, err
+	}
+	return req, nil
+}
+
+func respondSettlementError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repository.ErrUnknownBoard), errors.Is(err, couponsettlement.ErrScopedBoard):
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid board")
+	case errors.Is(err, couponsettlement.ErrSettlementInProgress):
+		res.Fail(w, r, http.StatusConflict, err, "settlement already in progress")
+	case errors.Is(err, repository.ErrAlreadyExists):
+		res.Fail(w, r, http.StatusConflict, err, "leaderboard already frozen")
+	case errors.Is(err, repository.ErrNotFound):
+		res.Fail(w, r, http.StatusNotFound, err, "leaderboard is not frozen")
+	default:
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to settle leaderboard")
+	}
+}
@@ -6,43 +6,116 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/service/adminaccounts"
 	"github.com/sitcon-tw/2026-game/pkg/helpers"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
-const adminSessionTTL = 30 * 24 * time.Hour
-
 type loginResponse struct {
-	Authenticated bool `json:"authenticated"`
+	Authenticated bool                     `json:"authenticated"`
+	Admin         *models.Admin            `json:"admin,omitempty"`
+	Permissions   []models.AdminPermission `json:"permissions,omitempty"`
+	ExpiresAt     *time.Time               `json:"expires_at,omitempty"`
 }
 
 // Login handles POST /admin/session.
-// Uses Authorization Bearer admin key and issues admin_token cookie.
+// Uses Authorization Bearer with the admin's personal login key and issues admin_token cookie.
 // @Summary      管理員登入
-// @Description  使用 Authorization Bearer ADMIN_KEY 登入，成功後會設定 admin_token cookie 供後續 admin API 使用。
+// @Description  使用 Authorization Bearer 個人 login key 登入，成功後會設定 admin_token cookie 供後續 admin API 使用。session 於 ADMIN_SESSION_TTL 後過期，需重新登入。回傳管理員身分、角色與可用權限。
 // @Tags         admin
 // @Produce      json
-// @Param        Authorization  header    string  true  "Bearer {admin_key}"
+// @Param        Authorization  header    string  true  "Bearer {login_key}"
 // @Success      200            {object}  loginResponse
 // @Failure      400            {object}  res.ErrorResponse "missing token"
 // @Failure      401            {object}  res.ErrorResponse "unauthorized"
 // @Failure      500            {object}  res.ErrorResponse
 // @Router       /admin/session [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
-	token := helpers.BearerToken(r.Header.Get("Authorization"))
-	if token == "" {
+	key := helpers.BearerToken(r.Header.Get("Authorization"))
+	if key == "" {
 		res.Fail(w, r, http.StatusBadRequest, errors.New("missing token"), "missing token")
 		return
 	}
-	if token != config.Env().AdminKey {
-		res.Fail(w, r, http.StatusUnauthorized, errors.New("invalid token"), "unauthorized")
+
+	admin, token, expiresAt, err := adminaccounts.Login(r.Context(), h.Repo, key)
+	if err != nil {
+		if errors.Is(err, adminaccounts.ErrInvalidKey) {
+			res.Fail(w, r, http.StatusUnauthorized, err, "unauthorized")
+			return
+		}
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create admin session")
 		return
 	}
 
-	http.SetCookie(w, helpers.NewCookie("admin_token", token, adminSessionTTL))
+	http.SetCookie(w, helpers.NewCookie("admin_token", token, time.Until(expiresAt)))
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(loginResponse{Authenticated: true})
+	_ = json.NewEncoder(w).Encode(loginResponse{
+		Authenticated: true,
+		Admin:         admin,
+		Permissions:   admin.Role.Permissions(),
+		ExpiresAt:     &expiresAt,
+	})
+}
+
+// Session handles GET /admin/session.
+// @Summary      取得目前管理員
+// @Description  需要 admin_token cookie。回傳目前登入的管理員身分、角色與可用權限。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  loginResponse
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Router       /admin/session [get]
+func (h *Handler) Session(w http.ResponseWriter, r *http.Request) {
+	admin, ok := middleware.AdminFromContext(r.Context())
+	if !ok || admin == nil {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("unauthorized"), "unauthorized")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(loginResponse{
+		Authenticated: true,
+		Admin:         admin,
+		Permissions:   admin.Role.Permissions(),
+	})
+}
+
+// Logout handles DELETE /admin/session.
+// @Summary      管理員登出
+// @Description  需要 admin_token cookie。撤銷目前的 admin session 並清除 cookie。
+// @Tags         admin
+// @Success      204  "No Content"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/session [delete]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie("admin_token")
+	if err != nil || cookie.Value == "" {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("missing admin session"), "unauthorized")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	if err = h.Repo.DeleteAdminSession(r.Context(), tx, helpers.HashSecret(cookie.Value)); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to revoke admin session")
+		return
+	}
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	http.SetCookie(w, helpers.NewCookie("admin_token", "", -time.Hour))
+	w.WriteHeader(http.StatusNoContent)
 }
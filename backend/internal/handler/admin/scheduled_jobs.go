@@ -0,0 +1,175 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/scheduler"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+const scheduledJobRunLimit = 50
+
+// ScheduledJobEntry is a persisted job plus whether this instance knows how to run it.
+type ScheduledJobEntry struct {
+	models.ScheduledJob
+
+	Registered bool `json:"registered"`
+}
+
+// ListScheduledJobs handles GET /admin/jobs.
+// @Summary      列出排程工作
+// @Description  需要 admin_token cookie。回傳所有排程工作的狀態（pending、running、completed、failed）、排定的執行時間（run_at）、執行中的租約到期時間（lease_until，租約過期的工作會由其他伺服器接手）、重試次數與最後錯誤。registered 表示目前這台伺服器是否能執行該工作。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   ScheduledJobEntry
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/jobs [get]
+func (h *Handler) ListScheduledJobs(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	jobs, err := h.Repo.ListScheduledJobs(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list jobs")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	registered := make(map[string]bool)
+	for _, name := range h.Scheduler.Names() {
+		registered[name] = true
+	}
+	entries := make([]ScheduledJobEntry, 0, len(jobs))
+	for _, job := range jobs {
+		entries = append(entries, ScheduledJobEntry{ScheduledJob: job, Registered: registered[job.Name]})
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+// ListScheduledJobRuns handles GET /admin/jobs/{name}/runs.
+// @Summary      列出排程工作執行紀錄
+// @Description  需要 admin_token cookie。回傳指定工作最近 50 次執行紀錄（新到舊）。trigger 為 schedule（準時）、catch_up（伺服器停機後補跑）、admin（手動觸發）。
+// @Tags         admin
+// @Produce      json
+// @Param        name  path      string  true  "Job name"
+// @Success      200  {array}   models.ScheduledJobRun
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/jobs/{name}/runs [get]
+func (h *Handler) ListScheduledJobRuns(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	runs, err := h.Repo.ListScheduledJobRuns(r.Context(), tx, chi.URLParam(r, "name"), scheduledJobRunLimit)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list job runs")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	if runs == nil {
+		runs = []models.ScheduledJobRun{}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(runs)
+}
+
+// TriggerScheduledJob handles POST /admin/jobs/{name}/runs.
+// @Summary      立即執行排程工作
+// @Description  需要 admin_token cookie。立即執行一次指定工作並回傳執行紀錄；工作本身失敗時仍回傳 201，status 為 failed。一次性工作成功後標記為 completed；週期性工作成功後回到 pending（包含原本為 failed 的工作）並排在下一個週期時間，原本尚未到期的下次執行時間不變。執行失敗時工作狀態不變。工作正在執行中（status 為 running 且租約未過期）時回傳 409。
+// @Tags         admin
+// @Produce      json
+// @Param        name  path      string  true  "Job name"
+// @Success      201  {object}  models.ScheduledJobRun
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "job not found"
+// @Failure      409  {object}  res.ErrorResponse "scheduler is busy or job is running"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/jobs/{name}/runs [post]
+func (h *Handler) TriggerScheduledJob(w http.ResponseWriter, r *http.Request) {
+	run, err := h.Scheduler.Trigger(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		respondSchedulerError(w, r, err)
+		return
+	}
+	h.recordCommitted(r, audit.Entry{
+		Action:     audit.ActionScheduledJobTrigger,
+		TargetType: audit.TargetScheduledJob,
+		TargetID:   run.JobName,
+		After:      run,
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(run)
+}
+
+// RetryScheduledJob handles POST /admin/jobs/{name}/retry.
+// @Summary      重試失敗的排程工作
+// @Description  需要 admin_token cookie。將重試次數用盡而標記為 failed 的工作重設為 pending，下一次輪詢時執行。
+// @Tags         admin
+// @Produce      json
+// @Param        name  path      string  true  "Job name"
+// @Success      200  {object}  models.ScheduledJob
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "job not found"
+// @Failure      409  {object}  res.ErrorResponse "job has not failed"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/jobs/{name}/retry [post]
+func (h *Handler) RetryScheduledJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.Scheduler.Retry(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		respondSchedulerError(w, r, err)
+		return
+	}
+	h.recordCommitted(r, audit.Entry{
+		Action:     audit.ActionScheduledJobRetry,
+		TargetType: audit.TargetScheduledJob,
+		TargetID:   job.Name,
+		After:      job,
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+func respondSchedulerError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		res.Fail(w, r, http.StatusNotFound, err, "job not found")
+	case errors.Is(err, scheduler.ErrBusy):
+		res.Fail(w, r, http.StatusConflict, err, "scheduler is busy")
+	case errors.Is(err, scheduler.ErrJobNotFailed):
+		res.Fail(w, r, http.StatusConflict, err, "job has not failed")
+	default:
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to run job")
+	}
+}
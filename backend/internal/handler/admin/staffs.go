@@ -0,0 +1,271 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+type staffRequest struct {
+	Name string `json:"name"`
+}
+
+// ListStaffs handles GET /admin/staffs.
+// @Summary      列出工作人員
+// @Description  需要 admin_token cookie。回傳所有工作人員與登入 token。
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   models.Staff
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/staffs [get]
+func (h *Handler) ListStaffs(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	staffs, err := h.Repo.ListStaffs(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list staffs")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(staffs)
+}
+
+// CreateStaff handles POST /admin/staffs.
+// @Summary      建立工作人員
+// @Description  需要 admin_token cookie。登入 token 由系統產生。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      staffRequest  true  "Staff"
+// @Success      201  {object}  models.Staff
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | name is required"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/staffs [post]
+func (h *Handler) CreateStaff(w http.ResponseWriter, r *http.Request) {
+	name, ok := decodeStaffName(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	staff, err := h.Repo.CreateStaff(r.Context(), tx, name)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create staff")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionStaffCreate,
+		TargetType: audit.TargetStaff,
+		TargetID:   staff.ID,
+		After:      staff,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(staff)
+}
+
+// UpdateStaff handles PUT /admin/staffs/{id}.
+// @Summary      更新工作人員
+// @Description  需要 admin_token cookie。修改工作人員名稱，token 不變。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string        true  "Staff ID"
+// @Param        request  body      staffRequest  true  "Staff"
+// @Success      200  {object}  models.Staff
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | name is required"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "staff not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/staffs/{id} [put]
+func (h *Handler) UpdateStaff(w http.ResponseWriter, r *http.Request) {
+	name, ok := decodeStaffName(w, r)
+	if !ok {
+		return
+	}
+	h.writeStaff(w, r, audit.ActionStaffUpdate, func(ctx context.Context, tx pgx.Tx, id string) (*models.Staff, error) {
+		return h.Repo.UpdateStaffName(ctx, tx, id, name)
+	})
+}
+
+// RotateStaffToken handles POST /admin/staffs/{id}/token-rotations.
+// @Summary      重新產生工作人員 token
+// @Description  需要 admin_token cookie。產生新的登入 token，使用舊 token 的工作人員會被登出。
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "Staff ID"
+// @Success      200  {object}  models.Staff
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "staff not found"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/staffs/{id}/token-rotations [post]
+func (h *Handler) RotateStaffToken(w http.ResponseWriter, r *http.Request) {
+	h.writeStaff(w, r, audit.ActionStaffTokenRotate, h.Repo.RotateStaffToken)
+}
+
+// DeleteStaff handles DELETE /admin/staffs/{id}.
+// @Summary      刪除工作人員
+// @Description  需要 admin_token cookie。已核銷或發放過折扣券的工作人員不能刪除（紀錄需保留），請改用 token rotation 停用。
+// @Tags         admin
+// @Param        id   path      string  true  "Staff ID"
+// @Success      204
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "staff not found"
+// @Failure      409  {object}  res.ErrorResponse "staff has coupon records"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /admin/staffs/{id} [delete]
+func (h *Handler) DeleteStaff(w http.ResponseWriter, r *http.Request) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	id := chi.URLParam(r, "id")
+	before, err := h.Repo.GetStaffByID(r.Context(), tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "staff not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to find staff")
+		}
+		return
+	}
+
+	if err = h.Repo.DeleteStaff(r.Context(), tx, id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			res.Fail(w, r, http.StatusNotFound, err, "staff not found")
+		case errors.Is(err, repository.ErrInUse):
+			res.Fail(w, r, http.StatusConflict, err, "staff has coupon records")
+		default:
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to delete staff")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionStaffDelete,
+		TargetType: audit.TargetStaff,
+		TargetID:   id,
+		Before:     before,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeStaff(
+	w http.ResponseWriter,
+	r *http.Request,
+	action string,
+	write func(ctx context.Context, tx pgx.Tx, id string) (*models.Staff, error),
+) {
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	id := chi.URLParam(r, "id")
+	before, err := h.Repo.GetStaffByID(r.Context(), tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "staff not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to find staff")
+		}
+		return
+	}
+
+	staff, err := write(r.Context(), tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "staff not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to update staff")
+		}
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     action,
+		TargetType: audit.TargetStaff,
+		TargetID:   staff.ID,
+		Before:     before,
+		After:      staff,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(staff)
+}
+
+func decodeStaffName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req staffRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return "", false
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		res.Fail(w, r, http.StatusBadRequest, errMissingName, errMissingName.Error())
+		return "", false
+	}
+	return name, true
+}
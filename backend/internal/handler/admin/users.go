@@ -5,7 +5,9 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
@@ -14,14 +16,30 @@ const (
 	maxSearchLimit     = 100
 )
 
+// adminUser is a user as admins see it. It leaves out coupon_token, which redeems the user's
+// coupons and must not reach admins that only hold the read permission.
+//
+//nolint:golines // keep struct tags aligned
+type adminUser struct {
+	ID           string    `json:"id"`
+	Nickname     string    `json:"nickname"`
+	Avatar       *string   `json:"avatar,omitempty"`
+	Group        *string   `json:"group,omitempty"`
+	UnlockLevel  int       `json:"unlock_level"`
+	CurrentLevel int       `json:"current_level"`
+	LastPassTime time.Time `json:"last_pass_time"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
 // SearchUsers handles GET /admin/users?q=keyword&limit=20.
 // @Summary      搜尋使用者
-// @Description  需要 admin_token cookie。使用 nickname 做全文/模糊搜尋並回傳使用者列表。
+// @Description  需要 admin_token cookie。使用 nickname 做全文/模糊搜尋並回傳使用者列表。回傳內容不含 coupon_token，只有 read 權限的管理員也無法取得可兌換折扣券的憑證。
 // @Tags         admin
 // @Produce      json
 // @Param        q            query     string  true   "Search keyword"
 // @Param        limit        query     int     false  "Result limit (default 20, max 100)"
-// @Success      200          {array}   models.User
+// @Success      200          {array}   adminUser
 // @Failure      400          {object}  res.ErrorResponse "missing q | invalid limit"
 // @Failure      401          {object}  res.ErrorResponse "unauthorized"
 // @Failure      500          {object}  res.ErrorResponse
@@ -64,7 +82,26 @@ func (h *Handler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resp := make([]adminUser, 0, len(users))
+	for i := range users {
+		resp = append(resp, toAdminUser(&users[i]))
+	}
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(users)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toAdminUser(u *models.User) adminUser {
+	return adminUser{
+		ID:           u.ID,
+		Nickname:     u.Nickname,
+		Avatar:       u.Avatar,
+		Group:        u.Group,
+		UnlockLevel:  u.UnlockLevel,
+		CurrentLevel: u.CurrentLevel,
+		LastPassTime: u.LastPassTime,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+	}
 }
@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/redemption"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+// VoidRedemption handles POST /admin/coupon-histories/{id}/voids.
+// @Summary      作廢任一核銷紀錄
+// @Description  需要 admin_token cookie。作廢任一工作人員的核銷紀錄，該次核銷的折扣券會恢復為未使用。原核銷紀錄會保留並標記為已作廢（actor 為 admin）。每筆核銷只能作廢一次。
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                 true  "Coupon history ID (UUID)"
+// @Param        request  body      redemption.VoidBody    true  "Void reason"
+// @Success      201      {object}  redemption.VoidResponse
+// @Failure      400      {object}  res.ErrorResponse "invalid request body | reason is required"
+// @Failure      401      {object}  res.ErrorResponse "unauthorized"
+// @Failure      404      {object}  res.ErrorResponse "redemption not found"
+// @Failure      409      {object}  res.ErrorResponse "redemption already voided"
+// @Failure      500      {object}  res.ErrorResponse
+// @Router       /admin/coupon-histories/{id}/voids [post]
+func (h *Handler) VoidRedemption(w http.ResponseWriter, r *http.Request) {
+	var req redemption.VoidBody
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	void, coupons, err := redemption.Void(r.Context(), h.Repo, tx, redemption.VoidRequest{
+		HistoryID: chi.URLParam(r, "id"),
+		Actor:     models.VoidActorAdmin,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		status, msg := redemption.VoidErrorStatus(err)
+		res.Fail(w, r, status, err, msg)
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionRedemptionVoid,
+		TargetType: audit.TargetCouponHistory,
+		TargetID:   void.HistoryID,
+		After:      redemption.NewVoidResponse(void, coupons),
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(redemption.NewVoidResponse(void, coupons))
+}
@@ -3,16 +3,35 @@ package announcements
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
+// announcementResponse is an announcement resolved for the caller's locale.
+//
+//nolint:golines // keep struct tags aligned
+type announcementResponse struct {
+	ID          string                      `json:"id"`
+	Title       string                      `json:"title"`
+	Content     string                      `json:"content"`
+	Severity    models.AnnouncementSeverity `json:"severity"`
+	Pinned      bool                        `json:"pinned"`
+	PublishedAt time.Time                   `json:"published_at"`
+	ExpireAt    *time.Time                  `json:"expire_at,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+}
+
 // List handles GET /announcements.
 // @Summary      取得公告列表
-// @Description  取得最新公告，依 created_at 由新到舊排序。不需要登入。
+// @Description  取得目前發布中的公告，置頂的在前，其餘依發布時間由新到舊排序。不需要登入；未登入只會看到給所有人的公告，登入後另外包含給自己 group 或等級門檻的公告。title、content 依 lang 參數（未提供時用 Accept-Language）選擇語系，找不到翻譯時使用預設內容。
 // @Tags         announcements
 // @Produce      json
-// @Success      200  {array}   models.Announcement
+// @Param        lang  query     string  false  "Locale, e.g. en or zh-TW"
+// @Success      200  {array}   announcementResponse
 // @Failure      500  {object}  res.ErrorResponse
 // @Router       /announcements [get]
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
@@ -35,7 +54,38 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	user, _ := middleware.UserFromContext(r.Context())
+	locale := requestLocale(r)
+	now := time.Now().UTC()
+	resp := make([]announcementResponse, 0, len(items))
+	for _, item := range items {
+		if !item.LiveAt(now) || !item.VisibleTo(user) {
+			continue
+		}
+		title, content := item.Localized(locale)
+		resp = append(resp, announcementResponse{
+			ID:          item.ID,
+			Title:       title,
+			Content:     content,
+			Severity:    item.Severity,
+			Pinned:      item.Pinned,
+			PublishedAt: item.PublishedAt(),
+			ExpireAt:    item.ExpireAt,
+			CreatedAt:   item.CreatedAt,
+		})
+	}
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(items)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// requestLocale returns the lang query parameter, or else the first Accept-Language tag.
+func requestLocale(r *http.Request) string {
+	if lang := strings.TrimSpace(r.URL.Query().Get("lang")); lang != "" {
+		return lang
+	}
+	first, _, _ := strings.Cut(r.Header.Get("Accept-Language"), ",")
+	tag, _, _ := strings.Cut(first, ";")
+	return strings.TrimSpace(tag)
 }
@@ -9,7 +9,11 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/couponrules"
+	"github.com/sitcon-tw/2026-game/internal/service/notifications"
+	"github.com/sitcon-tw/2026-game/internal/service/qrtoken"
 	"github.com/sitcon-tw/2026-game/pkg/config"
 	"github.com/sitcon-tw/2026-game/pkg/helpers"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
@@ -28,7 +32,7 @@ var (
 
 // AssignCouponByQRCode handles POST /discount-coupons/staff/scan-assignments.
 // @Summary      掃描使用者 QR code 發放折扣券（工作人員）
-// @Description  需要 staff_token cookie。透過使用者的一次性 QR code 發放固定折扣券（限時動態或貼文分享，discount_id 與金額由後端固定，不接受外部傳入），並防止同一 user 重複發放。
+// @Description  需要 staff_token cookie。透過使用者以 purpose=coupon_assignment 取得的一次性 QR code（每個 QR code 只能使用一次）發放固定折扣券（限時動態或貼文分享，discount_id 由後端固定，金額、有效期間與庫存依 coupon_rules 設定，不接受外部傳入），並防止同一 user 重複發放。
 // @Tags         discount
 // @Accept       json
 // @Produce      json
@@ -36,8 +40,9 @@ var (
 // @Success      201          {object}  models.DiscountCoupon
 // @Failure      400          {object}  res.ErrorResponse "invalid payload | invalid qr code"
 // @Failure      401          {object}  res.ErrorResponse "unauthorized"
-// @Failure      404          {object}  res.ErrorResponse "user not found"
-// @Failure      409          {object}  res.ErrorResponse "already issued by qr scan"
+// @Failure      403          {object}  res.ErrorResponse "coupon earning stopped | coupon rule not active"
+// @Failure      404          {object}  res.ErrorResponse "user not found | coupon rule not found"
+// @Failure      409          {object}  res.ErrorResponse "already issued by qr scan | coupon out of stock | qr code already used"
 // @Failure      500          {object}  res.ErrorResponse
 // @Router       /discount-coupons/staff/scan-assignments [post]
 func (h *Handler) AssignCouponByQRCode(w http.ResponseWriter, r *http.Request) {
@@ -75,6 +80,8 @@ func (h *Handler) AssignCouponByQRCode(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, errScanTargetUserNotFound):
 			res.Fail(w, r, http.StatusNotFound, err, "user not found")
+		case errors.Is(err, qrtoken.ErrTokenUsed):
+			res.Fail(w, r, http.StatusConflict, err, "qr code already used")
 		case errors.Is(err, errScanLookupFailed):
 			res.Fail(w, r, http.StatusInternalServerError, err, "failed to verify qr code")
 		default:
@@ -83,6 +90,20 @@ func (h *Handler) AssignCouponByQRCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rule, err := h.Coupons.Rule(r.Context(), tx, config.DiscountIDSitconSNSCoupon)
+	if err != nil {
+		if errors.Is(err, couponrules.ErrRuleNotFound) {
+			res.Fail(w, r, http.StatusNotFound, err, "coupon rule not found")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to load coupon rule")
+		}
+		return
+	}
+	if !rule.ActiveAt(time.Now().UTC()) {
+		res.Fail(w, r, http.StatusForbidden, errors.New("coupon rule not active"), "coupon rule not active")
+		return
+	}
+
 	marked, err := h.Repo.TryMarkStaffScanCouponIssued(r.Context(), tx, userID, config.DiscountIDSitconSNSCoupon, staff.ID)
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to mark staff scan issuance")
@@ -93,13 +114,35 @@ func (h *Handler) AssignCouponByQRCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.Coupons.Reserve(r.Context(), tx, rule.ID, rule.Amount); err != nil {
+		if errors.Is(err, couponrules.ErrOutOfStock) {
+			res.Fail(w, r, http.StatusConflict, err, "coupon out of stock")
+		} else {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to reserve coupon stock")
+		}
+		return
+	}
+
 	coupon, err := h.Repo.InsertDiscountCouponForUser(
-		r.Context(), tx, userID, config.SitconSNSCouponPrice, config.DiscountIDSitconSNSCoupon,
+		r.Context(), tx, userID, rule.Amount, rule.ID,
 	)
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to assign coupon")
 		return
 	}
+	if err = notifications.CouponIssued(r.Context(), h.Repo, tx, coupon, models.CouponSourceStaffScan); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to notify user")
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionCouponScanAssign,
+		TargetType: audit.TargetCoupon,
+		TargetID:   coupon.ID,
+		After:      coupon,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
 
 	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
@@ -170,36 +213,16 @@ func (h *Handler) ListStaffScanHistory(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) resolveUserIDFromOneTimeQRCode(ctx context.Context, tx pgx.Tx, token string) (string, error) {
-	userID, err := helpers.VerifyAndExtractUserIDFromOneTimeQRToken(
-		token,
-		time.Now().UTC(),
-		func(targetUserID string) (string, error) {
-			targetUser, lookupErr := h.Repo.GetUserByID(ctx, tx, targetUserID)
-			if lookupErr != nil {
-				if errors.Is(lookupErr, repository.ErrNotFound) {
-					return "", errScanTargetUserNotFound
-				}
-				return "", fmt.Errorf("%w: %w", errScanLookupFailed, lookupErr)
-			}
-			return targetUser.QRCodeToken, nil
-		},
-	)
+	user, err := qrtoken.Consume(ctx, h.Repo, tx, token, helpers.QRPurposeCouponAssignment)
 	if err != nil {
-		if errors.Is(err, errScanTargetUserNotFound) {
-			return "", err
-		}
-		if errors.Is(err, errScanLookupFailed) {
-			return "", err
-		}
-		return "", errors.New("invalid one-time qr token")
-	}
-
-	if _, err = h.Repo.GetUserByID(ctx, tx, userID); err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
+		switch {
+		case errors.Is(err, qrtoken.ErrUserNotFound):
 			return "", errScanTargetUserNotFound
+		case errors.Is(err, qrtoken.ErrInvalidToken), errors.Is(err, qrtoken.ErrTokenUsed):
+			return "", err
+		default:
+			return "", fmt.Errorf("%w: %w", errScanLookupFailed, err)
 		}
-		return "", err
 	}
-
-	return userID, nil
+	return user.ID, nil
 }
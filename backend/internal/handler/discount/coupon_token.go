@@ -0,0 +1,56 @@
+package discount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
+)
+
+var (
+	errCouponTokenExpired       = errors.New("coupon token expired or invalid")
+	errStaticCouponTokenRetired = errors.New("static coupon token is no longer accepted")
+)
+
+// resolveCouponTokenUser returns the user a scanned coupon QR token belongs to.
+// Signed qrc1 tokens are always accepted; the legacy static coupon_token only
+// while config.IsStaticCouponTokenAccepted allows it.
+func (h *Handler) resolveCouponTokenUser(ctx context.Context, tx pgx.Tx, token string) (*models.User, error) {
+	now := time.Now().UTC()
+	if !helpers.IsCouponQRToken(token) {
+		if !config.IsStaticCouponTokenAccepted(now) {
+			return nil, errStaticCouponTokenRetired
+		}
+		user, err := h.Repo.GetUserByCouponToken(ctx, tx, token)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errInvalidCouponToken
+		}
+		return user, err
+	}
+
+	var user *models.User
+	_, err := helpers.VerifyAndExtractUserIDFromCouponQRToken(token, now, func(userID string) (string, error) {
+		u, lookupErr := h.Repo.GetUserByID(ctx, tx, userID)
+		if lookupErr != nil {
+			if errors.Is(lookupErr, repository.ErrNotFound) {
+				return "", errInvalidCouponToken
+			}
+			return "", fmt.Errorf("%w: %w", errScanLookupFailed, lookupErr)
+		}
+		user = u
+		return u.QRCodeToken, nil
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidCouponToken) || errors.Is(err, errScanLookupFailed) {
+			return nil, err
+		}
+		return nil, errCouponTokenExpired
+	}
+	return user, nil
+}
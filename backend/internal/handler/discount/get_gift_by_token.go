@@ -5,7 +5,9 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/notifications"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
@@ -74,6 +76,10 @@ func (h *Handler) GetGiftCouponByToken(w http.ResponseWriter, r *http.Request) {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create user coupon")
 		return
 	}
+	if err = notifications.CouponIssued(r.Context(), h.Repo, tx, coupon, models.CouponSourceGift); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to notify user")
+		return
+	}
 
 	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
@@ -5,25 +5,28 @@ import (
 	"errors"
 	"net/http"
 
-	"github.com/sitcon-tw/2026-game/internal/repository"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
+// getUserCouponsRequest takes the same optional coupon_ids / max_amount as a redemption,
+// so staff can preview exactly what confirming would consume.
 type getUserCouponsRequest struct {
-	UserCouponToken string `json:"user_coupon_token"`
+	UserCouponToken string   `json:"user_coupon_token"`
+	CouponIDs       []string `json:"coupon_ids,omitempty"`
+	MaxAmount       *int     `json:"max_amount,omitempty"`
 }
 
 // GetUserCoupons handles POST /discount-coupons/staff/coupon-tokens/query.
 // Staff uses user's coupon token to inspect available coupons and total value.
 // @Summary      工作人員查詢某使用者可用折扣券
-// @Description  需要 staff_token cookie，帶 userCouponToken 查詢該使用者尚未使用的折扣券與總額
+// @Description  需要 staff_token cookie，帶 user_coupon_token（使用者 /users/me/coupon-qr 取得的 qrc1 限時 token；舊的固定 coupon_token 在 COUPON_STATIC_TOKEN_UNTIL 之前仍接受（非 dev 環境必須設定；dev 未設定則不限））查詢該使用者尚未使用的折扣券與總額。可帶與核銷相同的 coupon_ids 或 max_amount，回傳的 selected 與 selected_total 即為確認核銷時會使用的折扣券，供工作人員在確認前挑選。
 // @Tags         discount
 // @Accept       json
 // @Produce      json
 // @Param        request  body      getUserCouponsRequest  true  "User coupon token"
 // @Success      200  {object}  getUserCouponsResponse  ""
-// @Failure      400  {object}  res.ErrorResponse "missing token | invalid coupon token"
+// @Failure      400  {object}  res.ErrorResponse "missing token | invalid coupon token | coupon token expired | static coupon token retired | coupon not available | invalid max_amount"
 // @Failure      401  {object}  res.ErrorResponse "unauthorized staff"
 // @Failure      500  {object}  res.ErrorResponse
 // @Router       /discount-coupons/staff/coupon-tokens/query [post]
@@ -53,13 +56,9 @@ func (h *Handler) GetUserCoupons(w http.ResponseWriter, r *http.Request) {
 	}
 	defer h.Repo.DeferRollback(r.Context(), tx)
 
-	user, err := h.Repo.GetUserByCouponToken(r.Context(), tx, req.UserCouponToken)
+	user, err := h.resolveCouponTokenUser(r.Context(), tx, req.UserCouponToken)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			res.Fail(w, r, http.StatusBadRequest, errors.New("invalid coupon token"), "invalid coupon token")
-			return
-		}
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch user")
+		respondCouponSelectionError(w, r, err)
 		return
 	}
 
@@ -69,20 +68,29 @@ func (h *Handler) GetUserCoupons(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	total := 0
-	resp := getUserCouponsResponse{
-		Coupons: make([]couponItem, 0, len(coupons)),
+	selected, err := selectCoupons(coupons, couponSelection{CouponIDs: req.CouponIDs, MaxAmount: req.MaxAmount})
+	if err != nil {
+		respondCouponSelectionError(w, r, err)
+		return
+	}
+	selectedIDs := make(map[string]bool, len(selected))
+	for _, c := range selected {
+		selectedIDs[c.ID] = true
 	}
 
+	resp := getUserCouponsResponse{
+		Coupons:       make([]couponItem, 0, len(coupons)),
+		Total:         sumCoupons(coupons),
+		SelectedTotal: sumCoupons(selected),
+	}
 	for _, c := range coupons {
-		total += c.Price
 		resp.Coupons = append(resp.Coupons, couponItem{
 			ID:         c.ID,
 			DiscountID: c.DiscountID,
 			Price:      c.Price,
+			Selected:   selectedIDs[c.ID],
 		})
 	}
-	resp.Total = total
 
 	err = h.Repo.CommitTransaction(r.Context(), tx)
 	if err != nil {
@@ -96,6 +104,7 @@ func (h *Handler) GetUserCoupons(w http.ResponseWriter, r *http.Request) {
 }
 
 type getUserCouponsResponse struct {
-	Coupons []couponItem `json:"coupons"`
-	Total   int          `json:"total"`
+	Coupons       []couponItem `json:"coupons"`
+	Total         int          `json:"total"`
+	SelectedTotal int          `json:"selected_total"`
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
@@ -13,7 +14,7 @@ import (
 // ListStaffHistory handles GET /discount-coupons/staff/current/redemptions.
 // Returns redemption history for the authenticated staff.
 // @Summary      取得工作人員使用的折扣紀錄
-// @Description  需要 staff_token cookie，回傳該 staff 操作的折扣券使用紀錄
+// @Description  需要 staff_token cookie，回傳該 staff 操作的折扣券使用紀錄。已作廢的紀錄仍會列出並附上 void，net_total 為扣除作廢後的金額（已作廢為 0）。
 // @Tags         discount
 // @Produce      json
 // @Success      200  {object}  []historyItem
@@ -55,7 +56,9 @@ func (h *Handler) ListStaffHistory(w http.ResponseWriter, r *http.Request) {
 			Nickname: hst.Nickname,
 			StaffID:  hst.StaffID,
 			Total:    hst.Total,
+			NetTotal: hst.NetTotal(),
 			UsedAt:   hst.UsedAt,
+			Void:     hst.Void,
 		})
 	}
 
@@ -65,10 +68,12 @@ func (h *Handler) ListStaffHistory(w http.ResponseWriter, r *http.Request) {
 }
 
 type historyItem struct {
-	ID       string    `json:"id"`
-	UserID   string    `json:"user_id"`
-	Nickname string    `json:"nickname"`
-	StaffID  string    `json:"staff_id"`
-	Total    int       `json:"total"`
-	UsedAt   time.Time `json:"used_at"`
+	ID       string                    `json:"id"`
+	UserID   string                    `json:"user_id"`
+	Nickname string                    `json:"nickname"`
+	StaffID  string                    `json:"staff_id"`
+	Total    int                       `json:"total"`
+	NetTotal int                       `json:"net_total"`
+	UsedAt   time.Time                 `json:"used_at"`
+	Void     *models.CouponHistoryVoid `json:"void,omitempty"`
 }
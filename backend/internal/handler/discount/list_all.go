@@ -3,34 +3,36 @@ package discount
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
-	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
+//nolint:golines // keep struct tags aligned
 type couponRuleWithStatus struct {
-	ID          string `json:"id"`
-	PassLevel   int    `json:"pass_level"`
-	Amount      int    `json:"amount"`
-	IssuedQty   int    `json:"issued_qty"`
-	Description string `json:"description"`
+	ID          string                   `json:"id"`
+	Trigger     models.CouponRuleTrigger `json:"trigger"`
+	Threshold   int                      `json:"threshold"`
+	PassLevel   int                      `json:"pass_level"`
+	Amount      int                      `json:"amount"`
+	IssuedQty   int                      `json:"issued_qty"`
+	StockLimit  *int                     `json:"stock_limit"`
+	Remaining   *int                     `json:"remaining_stock"`
+	ValidFrom   *time.Time               `json:"valid_from"`
+	ValidUntil  *time.Time               `json:"valid_until"`
+	Description string                   `json:"description"`
 }
 
 // ListAllCoupons handles GET /discount-coupons/coupons.
 // @Summary      取得所有折扣券規則與發放狀態
-// @Description  公開回傳所有折扣券規則與目前發放數量。
+// @Description  公開回傳所有啟用中的折扣券規則與目前發放數量。trigger 為取得條件類型（level、activity_visits、activity、friends、leaderboard_rank、group_check_ins、manual），threshold 為對應門檻；pass_level 僅在 trigger 為 level 時有值，保留給舊版前端。issued_qty 為已發放到使用者的張數；remaining_stock 為依庫存上限與預算計算的剩餘可發放張數（已扣除未兌換的 gift coupon），null 表示不限量，0 表示已發完。
 // @Tags         discount
 // @Produce      json
 // @Success      200  {array}   couponRuleWithStatus
 // @Failure      500  {object}  res.ErrorResponse
 // @Router       /discount-coupons/coupons [get]
 func (h *Handler) ListAllCoupons(w http.ResponseWriter, r *http.Request) {
-	rules := config.GetCouponRules()
-	discountIDs := make([]string, 0, len(rules))
-	for _, rule := range rules {
-		discountIDs = append(discountIDs, rule.ID)
-	}
-
 	tx, err := h.Repo.StartTransaction(r.Context())
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
@@ -38,6 +40,22 @@ func (h *Handler) ListAllCoupons(w http.ResponseWriter, r *http.Request) {
 	}
 	defer h.Repo.DeferRollback(r.Context(), tx)
 
+	allRules, err := h.Repo.ListCouponRules(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list coupon rules")
+		return
+	}
+
+	rules := make([]models.CouponRule, 0, len(allRules))
+	discountIDs := make([]string, 0, len(allRules))
+	for _, rule := range allRules {
+		if !rule.Enabled {
+			continue
+		}
+		rules = append(rules, rule)
+		discountIDs = append(discountIDs, rule.ID)
+	}
+
 	counts, err := h.Repo.CountDiscountCouponsByDiscountIDs(r.Context(), tx, discountIDs)
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to count issued coupons")
@@ -52,12 +70,21 @@ func (h *Handler) ListAllCoupons(w http.ResponseWriter, r *http.Request) {
 
 	resp := make([]couponRuleWithStatus, 0, len(rules))
 	for _, rule := range rules {
-		issuedQty := counts[rule.ID]
+		passLevel := 0
+		if rule.Trigger == models.CouponTriggerLevel {
+			passLevel = rule.Threshold
+		}
 		resp = append(resp, couponRuleWithStatus{
 			ID:          rule.ID,
-			PassLevel:   rule.PassLevel,
+			Trigger:     rule.Trigger,
+			Threshold:   rule.Threshold,
+			PassLevel:   passLevel,
 			Amount:      rule.Amount,
-			IssuedQty:   issuedQty,
+			IssuedQty:   counts[rule.ID],
+			StockLimit:  rule.StockLimit,
+			Remaining:   rule.RemainingStock(),
+			ValidFrom:   rule.ValidFrom,
+			ValidUntil:  rule.ValidUntil,
 			Description: rule.Description,
 		})
 	}
@@ -0,0 +1,106 @@
+package discount
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+var (
+	errCouponNotAvailable = errors.New("coupon not available")
+	errInvalidMaxAmount   = errors.New("max_amount must be positive")
+)
+
+// couponSelection narrows which unused coupons a redemption consumes.
+// With neither field set every unused coupon is selected, matching the original behaviour.
+type couponSelection struct {
+	CouponIDs []string
+	MaxAmount *int
+}
+
+// selectCoupons applies the selection to a user's unused coupons. Coupons are never split, so with
+// MaxAmount the result is the subset whose total is the largest value not exceeding it; among equal
+// totals the one using fewer coupons wins, leaving more coupons for later purchases.
+func selectCoupons(coupons []models.DiscountCoupon, sel couponSelection) ([]models.DiscountCoupon, error) {
+	if sel.MaxAmount != nil && *sel.MaxAmount <= 0 {
+		return nil, errInvalidMaxAmount
+	}
+
+	candidates := coupons
+	if len(sel.CouponIDs) > 0 {
+		byID := make(map[string]models.DiscountCoupon, len(coupons))
+		for _, c := range coupons {
+			byID[c.ID] = c
+		}
+		candidates = make([]models.DiscountCoupon, 0, len(sel.CouponIDs))
+		seen := make(map[string]bool, len(sel.CouponIDs))
+		for _, id := range sel.CouponIDs {
+			c, ok := byID[id]
+			if !ok {
+				return nil, errCouponNotAvailable
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			candidates = append(candidates, c)
+		}
+	}
+
+	if sel.MaxAmount == nil {
+		return candidates, nil
+	}
+	return fitWithin(candidates, *sel.MaxAmount), nil
+}
+
+// fitWithin solves the bounded subset-sum over coupon prices. Prices are small NT$ amounts,
+// so a table indexed by total is cheap.
+func fitWithin(coupons []models.DiscountCoupon, limit int) []models.DiscountCoupon {
+	total := 0
+	for _, c := range coupons {
+		total += c.Price
+	}
+	if total <= limit {
+		return coupons
+	}
+
+	// best[t] is the smallest number of coupons reaching exactly t; -1 when unreachable.
+	// pick[i][t] records whether coupon i was used to reach t after considering coupons[:i+1].
+	best := make([]int, limit+1)
+	for t := 1; t <= limit; t++ {
+		best[t] = -1
+	}
+	pick := make([][]bool, len(coupons))
+	for i, c := range coupons {
+		pick[i] = make([]bool, limit+1)
+		if c.Price <= 0 {
+			continue
+		}
+		for t := limit; t >= c.Price; t-- {
+			prev := best[t-c.Price]
+			if prev < 0 {
+				continue
+			}
+			if best[t] < 0 || prev+1 < best[t] {
+				best[t] = prev + 1
+				pick[i][t] = true
+			}
+		}
+	}
+
+	target := limit
+	for target > 0 && best[target] < 0 {
+		target--
+	}
+
+	selected := []models.DiscountCoupon{}
+	for i := len(coupons) - 1; i >= 0 && target > 0; i-- {
+		if pick[i][target] {
+			selected = append(selected, coupons[i])
+			target -= coupons[i].Price
+		}
+	}
+	slices.Reverse(selected)
+	return selected
+}
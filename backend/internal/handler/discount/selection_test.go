@@ -0,0 +1,69 @@
+package discount //nolint:testpackage // tests need access to unexported coupon selection
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+func coupons(prices ...int) []models.DiscountCoupon {
+	out := make([]models.DiscountCoupon, len(prices))
+	for i, p := range prices {
+		out[i] = models.DiscountCoupon{ID: string(rune('a' + i)), Price: p}
+	}
+	return out
+}
+
+func sum(cs []models.DiscountCoupon) int {
+	total := 0
+	for _, c := range cs {
+		total += c.Price
+	}
+	return total
+}
+
+func TestSelectCouponsMaxAmount(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		prices    []int
+		limit     int
+		wantTotal int
+		wantCount int
+	}{
+		{name: "everything fits", prices: []int{25, 35}, limit: 100, wantTotal: 60, wantCount: 2},
+		{name: "best subset", prices: []int{50, 35, 25, 25}, limit: 60, wantTotal: 60, wantCount: 2},
+		{name: "fewer coupons on tie", prices: []int{25, 25, 50}, limit: 50, wantTotal: 50, wantCount: 1},
+		{name: "nothing fits", prices: []int{25, 35}, limit: 20, wantTotal: 0, wantCount: 0},
+	}
+
+	for _, tc := range cases {
+		limit := tc.limit
+		got, err := selectCoupons(coupons(tc.prices...), couponSelection{MaxAmount: &limit})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if sum(got) != tc.wantTotal || len(got) != tc.wantCount {
+			t.Fatalf("%s: expected total %d with %d coupons, got total %d with %d", tc.name, tc.wantTotal, tc.wantCount, sum(got), len(got))
+		}
+	}
+}
+
+func TestSelectCouponsByIDRejectsUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := selectCoupons(coupons(25, 35), couponSelection{CouponIDs: []string{"a", "z"}})
+	if !errors.Is(err, errCouponNotAvailable) {
+		t.Fatalf("expected errCouponNotAvailable, got %v", err)
+	}
+
+	got, err := selectCoupons(coupons(25, 35, 50), couponSelection{CouponIDs: []string{"c", "a", "c"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "c" || got[1].ID != "a" {
+		t.Fatalf("expected coupons c and a, got %+v", got)
+	}
+}
@@ -3,24 +3,32 @@ package discount
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/devicesessions"
 	"github.com/sitcon-tw/2026-game/pkg/helpers"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
 
+type staffLoginRequest struct {
+	DeviceLabel string `json:"device_label"`
+}
+
 // StaffLogin handles POST /discount-coupons/staff/session.
 // @Summary      工作人員登入
-// @Description  工作人員使用此 API 登入系統，成功後會在 cookie 設定 staff_token，之後就可以使用 cookie 來呼叫需要 staff 身分的 endpoint。
+// @Description  工作人員使用此 API 登入系統，成功後會在 cookie 設定 staff_token（一組新的 session，而非工作人員 token 本身），之後就可以使用 cookie 來呼叫需要 staff 身分的 endpoint。session 於 STAFF_SESSION_TTL 後過期，需重新登入；管理員可在後台查看並撤銷。body 可省略，device_label 用來辨識裝置，未提供時使用 User-Agent。
 // @Tags         discount
+// @Accept       json
 // @Produce      json
+// @Param        Authorization  header  string             true   "Bearer {token}"
+// @Param        request        body    staffLoginRequest  false  "Device label"
 // @Success      200  {object}  models.Staff
-// @Failure      400  {object}  res.ErrorResponse "missing token"
+// @Failure      400  {object}  res.ErrorResponse "missing token | invalid request body"
 // @Failure      401  {object}  res.ErrorResponse "unauthorized staff"
 // @Failure      500  {object}  res.ErrorResponse
-// @Param        Authorization  header  string  true  "Bearer {token}"
 // @Router       /discount-coupons/staff/session [post]
 func (h *Handler) StaffLogin(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -32,6 +40,14 @@ func (h *Handler) StaffLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var req staffLoginRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+
 	tx, err := h.Repo.StartTransaction(ctx)
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
@@ -53,15 +69,58 @@ func (h *Handler) StaffLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sessionToken, session, err := devicesessions.OpenStaff(
+		ctx, h.Repo, tx, staff.ID, devicesessions.DeviceLabel(req.DeviceLabel, r.UserAgent()),
+	)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create staff session")
+		return
+	}
+
 	err = h.Repo.CommitTransaction(ctx, tx)
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
 		return
 	}
 
-	http.SetCookie(w, helpers.NewCookie("staff_token", staff.Token, 30*24*time.Hour))
+	http.SetCookie(w, helpers.NewCookie("staff_token", sessionToken, time.Until(session.ExpiresAt)))
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(staff)
 }
+
+// StaffLogout handles DELETE /discount-coupons/staff/session.
+// @Summary      工作人員登出
+// @Description  撤銷目前 staff_token cookie 對應的 session 並清除 cookie。
+// @Tags         discount
+// @Success      204  "No Content"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /discount-coupons/staff/session [delete]
+func (h *Handler) StaffLogout(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie("staff_token")
+	if err != nil || cookie.Value == "" {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("missing staff session"), "unauthorized")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	if err = h.Repo.DeleteStaffSessionByHash(r.Context(), tx, helpers.HashSecret(cookie.Value)); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to revoke staff session")
+		return
+	}
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	http.SetCookie(w, helpers.NewCookie("staff_token", "", -time.Hour))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -11,14 +11,23 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 )
 
+// discountUsedRequest redeems coupon_ids, or as many coupons as fit in max_amount, or both combined.
+// With neither set every unused coupon is redeemed.
 type discountUsedRequest struct {
-	UserCouponToken string `json:"user_coupon_token"`
+	UserCouponToken string   `json:"user_coupon_token"`
+	CouponIDs       []string `json:"coupon_ids,omitempty"`
+	MaxAmount       *int     `json:"max_amount,omitempty"`
+}
+
+func (r discountUsedRequest) selection() couponSelection {
+	return couponSelection{CouponIDs: r.CouponIDs, MaxAmount: r.MaxAmount}
 }
 
 var (
@@ -28,14 +37,14 @@ var (
 
 // DiscountUsed handles POST /discount-coupons/staff/redemptions.
 // @Summary      工作人員掃 QR Code 來使用折扣券
-// @Description  用 QR Code 掃描器掃會眾的折價券，然後折價券就會被標記為已使用，同時返回這個折價券的詳細資訊。需已登入並持有 staff_token cookie。
+// @Description  用 QR Code 掃描器掃會眾的折價券，然後折價券就會被標記為已使用，同時返回這個折價券的詳細資訊。需已登入並持有 staff_token cookie。user_coupon_token 須為使用者 /users/me/coupon-qr 取得的 qrc1 限時 token，舊的固定 coupon_token 在 COUPON_STATIC_TOKEN_UNTIL 之前仍接受（非 dev 環境必須設定；dev 未設定則不限）。可帶 coupon_ids 只核銷指定的折扣券，或帶 max_amount 只核銷總額不超過消費金額的折扣券（折扣券不會被拆開使用，會選出最接近 max_amount 的組合）；兩者皆未帶時核銷全部未使用的折扣券。消費紀錄只記錄實際核銷的金額，remaining_total 為核銷後剩餘的折扣券總額。
 // @Tags         discount
 // @Accept       json
 // @Produce      json
 // @Param        request  body      discountUsedRequest  true  "Discount coupon token"
 // @Success      200  {object}  discountUsedResponse  ""
 // @Failure      500  {object}  res.ErrorResponse
-// @Failure      400  {object}  res.ErrorResponse "missing token | invalid coupon"
+// @Failure      400  {object}  res.ErrorResponse "missing token | invalid coupon token | coupon token expired | static coupon token retired | coupon not available | invalid max_amount"
 // @Failure      401  {object}  res.ErrorResponse "unauthorized staff"
 // @Router       /discount-coupons/staff/redemptions [post]
 func (h *Handler) DiscountUsed(w http.ResponseWriter, r *http.Request) {
@@ -58,20 +67,22 @@ func (h *Handler) DiscountUsed(w http.ResponseWriter, r *http.Request) {
 	}
 	defer h.Repo.DeferRollback(r.Context(), tx)
 
-	user, _, total, err := h.loadUserAndCoupons(r.Context(), tx, req.UserCouponToken)
+	user, unused, err := h.loadUserAndCoupons(r.Context(), tx, req.UserCouponToken)
 	if err != nil {
-		if errors.Is(err, errInvalidCouponToken) {
-			res.Fail(w, r, http.StatusBadRequest, err, "invalid coupon token")
-			return
-		}
-		if errors.Is(err, errNoAvailableCoupons) {
-			res.Fail(w, r, http.StatusBadRequest, err, "no available coupons")
-			return
-		}
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to load coupon context")
+		respondCouponSelectionError(w, r, err)
 		return
 	}
 
+	selected, err := selectCoupons(unused, req.selection())
+	if err == nil && len(selected) == 0 {
+		err = errNoAvailableCoupons
+	}
+	if err != nil {
+		respondCouponSelectionError(w, r, err)
+		return
+	}
+	total := sumCoupons(selected)
+
 	usedAt := time.Now().UTC()
 	history, err := h.createCouponHistory(r.Context(), tx, user.ID, staff.ID, total, usedAt)
 	if err != nil {
@@ -79,15 +90,25 @@ func (h *Handler) DiscountUsed(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedCoupons, err := h.markCouponsUsed(r.Context(), tx, user.ID, staff.ID, history.ID, usedAt)
+	updatedCoupons, err := h.markCouponsUsed(r.Context(), tx, user.ID, couponIDs(selected), staff.ID, history.ID, usedAt)
 	if err != nil {
-		if errors.Is(err, errNoAvailableCoupons) {
-			res.Fail(w, r, http.StatusBadRequest, err, "no available coupons")
+		if errors.Is(err, errNoAvailableCoupons) || errors.Is(err, errCouponNotAvailable) {
+			respondCouponSelectionError(w, r, err)
 			return
 		}
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to mark coupons used")
 		return
 	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionCouponRedeem,
+		TargetType: audit.TargetCouponHistory,
+		TargetID:   history.ID,
+		Before:     selected,
+		After:      updatedCoupons,
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
 
 	err = h.Repo.CommitTransaction(r.Context(), tx)
 	if err != nil {
@@ -96,6 +117,7 @@ func (h *Handler) DiscountUsed(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := buildDiscountUsedResponse(user, staff, updatedCoupons, total, usedAt)
+	resp.RemainingTotal = sumCoupons(unused) - total
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
@@ -117,17 +139,34 @@ func parseDiscountUsedRequest(r *http.Request) (discountUsedRequest, error) {
 	return req, nil
 }
 
+func respondCouponSelectionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, errInvalidCouponToken):
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid coupon token")
+	case errors.Is(err, errCouponTokenExpired):
+		res.Fail(w, r, http.StatusBadRequest, err, "coupon token expired")
+	case errors.Is(err, errStaticCouponTokenRetired):
+		res.Fail(w, r, http.StatusBadRequest, err, "static coupon token retired")
+	case errors.Is(err, errNoAvailableCoupons):
+		res.Fail(w, r, http.StatusBadRequest, err, "no available coupons")
+	case errors.Is(err, errCouponNotAvailable):
+		res.Fail(w, r, http.StatusBadRequest, err, "coupon not available")
+	case errors.Is(err, errInvalidMaxAmount):
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid max_amount")
+	default:
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to load coupon context")
+	}
+}
+
+// loadUserAndCoupons resolves the coupon token and locks the user's unused coupons.
 func (h *Handler) loadUserAndCoupons(
 	ctx context.Context,
 	tx pgx.Tx,
 	userCouponToken string,
-) (*models.User, []models.DiscountCoupon, int, error) {
-	user, err := h.Repo.GetUserByCouponToken(ctx, tx, userCouponToken)
+) (*models.User, []models.DiscountCoupon, error) {
+	user, err := h.resolveCouponTokenUser(ctx, tx, userCouponToken)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return nil, nil, 0, errInvalidCouponToken
-		}
-		return nil, nil, 0, err
+		return nil, nil, err
 	}
 
 	loadCtx, loadSpan := h.tracer.Start(ctx, "discount.redeem.load_unused")
@@ -137,20 +176,31 @@ func (h *Handler) loadUserAndCoupons(
 	if err != nil {
 		loadSpan.RecordError(err)
 		loadSpan.SetStatus(codes.Error, "list unused coupons failed")
-		return nil, nil, 0, err
+		return nil, nil, err
 	}
 
 	loadSpan.SetAttributes(attribute.Int("discount.coupons.count", len(coupons)))
 	if len(coupons) == 0 {
-		return nil, nil, 0, errNoAvailableCoupons
+		return nil, nil, errNoAvailableCoupons
 	}
 
+	return user, coupons, nil
+}
+
+func sumCoupons(coupons []models.DiscountCoupon) int {
 	total := 0
 	for _, c := range coupons {
 		total += c.Price
 	}
+	return total
+}
 
-	return user, coupons, total, nil
+func couponIDs(coupons []models.DiscountCoupon) []string {
+	ids := make([]string, len(coupons))
+	for i, c := range coupons {
+		ids[i] = c.ID
+	}
+	return ids
 }
 
 func (h *Handler) createCouponHistory(
@@ -188,6 +238,7 @@ func (h *Handler) markCouponsUsed(
 	ctx context.Context,
 	tx pgx.Tx,
 	userID string,
+	ids []string,
 	staffID string,
 	historyID string,
 	usedAt time.Time,
@@ -195,7 +246,7 @@ func (h *Handler) markCouponsUsed(
 	markCtx, markSpan := h.tracer.Start(ctx, "discount.redeem.mark_used")
 	defer markSpan.End()
 
-	updatedCoupons, err := h.Repo.MarkDiscountsUsedByUser(markCtx, tx, userID, staffID, historyID, usedAt)
+	updatedCoupons, err := h.Repo.MarkDiscountsUsedByIDs(markCtx, tx, userID, ids, staffID, historyID, usedAt)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			markSpan.SetStatus(codes.Error, "no coupons to mark")
@@ -207,6 +258,10 @@ func (h *Handler) markCouponsUsed(
 	}
 
 	markSpan.SetAttributes(attribute.Int("discount.marked.count", len(updatedCoupons)))
+	if len(updatedCoupons) != len(ids) {
+		markSpan.SetStatus(codes.Error, "selected coupon already used")
+		return nil, errCouponNotAvailable
+	}
 	return updatedCoupons, nil
 }
 
@@ -239,20 +294,23 @@ func buildDiscountUsedResponse(
 	return resp
 }
 
-// discountUsedResponse is returned after marking all of a user's coupons as used.
+// discountUsedResponse is returned after marking the selected coupons as used.
+// RemainingTotal is the value of the user's coupons still unused afterwards.
 type discountUsedResponse struct {
-	UserID      string       `json:"user_id"`
-	UserName    string       `json:"user_name"`
-	CouponToken string       `json:"coupon_token"`
-	Total       int          `json:"total"`
-	Count       int          `json:"count"`
-	UsedBy      string       `json:"used_by"`
-	UsedAt      time.Time    `json:"used_at"`
-	Coupons     []couponItem `json:"coupons"`
+	UserID         string       `json:"user_id"`
+	UserName       string       `json:"user_name"`
+	CouponToken    string       `json:"coupon_token"`
+	Total          int          `json:"total"`
+	Count          int          `json:"count"`
+	RemainingTotal int          `json:"remaining_total"`
+	UsedBy         string       `json:"used_by"`
+	UsedAt         time.Time    `json:"used_at"`
+	Coupons        []couponItem `json:"coupons"`
 }
 
 type couponItem struct {
 	ID         string `json:"id"`
 	DiscountID string `json:"discount_id"`
 	Price      int    `json:"price"`
+	Selected   bool   `json:"selected,omitempty"`
 }
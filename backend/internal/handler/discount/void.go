@@ -0,0 +1,122 @@
+package discount
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/service/audit"
+	"github.com/sitcon-tw/2026-game/internal/service/redemption"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+// VoidRedemption handles POST /discount-coupons/staff/redemptions/{id}/voids.
+// @Summary      作廢核銷紀錄（工作人員）
+// @Description  需要 staff_token cookie。作廢自己操作的一筆核銷紀錄，該次核銷的折扣券會恢復為未使用。原核銷紀錄會保留並標記為已作廢，作廢紀錄會記錄原 history_id、原因與操作的工作人員。每筆核銷只能作廢一次。
+// @Tags         discount
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                 true  "Coupon history ID (UUID)"
+// @Param        request  body      redemption.VoidBody    true  "Void reason"
+// @Success      201      {object}  redemption.VoidResponse
+// @Failure      400      {object}  res.ErrorResponse "invalid request body | reason is required"
+// @Failure      401      {object}  res.ErrorResponse "unauthorized staff"
+// @Failure      403      {object}  res.ErrorResponse "redemption was made by another staff"
+// @Failure      404      {object}  res.ErrorResponse "redemption not found"
+// @Failure      409      {object}  res.ErrorResponse "redemption already voided"
+// @Failure      500      {object}  res.ErrorResponse
+// @Router       /discount-coupons/staff/redemptions/{id}/voids [post]
+func (h *Handler) VoidRedemption(w http.ResponseWriter, r *http.Request) {
+	staff, ok := middleware.StaffFromContext(r.Context())
+	if !ok || staff == nil {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("unauthorized"), "unauthorized staff")
+		return
+	}
+
+	var req redemption.VoidBody
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	void, coupons, err := redemption.Void(r.Context(), h.Repo, tx, redemption.VoidRequest{
+		HistoryID: chi.URLParam(r, "id"),
+		Actor:     models.VoidActorStaff,
+		StaffID:   &staff.ID,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		status, msg := redemption.VoidErrorStatus(err)
+		res.Fail(w, r, status, err, msg)
+		return
+	}
+	if err = audit.Record(r.Context(), h.Repo, tx, audit.Entry{
+		Action:     audit.ActionRedemptionVoid,
+		TargetType: audit.TargetCouponHistory,
+		TargetID:   void.HistoryID,
+		After:      redemption.NewVoidResponse(void, coupons),
+	}); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to write audit event")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(redemption.NewVoidResponse(void, coupons))
+}
+
+// StaffRedemptionTotals handles GET /discount-coupons/staff/current/redemption-totals.
+// @Summary      取得工作人員核銷總額
+// @Description  需要 staff_token cookie。回傳該 staff 的核銷筆數與金額，net_total 為扣除已作廢核銷後的淨額。
+// @Tags         discount
+// @Produce      json
+// @Success      200  {object}  models.CouponHistoryTotals
+// @Failure      401  {object}  res.ErrorResponse "unauthorized staff"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /discount-coupons/staff/current/redemption-totals [get]
+func (h *Handler) StaffRedemptionTotals(w http.ResponseWriter, r *http.Request) {
+	staff, ok := middleware.StaffFromContext(r.Context())
+	if !ok || staff == nil {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("unauthorized"), "unauthorized staff")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	totals, err := h.Repo.SumCouponHistoryByStaff(r.Context(), tx, staff.ID)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to sum redemptions")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(totals)
+}
@@ -5,11 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
-	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/internal/service/notifications"
+	"github.com/sitcon-tw/2026-game/internal/service/qrtoken"
 	"github.com/sitcon-tw/2026-game/pkg/helpers"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
@@ -33,13 +34,14 @@ type friendCapacity struct {
 
 // AddByQRCode handles POST /friendships.
 // @Summary      建立好友關係
-// @Description  透過對方的 QR code 建立好友關係，雙方好友數量與 unlock_level 會在首次建立時各自增加。
+// @Description  透過對方以 purpose=friend 取得的 one-time QR code 建立好友關係（每個 QR code 只能使用一次），雙方好友數量與 unlock_level 會在首次建立時各自增加。
 // @Tags         friends
 // @Accept       json
 // @Produce      json
 // @Param        request  body      addByQRCodeRequest  true  "User QR code token"
 // @Success      200  {object}  models.PublicUser
 // @Failure      400  {object}  res.ErrorResponse "missing or invalid qr code | already friends"
+// @Failure      409  {object}  res.ErrorResponse "qr code already used"
 // @Failure      401  {object}  res.ErrorResponse "unauthorized"
 // @Failure      500  {object}  res.ErrorResponse
 // @Router       /friendships [post]
@@ -106,10 +108,28 @@ func (h *Handler) addByQRCode(ctx context.Context, currentUserID string, userQRC
 		return nil, err
 	}
 
+	for _, userID := range []string{currentUserID, targetUser.ID} {
+		if _, err = h.Coupons.Evaluate(ctx, tx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	currentUser, err := h.Repo.GetUserByID(ctx, tx, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err = notifications.FriendAdded(ctx, h.Repo, tx, targetUser.ID, currentUser); err != nil {
+		return nil, err
+	}
+
 	err = h.Repo.CommitTransaction(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
+	h.Events.Publish(ctx, events.Event{
+		Kind:    events.KindFriendAdded,
+		UserIDs: []string{currentUserID, targetUser.ID},
+	})
 
 	return targetUser, nil
 }
@@ -122,6 +142,10 @@ func (h *Handler) respondAddByQRCodeError(w http.ResponseWriter, r *http.Request
 		res.Fail(w, r, http.StatusBadRequest, err, "cannot add yourself")
 	case errors.Is(err, errAlreadyFriends):
 		res.Fail(w, r, http.StatusBadRequest, err, "already friends")
+	case errors.Is(err, qrtoken.ErrInvalidToken):
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid qr code")
+	case errors.Is(err, qrtoken.ErrTokenUsed):
+		res.Fail(w, r, http.StatusConflict, err, "qr code already used")
 	default:
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to add friend")
 	}
@@ -141,38 +165,13 @@ func parseAddByQRCodeRequest(r *http.Request) (addByQRCodeRequest, error) {
 }
 
 func (h *Handler) loadTargetUser(ctx context.Context, tx pgx.Tx, userQRCode string, currentUserID string) (*models.User, error) {
-	targetUserID, err := helpers.VerifyAndExtractUserIDFromOneTimeQRToken(
-		userQRCode,
-		time.Now().UTC(),
-		func(userID string) (string, error) {
-			targetUser, lookupErr := h.Repo.GetUserByID(ctx, tx, userID)
-			if lookupErr != nil {
-				if errors.Is(lookupErr, repository.ErrNotFound) {
-					return "", errUserNotFound
-				}
-				return "", lookupErr
-			}
-
-			return targetUser.QRCodeToken, nil
-		},
-	)
-	if err != nil {
-		if errors.Is(err, errUserNotFound) {
-			return nil, errUserNotFound
-		}
-		return nil, errUserNotFound
-	}
-
-	targetUser, err := h.Repo.GetUserByID(ctx, tx, targetUserID)
+	targetUser, err := qrtoken.Consume(ctx, h.Repo, tx, userQRCode, helpers.QRPurposeFriend)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
+		if errors.Is(err, qrtoken.ErrUserNotFound) {
 			return nil, errUserNotFound
 		}
 		return nil, err
 	}
-	if targetUser == nil {
-		return nil, errUserNotFound
-	}
 	if targetUser.ID == currentUserID {
 		return nil, errCannotAddSelf
 	}
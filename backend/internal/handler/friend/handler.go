@@ -2,6 +2,8 @@ package friend
 
 import (
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/couponrules"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -9,16 +11,20 @@ import (
 
 // Handler handles friend-related requests.
 type Handler struct {
-	Repo   repository.Repository
-	Logger *zap.Logger
-	tracer trace.Tracer
+	Repo    repository.Repository
+	Logger  *zap.Logger
+	Events  *events.Bus
+	Coupons *couponrules.Engine
+	tracer  trace.Tracer
 }
 
 // New wires required dependencies for the friend handler.
-func New(repo repository.Repository, logger *zap.Logger) *Handler {
+func New(repo repository.Repository, logger *zap.Logger, bus *events.Bus) *Handler {
 	return &Handler{
-		Repo:   repo,
-		Logger: logger,
-		tracer: otel.Tracer("github.com/sitcon-tw/2026-game/friend"),
+		Repo:    repo,
+		Logger:  logger,
+		Events:  bus,
+		Coupons: couponrules.New(repo),
+		tracer:  otel.Tracer("github.com/sitcon-tw/2026-game/friend"),
 	}
 }
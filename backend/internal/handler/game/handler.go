@@ -2,6 +2,9 @@ package game
 
 import (
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/couponrules"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/internal/service/leaderboard"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -9,16 +12,27 @@ import (
 
 // Handler handles game-related requests.
 type Handler struct {
-	Repo   repository.Repository
-	Logger *zap.Logger
-	tracer trace.Tracer
+	Repo        repository.Repository
+	Logger      *zap.Logger
+	Leaderboard *leaderboard.Service
+	Events      *events.Bus
+	Coupons     *couponrules.Engine
+	tracer      trace.Tracer
 }
 
 // New wires required dependencies for the game handler.
-func New(repo repository.Repository, logger *zap.Logger) *Handler {
+func New(
+	repo repository.Repository,
+	logger *zap.Logger,
+	board *leaderboard.Service,
+	bus *events.Bus,
+) *Handler {
 	return &Handler{
-		Repo:   repo,
-		Logger: logger,
-		tracer: otel.Tracer("github.com/sitcon-tw/2026-game/game"),
+		Repo:        repo,
+		Logger:      logger,
+		Leaderboard: board,
+		Events:      bus,
+		Coupons:     couponrules.New(repo),
+		tracer:      otel.Tracer("github.com/sitcon-tw/2026-game/game"),
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/pkg/config"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
@@ -17,11 +18,21 @@ type LevelInfoResponse struct {
 	Speed int      `json:"speed"`
 	Notes int      `json:"notes"`
 	Sheet []string `json:"sheet"`
+	// Chart carries each note's beat, duration and type; Beats is the chart length in beats.
+	Chart []models.Note `json:"chart"`
+	Beats float64       `json:"beats"`
+	// TrackID names the sheet-music track the sheet was taken from; Tempo and TimeSignature are the
+	// track's authored values, while Speed is the level's play speed.
+	TrackID       string `json:"track_id"`
+	Tempo         int    `json:"tempo"`
+	TimeSignature string `json:"time_signature"`
+	// ConfigVersion is the game config version this level was read from.
+	ConfigVersion int `json:"config_version"`
 }
 
 // GetLevelInfo handles GET /games/levels/{level}.
 // @Summary      取得指定關卡資訊
-// @Description  回傳指定 level 的速度與需要的音符數，以及對應的譜面片段。若 level 為 "current"，則取目前登入使用者的 current_level。
+// @Description  回傳指定 level 的速度與需要的音符數，以及對應的譜面片段。sheet 為音名列表，chart 為含拍點、長度與類型（tap／hold／chord）的結構化譜面，beats 為譜面總拍數。若 level 為 "current"，則取目前登入使用者的 current_level。track_id 為譜面所屬曲目（如贊助商主題曲），tempo 與 time_signature 為該曲目的原始速度與拍號。config_version 為回應所依據的關卡設定版本，管理員切換版本後會改變。
 // @Tags         game
 // @Produce      json
 // @Param        level  path      string  true  "關卡等級 (從 1 開始) 或 'current'"
@@ -67,21 +78,31 @@ func (h *Handler) GetLevelInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	info, ok, err := config.LevelInfo(lvl)
+	// Read from one snapshot so the level and the reported version always match.
+	cfg, err := config.ActiveGameConfig()
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to load level config")
 		return
 	}
+	info, ok := cfg.LevelInfo(lvl)
 	if !ok {
 		res.Fail(w, r, http.StatusNotFound, nil, "level not found")
 		return
 	}
 
 	resp := LevelInfoResponse{
-		Level: info.Level,
-		Speed: info.Speed,
-		Notes: info.Notes,
-		Sheet: info.Sheet,
+		Level:         info.Level,
+		Speed:         info.Speed,
+		Notes:         info.Notes,
+		Sheet:         info.Sheet,
+		Chart:         info.Chart,
+		Beats:         info.Beats,
+		ConfigVersion: cfg.Version,
+	}
+	if track, found := cfg.Track(info.TrackID); found {
+		resp.TrackID = track.TrackID
+		resp.Tempo = track.Tempo
+		resp.TimeSignature = track.TimeSignature
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -1,11 +1,14 @@
 package game
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 )
@@ -15,10 +18,13 @@ const aroundSpan = 10
 
 // Rank handles GET /games/leaderboards.
 // @Summary      取得遊戲的排行資料
-// @Description  排行資料會包含三個部分：1. 全站的分頁排行 (每頁30名) 2. 以目前使用者為中心，前後各10名玩家的暱稱、等級與排名 3. 目前使用者的暱稱、等級與排名。需要登入後才能取得排行資料。支援 page 查詢參數來分頁瀏覽全站排行，每頁30名玩家，預設為第1頁。
+// @Description  排行資料會包含三個部分：1. 全站的分頁排行 (每頁30名) 2. 以目前使用者為中心，前後各10名玩家的暱稱、等級與排名 3. 目前使用者的暱稱、等級與排名。需要登入後才能取得排行資料。支援 page 查詢參數來分頁瀏覽全站排行，每頁30名玩家，預設為第1頁。board 查詢參數可選擇排行榜：overall（全站進度，預設）、friends（自己與好友）、group（同組成員）、activities（造訪最多攤位/活動）、most_friends（好友最多）。score 為該排行榜的排序依據數值。全站類型的排行榜（overall、activities、most_friends）由定期更新的快照提供，snapshot_at 為快照時間；friends 與 group 會即時查詢。排行榜凍結後會回傳凍結當下的名次，frozen_at 為凍結時間。
 // @Tags         game
 // @Produce      json
+// @Param        page   query     int     false  "頁數，預設 1"
+// @Param        board  query     string  false  "排行榜" Enums(overall, friends, group, activities, most_friends)
 // @Success      200  {object}  RankResponse  ""
+// @Failure      400  {object}  res.ErrorResponse "invalid page parameter | invalid board parameter"
 // @Failure      401  {object}  res.ErrorResponse "unauthorized"
 // @Failure      500  {object}  res.ErrorResponse
 // @Router       /games/leaderboards [get]
@@ -40,69 +46,35 @@ func (h *Handler) Rank(w http.ResponseWriter, r *http.Request) {
 	}
 	offset := (page - 1) * pageSize
 
-	tx, err := h.Repo.StartTransaction(r.Context())
-	if err != nil {
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
-		return
+	board := repository.BoardOverall
+	if b := r.URL.Query().Get("board"); b != "" {
+		board = repository.Board(b)
 	}
-	defer h.Repo.DeferRollback(r.Context(), tx)
-
-	// Fetch pieces individually to keep repository concerns separated.
-	topRows, err := h.Repo.GetTopUsers(r.Context(), tx, pageSize, offset)
-	if err != nil {
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch top users")
+	if _, known := repository.LookupRankingStrategy(board); !known {
+		res.Fail(w, r, http.StatusBadRequest, repository.ErrUnknownBoard, "invalid board parameter")
 		return
 	}
 
-	meRow, meRank, err := h.Repo.GetUserWithRank(r.Context(), tx, user.ID)
+	rows, err := h.loadRankRows(r.Context(), board, user.ID, offset)
 	if err != nil {
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch user rank")
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch leaderboard")
 		return
 	}
 
-	aroundRows, err := h.Repo.GetAroundUsers(r.Context(), tx, user.ID, aroundSpan)
-	if err != nil {
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch around users")
-		return
-	}
-
-	err = h.Repo.CommitTransaction(r.Context(), tx)
-	if err != nil {
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
-		return
-	}
-
-	top := make([]RankEntry, len(topRows))
-	for i, row := range topRows {
-		top[i] = RankEntry{
-			Nickname: row.User.Nickname,
-			Avatar:   row.User.Avatar,
-			Level:    row.User.CurrentLevel,
-			Namecard: models.ToPublicUser(row.User).Namecard,
-			Rank:     row.Rank,
-		}
+	top := make([]RankEntry, len(rows.top))
+	for i, row := range rows.top {
+		top[i] = toRankEntry(row)
 	}
 
 	var me *RankEntry
-	if meRow != nil {
-		me = &RankEntry{
-			Nickname: meRow.Nickname,
-			Avatar:   meRow.Avatar,
-			Level:    meRow.CurrentLevel,
-			Namecard: models.ToPublicUser(*meRow).Namecard,
-			Rank:     meRank,
-		}
+	if rows.me != nil {
+		entry := toRankEntry(*rows.me)
+		me = &entry
 	}
 
-	around := make([]RankEntry, len(aroundRows))
-	for i, row := range aroundRows {
-		around[i] = RankEntry{
-			Nickname: row.User.Nickname,
-			Avatar:   row.User.Avatar,
-			Level:    row.User.CurrentLevel,
-			Namecard: models.ToPublicUser(row.User).Namecard,
-			Rank:     row.Rank,
-		}
+	around := make([]RankEntry, len(rows.around))
+	for i, row := range rows.around {
+		around[i] = toRankEntry(row)
 	}
 
 	resp := RankResponse{
@@ -110,9 +82,83 @@ func (h *Handler) Rank(w http.ResponseWriter, r *http.Request) {
 		Around: around,
 		Me:     me,
 		Page:   page,
+		Board:  string(board),
+
+		SnapshotAt: rows.snapshotAt,
+		FrozenAt:   rows.frozenAt,
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(resp)
 }
+
+// rankRows is the raw leaderboard data for one response, either from a cached snapshot or the database.
+type rankRows struct {
+	top        []repository.RankedUser
+	me         *repository.RankedUser
+	around     []repository.RankedUser
+	snapshotAt time.Time
+	frozenAt   *time.Time
+}
+
+// loadRankRows serves cached boards from their snapshot. Viewer-scoped boards, a cold cache,
+// and users who joined after the snapshot was taken fall back to the database.
+// A frozen board never falls back: users missing from the freeze simply have no rank.
+func (h *Handler) loadRankRows(ctx context.Context, board repository.Board, userID string, offset int) (rankRows, error) {
+	if snap, ok := h.Leaderboard.Snapshot(board); ok {
+		me, found := snap.Find(userID)
+		if found || snap.FrozenAt != nil {
+			rows := rankRows{
+				top:        snap.Page(pageSize, offset),
+				around:     snap.Around(userID, aroundSpan),
+				snapshotAt: snap.TakenAt,
+				frozenAt:   snap.FrozenAt,
+			}
+			if found {
+				rows.me = &me
+			}
+			return rows, nil
+		}
+	}
+
+	tx, err := h.Repo.StartTransaction(ctx)
+	if err != nil {
+		return rankRows{}, err
+	}
+	defer h.Repo.DeferRollback(ctx, tx)
+
+	rows := rankRows{snapshotAt: time.Now().UTC()}
+
+	// Fetch pieces individually to keep repository concerns separated.
+	rows.top, err = h.Repo.GetTopUsers(ctx, tx, board, userID, pageSize, offset)
+	if err != nil {
+		return rankRows{}, err
+	}
+
+	rows.me, err = h.Repo.GetUserWithRank(ctx, tx, board, userID)
+	if err != nil {
+		return rankRows{}, err
+	}
+
+	rows.around, err = h.Repo.GetAroundUsers(ctx, tx, board, userID, aroundSpan)
+	if err != nil {
+		return rankRows{}, err
+	}
+
+	if err = h.Repo.CommitTransaction(ctx, tx); err != nil {
+		return rankRows{}, err
+	}
+	return rows, nil
+}
+
+func toRankEntry(row repository.RankedUser) RankEntry {
+	return RankEntry{
+		Nickname: row.User.Nickname,
+		Avatar:   row.User.Avatar,
+		Level:    row.User.CurrentLevel,
+		Score:    row.Score,
+		Namecard: models.ToPublicUser(row.User).Namecard,
+		Rank:     row.Rank,
+	}
+}
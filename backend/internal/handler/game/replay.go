@@ -0,0 +1,219 @@
+package game
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+// Judgment values accepted in a submitted hit log.
+const (
+	judgmentPerfect = "perfect"
+	judgmentGreat   = "great"
+	judgmentMiss    = "miss"
+)
+
+const (
+	// perfectWindowDivisor sets the perfect window to ±interval/4 around the expected tap gap.
+	perfectWindowDivisor = 4
+	// holdMissDivisor makes a hold released before half its duration a miss.
+	holdMissDivisor = 2
+	// minTapGap is the shortest gap between two taps we accept from a human player.
+	minTapGap = 60 * time.Millisecond
+	// clockSkewTolerance allows the client clock to run slightly ahead of the server.
+	clockSkewTolerance = 2 * time.Second
+	// scorePerfect and scoreGreat are the points awarded per judged note.
+	scorePerfect = 1000
+	scoreGreat   = 500
+	// comboBonus is added per note of the longest combo.
+	comboBonus = 10
+)
+
+var (
+	errHitCountMismatch  = errors.New("hit count does not match level notes")
+	errHitOutOfOrder     = errors.New("hit log is out of order")
+	errHitBeforePlayback = errors.New("hit recorded before sheet playback finished")
+	errHitTooDense       = errors.New("hits are closer than humanly possible")
+	errHitInFuture       = errors.New("hit timestamp is later than server clock")
+	errUnknownJudgment   = errors.New("unknown judgment")
+	errJudgmentMismatch  = errors.New("claimed judgment does not match replay")
+)
+
+// hitEntry is one tap recorded by the client, relative to the play session start.
+// Note is the note's label; chord pitches may be sent in any order. HoldMS is how long a hold
+// note was held down and is ignored for other notes.
+type hitEntry struct {
+	Index    int    `json:"index"`
+	Note     string `json:"note"`
+	AtMS     int64  `json:"at_ms"`
+	HoldMS   int64  `json:"hold_ms,omitempty"`
+	Judgment string `json:"judgment"`
+}
+
+// replayResult summarizes a hit log after server-side replay.
+type replayResult struct {
+	Perfect  int
+	Great    int
+	Miss     int
+	MaxCombo int
+}
+
+// score converts judgment counts into the points stored on a level run.
+func (r replayResult) score() int {
+	return r.Perfect*scorePerfect + r.Great*scoreGreat + r.MaxCombo*comboBonus
+}
+
+// cleared reports whether the run passes the level; a single miss fails it.
+func (r replayResult) cleared() bool {
+	return r.Miss == 0
+}
+
+// accuracy is the weighted hit ratio in [0, 1]; a great counts as half a perfect.
+func (r replayResult) accuracy() float64 {
+	total := r.Perfect + r.Great + r.Miss
+	if total == 0 {
+		return 0
+	}
+	return (float64(r.Perfect) + float64(r.Great)/2) / float64(total)
+}
+
+// replayHitLog re-judges a hit log against the level chart and speed. Misses are counted, not
+// rejected; only logs that could not have been played honestly return an error.
+// elapsed is the server-measured time between opening the session and submitting it.
+func replayHitLog(info models.LevelInfo, hits []hitEntry, elapsed time.Duration) (replayResult, error) {
+	if len(hits) != info.Notes || len(info.Chart) != info.Notes {
+		return replayResult{}, errHitCountMismatch
+	}
+
+	interval := time.Minute / time.Duration(info.Speed)
+	playback := beatsDuration(info.Beats, interval)
+	perfectWindow := interval / perfectWindowDivisor
+
+	var (
+		result replayResult
+		combo  int
+		prevAt time.Duration
+	)
+	for i, hit := range hits {
+		if hit.Index != i {
+			return replayResult{}, errHitOutOfOrder
+		}
+
+		note := info.Chart[i]
+		at := time.Duration(hit.AtMS) * time.Millisecond
+		held := time.Duration(max(hit.HoldMS, 0)) * time.Millisecond
+		if note.Type != models.NoteHold {
+			held = 0
+		}
+		if at < playback {
+			return replayResult{}, errHitBeforePlayback
+		}
+		if at+held > elapsed+clockSkewTolerance {
+			return replayResult{}, errHitInFuture
+		}
+		if i > 0 && at-prevAt < minTapGap {
+			return replayResult{}, errHitTooDense
+		}
+
+		var expectedGap time.Duration
+		if i > 0 {
+			expectedGap = beatsDuration(note.Beat-info.Chart[i-1].Beat, interval)
+		}
+		judgment := judgeHit(note, hit.Note, i, at-prevAt, expectedGap, perfectWindow)
+		if note.Type == models.NoteHold {
+			judgment = judgeHold(judgment, held, beatsDuration(note.Duration, interval), perfectWindow)
+		}
+		switch hit.Judgment {
+		case judgmentPerfect, judgmentGreat, judgmentMiss:
+		default:
+			return replayResult{}, errUnknownJudgment
+		}
+		if hit.Judgment != judgment {
+			return replayResult{}, errJudgmentMismatch
+		}
+
+		switch judgment {
+		case judgmentPerfect:
+			result.Perfect++
+			combo++
+		case judgmentGreat:
+			result.Great++
+			combo++
+		default:
+			result.Miss++
+			combo = 0
+		}
+		result.MaxCombo = max(result.MaxCombo, combo)
+		prevAt = at
+	}
+
+	return result, nil
+}
+
+// judgeHit grades a single tap against the gap the chart expects since the previous note.
+// The first tap has no previous beat to compare against, so a correct first note is always perfect.
+func judgeHit(expected models.Note, actual string, index int, gap, expectedGap, perfectWindow time.Duration) string {
+	if !sameNote(expected, actual) {
+		return judgmentMiss
+	}
+	if index == 0 {
+		return judgmentPerfect
+	}
+
+	deviation := gap - expectedGap
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	if deviation <= perfectWindow {
+		return judgmentPerfect
+	}
+	return judgmentGreat
+}
+
+// judgeHold lowers a tap judgment when the hold was released early: before half of the note it is
+// a miss, and more than the perfect window early it is at most great.
+func judgeHold(tap string, held, required, perfectWindow time.Duration) string {
+	switch {
+	case tap == judgmentMiss || held < required/holdMissDivisor:
+		return judgmentMiss
+	case held < required-perfectWindow:
+		return judgmentGreat
+	default:
+		return tap
+	}
+}
+
+// sameNote reports whether a submitted label names the expected note, ignoring chord pitch order.
+func sameNote(expected models.Note, actual string) bool {
+	pitches := strings.Split(actual, models.ChordSeparator)
+	if len(pitches) != len(expected.Pitches) {
+		return false
+	}
+	want := slices.Clone(expected.Pitches)
+	slices.Sort(pitches)
+	slices.Sort(want)
+	return slices.Equal(pitches, want)
+}
+
+// beatsDuration converts a length in beats to wall time at one beat per interval.
+func beatsDuration(beats float64, interval time.Duration) time.Duration {
+	return time.Duration(math.Round(beats * float64(interval)))
+}
+
+// chartDigest fingerprints a level chart so a session can detect config changes between start and
+// submit. Timing and note types are included, so retiming a chart invalidates open sessions too.
+func chartDigest(chart []models.Note) string {
+	lines := make([]string, len(chart))
+	for i, note := range chart {
+		lines[i] = fmt.Sprintf("%s,%v,%v,%s", note.Label(), note.Beat, note.Duration, note.Type)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,31 +1,98 @@
 package game
 
-import "github.com/sitcon-tw/2026-game/internal/models"
+import (
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
 
 // RankEntry is the public representation of a leaderboard row.
 type RankEntry struct {
 	Nickname string                `json:"nickname"`
 	Avatar   *string               `json:"avatar,omitempty"`
 	Level    int                   `json:"level"`
+	Score    int                   `json:"score"`
 	Namecard models.PublicNamecard `json:"namecard"`
 	Rank     int                   `json:"rank"`
 }
 
 // RankResponse is returned by GET /game/rank.
 // Rank contains the global ranking list (paged); Around contains the caller's ±10 neighbors (inclusive);
-// Me is the caller; Page echoes the requested page number for Rank.
+// Me is the caller; Page echoes the requested page number for Rank; Board echoes the board being ranked.
+// SnapshotAt is when the ranking was computed; cached boards may lag behind the latest submissions.
+// FrozenAt is set once the board is frozen for settlement.
 type RankResponse struct {
 	Rank   []RankEntry `json:"rank"`
 	Around []RankEntry `json:"around"`
 	Me     *RankEntry  `json:"me"`
 	Page   int         `json:"page"`
+	Board  string      `json:"board"`
+
+	SnapshotAt time.Time  `json:"snapshot_at"`
+	FrozenAt   *time.Time `json:"frozen_at"`
 }
 
-// SubmitResponse is returned by POST /game.
+// PlaySessionResponse is returned by POST /games/sessions.
+// The nonce must be echoed back together with the hit log when submitting.
+type PlaySessionResponse struct {
+	SessionID string   `json:"session_id"`
+	Nonce     string   `json:"nonce"`
+	Level     int      `json:"level"`
+	Speed     int      `json:"speed"`
+	Notes     int      `json:"notes"`
+	Sheet     []string `json:"sheet"`
+	// Chart carries each note's beat, duration and type; Beats is the chart length in beats.
+	Chart     []models.Note `json:"chart"`
+	Beats     float64       `json:"beats"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+// SubmitResponse is returned by POST /games/submissions.
+// RankDelta is the leaderboard movement caused by this submission; positive means the player moved up.
+// Both ranks are taken against the latest overall snapshot.
 type SubmitResponse struct {
 	CurrentLevel int              `json:"current_level"`
 	UnlockLevel  int              `json:"unlock_level"`
 	Coupons      []CouponResponse `json:"coupons"`
+	Run          LevelRunEntry    `json:"run"`
+	PersonalBest bool             `json:"personal_best"`
+	Rank         int              `json:"rank"`
+	RankDelta    int              `json:"rank_delta"`
+}
+
+// LevelRunEntry is the public representation of a single run. Cleared is false for runs with misses.
+type LevelRunEntry struct {
+	Level     int       `json:"level"`
+	Score     int       `json:"score"`
+	Accuracy  float64   `json:"accuracy"`
+	MaxCombo  int       `json:"max_combo"`
+	Perfect   int       `json:"perfect"`
+	Great     int       `json:"great"`
+	Miss      int       `json:"miss"`
+	Cleared   bool      `json:"cleared"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LevelRunsResponse is returned by GET /games/levels/{level}/runs.
+// Best is null when the caller has not cleared the level yet.
+type LevelRunsResponse struct {
+	Level int             `json:"level"`
+	Best  *LevelRunEntry  `json:"best"`
+	Runs  []LevelRunEntry `json:"runs"`
+}
+
+func toLevelRunEntry(run models.LevelRun) LevelRunEntry {
+	return LevelRunEntry{
+		Level:     run.Level,
+		Score:     run.Score,
+		Accuracy:  run.Accuracy,
+		MaxCombo:  run.MaxCombo,
+		Perfect:   run.Perfect,
+		Great:     run.Great,
+		Miss:      run.Miss,
+		Cleared:   run.Cleared,
+		CreatedAt: run.CreatedAt,
+	}
 }
 
 // CouponResponse represents a discount coupon earned by passing levels.
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+const levelRunHistoryLimit = 20
+
+// ListLevelRuns handles GET /games/levels/{level}/runs.
+// @Summary      取得指定關卡的遊玩紀錄
+// @Description  回傳目前登入使用者在指定 level 的個人最佳紀錄，以及最近 20 次通過驗證的遊玩紀錄（由新到舊，含有 miss 而未過關、cleared 為 false 的紀錄），包含分數、準確率、最大連擊與 perfect/great/miss 數量。個人最佳只計算 cleared 的紀錄，尚未通過該關時 best 為 null。
+// @Tags         game
+// @Produce      json
+// @Param        level  path      int  true  "關卡等級 (從 1 開始)"
+// @Success      200    {object}  LevelRunsResponse
+// @Failure      400    {object}  res.ErrorResponse "invalid level"
+// @Failure      401    {object}  res.ErrorResponse "unauthorized"
+// @Failure      500    {object}  res.ErrorResponse
+// @Router       /games/levels/{level}/runs [get]
+func (h *Handler) ListLevelRuns(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok || user == nil {
+		res.Fail(w, r, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	lvl, err := strconv.Atoi(chi.URLParam(r, "level"))
+	if err != nil || lvl <= 0 {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid level")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	resp := LevelRunsResponse{
+		Level: lvl,
+		Runs:  []LevelRunEntry{},
+	}
+
+	best, err := h.Repo.GetPersonalBest(r.Context(), tx, user.ID, lvl)
+	switch {
+	case err == nil:
+		entry := toLevelRunEntry(*best)
+		resp.Best = &entry
+	case !errors.Is(err, repository.ErrNotFound):
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch personal best")
+		return
+	}
+
+	runs, err := h.Repo.ListLevelRuns(r.Context(), tx, user.ID, lvl, levelRunHistoryLimit)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch level runs")
+		return
+	}
+	for _, run := range runs {
+		resp.Runs = append(resp.Runs, toLevelRunEntry(run))
+	}
+
+	err = h.Repo.CommitTransaction(r.Context(), tx)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
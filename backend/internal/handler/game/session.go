@@ -0,0 +1,125 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+const sessionNonceLength = 32
+
+type startSessionRequest struct {
+	Level int `json:"level"`
+}
+
+// StartSession handles POST /games/sessions.
+// @Summary      開始一場遊戲
+// @Description  開始遊玩某一關前先呼叫此 API 取得 session_id 與 nonce，提交時需帶回完整的打擊紀錄。sheet 為各音符名稱，chart 另帶每個音符的 pitches、beat（自本關第一個音符起算的拍數）、duration 與 type（tap／hold／chord），一拍長 60/speed 秒；beats 為整份譜面長度，打擊必須在播完後才開始。level 可以是目前可以挑戰的下一關，或是已經通過、想刷新分數的關卡。session 只能使用一次且會過期。
+// @Tags         game
+// @Accept       json
+// @Produce      json
+// @Param        request  body      startSessionRequest  true  "Level to play"
+// @Success      201      {object}  PlaySessionResponse
+// @Failure      400      {object}  res.ErrorResponse "invalid level | current level cannot exceed unlock level"
+// @Failure      401      {object}  res.ErrorResponse "unauthorized"
+// @Failure      404      {object}  res.ErrorResponse "level not found"
+// @Failure      500      {object}  res.ErrorResponse
+// @Router       /games/sessions [post]
+func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok || user == nil {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("unauthorized"), "unauthorized")
+		return
+	}
+
+	var req startSessionRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	fresh, err := h.Repo.GetUserByID(r.Context(), tx, user.ID)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to load user")
+		return
+	}
+
+	// Cleared levels can be replayed for a better score; nothing beyond the next level can be opened.
+	if req.Level < 1 || req.Level > fresh.CurrentLevel+1 {
+		res.Fail(w, r, http.StatusBadRequest, errors.New("level is not playable yet"), "invalid level")
+		return
+	}
+	if req.Level > fresh.UnlockLevel {
+		res.Fail(w, r, http.StatusBadRequest, errLevelExceedsUnlock, "current level cannot exceed unlock level")
+		return
+	}
+
+	info, found, err := config.LevelInfo(req.Level)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to load level config")
+		return
+	}
+	if !found {
+		res.Fail(w, r, http.StatusNotFound, nil, "level not found")
+		return
+	}
+
+	nonce, err := helpers.RandomAlphabetToken(sessionNonceLength)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to generate nonce")
+		return
+	}
+
+	now := time.Now().UTC()
+	session := &models.PlaySession{
+		ID:        uuid.NewString(),
+		UserID:    fresh.ID,
+		Level:     info.Level,
+		Nonce:     nonce,
+		SheetHash: chartDigest(info.Chart),
+		ExpiresAt: now.Add(config.Env().GameSessionTTL),
+		CreatedAt: now,
+	}
+	if err = h.Repo.InsertPlaySession(r.Context(), tx, session); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to create play session")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	resp := PlaySessionResponse{
+		SessionID: session.ID,
+		Nonce:     session.Nonce,
+		Level:     info.Level,
+		Speed:     info.Speed,
+		Notes:     info.Notes,
+		Sheet:     info.Sheet,
+		Chart:     info.Chart,
+		Beats:     info.Beats,
+		ExpiresAt: session.ExpiresAt,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
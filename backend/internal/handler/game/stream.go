@@ -0,0 +1,160 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/leaderboard"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+const streamHeartbeat = 15 * time.Second
+
+// SSE event names sent by GET /games/leaderboards/stream.
+const (
+	streamEventTop  = "top"
+	streamEventRank = "rank"
+)
+
+// LeaderboardTopEvent is pushed when the leading rows of a board change.
+type LeaderboardTopEvent struct {
+	Board      string      `json:"board"`
+	SnapshotAt time.Time   `json:"snapshot_at"`
+	Rank       []RankEntry `json:"rank"`
+}
+
+// RankChangeEvent is pushed to a single user when their own rank or score changes.
+// PreviousRank is 0 when the user was not on the board before.
+type RankChangeEvent struct {
+	Board        string    `json:"board"`
+	SnapshotAt   time.Time `json:"snapshot_at"`
+	Rank         int       `json:"rank"`
+	PreviousRank int       `json:"previous_rank"`
+	Score        int       `json:"score"`
+}
+
+// StreamRank handles GET /games/leaderboards/stream.
+// @Summary      即時排行榜推播 (SSE)
+// @Description  以 Server-Sent Events 推播排行榜變化。連線後會先送出一次目前的前 10 名（event: top）與自己的排名（event: rank），之後前 10 名有變動時推播 top，自己的名次或分數變動時推播 rank。每 15 秒會送出註解行作為 heartbeat。board 僅支援全站類型的排行榜（overall、activities、most_friends），預設為 overall。需要登入。
+// @Tags         game
+// @Produce      text/event-stream
+// @Param        board  query     string  false  "排行榜" Enums(overall, activities, most_friends)
+// @Success      200  {string}  string  "event: top 的 data 為 LeaderboardTopEvent；event: rank 的 data 為 RankChangeEvent"
+// @Failure      400  {object}  res.ErrorResponse "invalid board parameter"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      503  {object}  res.ErrorResponse "leaderboard is warming up"
+// @Router       /games/leaderboards/stream [get]
+func (h *Handler) StreamRank(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok || user == nil {
+		res.Fail(w, r, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	board := repository.BoardOverall
+	if b := r.URL.Query().Get("board"); b != "" {
+		board = repository.Board(b)
+	}
+	strategy, known := repository.LookupRankingStrategy(board)
+	if !known || strategy.Scoped() {
+		res.Fail(w, r, http.StatusBadRequest, repository.ErrUnknownBoard, "invalid board parameter")
+		return
+	}
+
+	snap, ok := h.Leaderboard.Snapshot(board)
+	if !ok {
+		res.Fail(w, r, http.StatusServiceUnavailable, errors.New("snapshot not loaded"), "leaderboard is warming up")
+		return
+	}
+
+	updates, stop := h.Leaderboard.Watch()
+	defer stop()
+
+	rc := http.NewResponseController(w)
+	// The server write timeout would otherwise cut the stream.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	_ = writeSSE(w, streamEventTop, topEvent(board, snap.TakenAt, snap.Page(leaderboard.StreamTopN, 0)))
+	if me, found := snap.Find(user.ID); found {
+		_ = writeSSE(w, streamEventRank, RankChangeEvent{
+			Board:        string(board),
+			SnapshotAt:   snap.TakenAt,
+			Rank:         me.Rank,
+			PreviousRank: me.Rank,
+			Score:        me.Score,
+		})
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": ping\n\n")
+		case u := <-updates:
+			if u.Board != board {
+				continue
+			}
+			err = h.writeUpdate(w, u, user.ID)
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (h *Handler) writeUpdate(w http.ResponseWriter, u leaderboard.Update, userID string) error {
+	if u.TopChanged {
+		if err := writeSSE(w, streamEventTop, topEvent(u.Board, u.SnapshotAt, u.Top)); err != nil {
+			return err
+		}
+	}
+	if change, ok := u.Changes[userID]; ok {
+		return writeSSE(w, streamEventRank, RankChangeEvent{
+			Board:        string(u.Board),
+			SnapshotAt:   u.SnapshotAt,
+			Rank:         change.Current,
+			PreviousRank: change.Previous,
+			Score:        change.Score,
+		})
+	}
+	return nil
+}
+
+func topEvent(board repository.Board, at time.Time, rows []repository.RankedUser) LeaderboardTopEvent {
+	entries := make([]RankEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = toRankEntry(row)
+	}
+	return LeaderboardTopEvent{Board: string(board), SnapshotAt: at, Rank: entries}
+}
+
+func writeSSE(w http.ResponseWriter, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
@@ -2,13 +2,17 @@ package game
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
 	"github.com/sitcon-tw/2026-game/pkg/config"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
@@ -16,20 +20,36 @@ import (
 	"go.opentelemetry.io/otel/codes"
 )
 
-var errLevelExceedsUnlock = errors.New("level exceeds unlock")
-var errSubmissionTooFast = errors.New("submission too fast")
+var (
+	errLevelExceedsUnlock   = errors.New("level exceeds unlock")
+	errSessionNotFound      = errors.New("play session not found")
+	errSessionConsumed      = errors.New("play session already used")
+	errSessionExpired       = errors.New("play session expired")
+	errSessionNonceMismatch = errors.New("play session nonce mismatch")
+	errSessionLevelMismatch = errors.New("play session is for another level")
+	errSheetChanged         = errors.New("level sheet changed since session start")
+)
 
-const secondsPerMinute = 60
+type submitRequest struct {
+	SessionID string     `json:"session_id"`
+	Nonce     string     `json:"nonce"`
+	Hits      []hitEntry `json:"hits"`
+}
 
 // Submit handles POST /games/submissions.
 // @Summary      提交遊戲紀錄
-// @Description  提交你的遊戲紀錄，會幫你把你目前的 level 提升 1 級。一樣需要 cookie 登入，需要注意的點是，當前等級不能超過解鎖等級。以及這整個是沒有任何驗證的，代表別人可以狂 call API，未來需要修個。
+// @Description  帶上 POST /games/sessions 取得的 session_id、nonce 與每個音符的打擊紀錄（index、note、at_ms、judgment；hold 音符另帶按住毫秒數 hold_ms，和弦的 note 為以 + 連接的音高，順序不拘）。後端會依照關卡譜面的拍點、長度與速度重播整份紀錄並計算分數、準確率與最大連擊，驗證通過的紀錄會保存為一次遊玩紀錄；有 miss 的紀錄同樣會保存，但 run.cleared 為 false，不會提升等級也不算個人最佳。若 session 是下一關且沒有 miss，會把 current level 提升 1 級；若是已通過的關卡，只會更新個人最佳紀錄。回應會附上本次分數、是否刷新個人最佳以及排名變化（依全站排行榜快照計算，rank_delta 為正代表名次上升）。session 只能使用一次，未通過驗證的紀錄會被保存供人工檢查。一樣需要 cookie 登入，當前等級不能超過解鎖等級。
 // @Tags         game
+// @Accept       json
 // @Produce      json
+// @Param        request  body      submitRequest  true  "Play session and hit log"
 // @Success      200  {object}  SubmitResponse
-// @Failure      400  {object}  res.ErrorResponse "current level cannot exceed unlock level"
-// @Failure      429  {object}  res.ErrorResponse "submission too fast"
+// @Failure      400  {object}  res.ErrorResponse "invalid request body | current level cannot exceed unlock level | invalid nonce"
 // @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      404  {object}  res.ErrorResponse "play session not found"
+// @Failure      409  {object}  res.ErrorResponse "play session already used | play session level mismatch | level config changed"
+// @Failure      410  {object}  res.ErrorResponse "play session expired"
+// @Failure      422  {object}  res.ErrorResponse "hit log rejected"
 // @Failure      500  {object}  res.ErrorResponse
 // @Router       /games/submissions [post]
 func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
@@ -39,6 +59,18 @@ func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var req submitRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+	if req.SessionID == "" || req.Nonce == "" {
+		res.Fail(w, r, http.StatusBadRequest, errors.New("missing session_id or nonce"), "invalid request body")
+		return
+	}
+
 	tx, err := h.Repo.StartTransaction(r.Context())
 	if err != nil {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
@@ -57,34 +89,79 @@ func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newLevel, err := h.validateNextLevel(r.Context(), fresh)
+	now := time.Now().UTC()
+	session, err := h.loadPlaySession(r.Context(), tx, fresh.ID, req, now)
+	if err != nil {
+		respondSubmitError(w, r, err)
+		return
+	}
+
+	levelCfg, advance, err := h.validateSessionLevel(r.Context(), fresh, session)
 	if err != nil {
-		if errors.Is(err, errLevelExceedsUnlock) {
-			res.Fail(w,
-				r,
-				http.StatusBadRequest,
-				err,
-				"current level cannot exceed unlock level",
-			)
+		respondSubmitError(w, r, err)
+		return
+	}
+
+	if err = h.Repo.ConsumePlaySession(r.Context(), tx, session.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondSubmitError(w, r, errSessionConsumed)
 			return
 		}
-		if errors.Is(err, errSubmissionTooFast) {
-			res.Fail(w, r, http.StatusTooManyRequests, err, "submission too fast")
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to consume play session")
+		return
+	}
+
+	result, replayErr := h.replay(r.Context(), levelCfg, req.Hits, now.Sub(session.CreatedAt))
+	if replayErr != nil {
+		if err = h.storeRejection(r.Context(), tx, session, req.Hits, replayErr); err != nil {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to store rejected hit log")
 			return
 		}
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to validate level")
+		// Commit so the session stays consumed and the rejected log is kept for review.
+		if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+			return
+		}
+		res.Fail(w, r, http.StatusUnprocessableEntity, replayErr, "hit log rejected")
 		return
 	}
 
-	err = h.Repo.UpdateCurrentLevel(r.Context(), tx, fresh.ID, newLevel)
+	// A run with misses is stored but does not clear the level.
+	advance = advance && result.cleared()
+
+	rankBefore, err := h.rankOnOverallBoard(r.Context(), tx, *fresh)
 	if err != nil {
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to update level")
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch user rank")
 		return
 	}
 
-	issued, err := h.issueCoupons(r.Context(), tx, fresh.ID, newLevel)
+	run, personalBest, err := h.recordRun(r.Context(), tx, session, result)
 	if err != nil {
-		res.Fail(w, r, http.StatusInternalServerError, err, "failed to issue coupon")
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to record level run")
+		return
+	}
+
+	updated := *fresh
+	issued := []CouponResponse{}
+	if advance {
+		updated.CurrentLevel = session.Level
+		updated.LastPassTime = now
+		err = h.Repo.UpdateCurrentLevel(r.Context(), tx, fresh.ID, updated.CurrentLevel)
+		if err != nil {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to update level")
+			return
+		}
+
+		issued, err = h.issueCoupons(r.Context(), tx, fresh.ID)
+		if err != nil {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to issue coupon")
+			return
+		}
+	}
+
+	rankAfter, err := h.rankOnOverallBoard(r.Context(), tx, updated)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to fetch user rank")
 		return
 	}
 
@@ -93,11 +170,22 @@ func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
 		return
 	}
+	if advance {
+		h.Events.Publish(r.Context(), events.Event{
+			Kind:    events.KindLevelPassed,
+			UserIDs: []string{fresh.ID},
+		})
+	}
 
 	resp := SubmitResponse{
-		CurrentLevel: newLevel,
+		CurrentLevel: updated.CurrentLevel,
 		UnlockLevel:  fresh.UnlockLevel,
 		Coupons:      issued,
+		Run:          toLevelRunEntry(*run),
+		PersonalBest: personalBest,
+		Rank:         rankAfter,
+		// Positive when the player climbed the leaderboard.
+		RankDelta: rankBefore - rankAfter,
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -105,79 +193,226 @@ func (h *Handler) Submit(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-func (h *Handler) validateNextLevel(ctx context.Context, fresh *models.User) (int, error) {
+func respondSubmitError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, errLevelExceedsUnlock):
+		res.Fail(w, r, http.StatusBadRequest, err, "current level cannot exceed unlock level")
+	case errors.Is(err, errSessionNonceMismatch):
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid nonce")
+	case errors.Is(err, errSessionNotFound):
+		res.Fail(w, r, http.StatusNotFound, err, "play session not found")
+	case errors.Is(err, errSessionConsumed):
+		res.Fail(w, r, http.StatusConflict, err, "play session already used")
+	case errors.Is(err, errSessionLevelMismatch):
+		res.Fail(w, r, http.StatusConflict, err, "play session level mismatch")
+	case errors.Is(err, errSheetChanged):
+		res.Fail(w, r, http.StatusConflict, err, "level config changed")
+	case errors.Is(err, errSessionExpired):
+		res.Fail(w, r, http.StatusGone, err, "play session expired")
+	default:
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to validate submission")
+	}
+}
+
+func (h *Handler) loadPlaySession(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID string,
+	req submitRequest,
+	now time.Time,
+) (*models.PlaySession, error) {
+	session, err := h.Repo.GetPlaySessionForUpdate(ctx, tx, req.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errSessionNotFound
+		}
+		return nil, err
+	}
+	// Another user's session is reported as missing so session IDs cannot be probed.
+	if session.UserID != userID {
+		return nil, errSessionNotFound
+	}
+	if session.ConsumedAt != nil {
+		return nil, errSessionConsumed
+	}
+	if !now.Before(session.ExpiresAt) {
+		return nil, errSessionExpired
+	}
+	if subtle.ConstantTimeCompare([]byte(session.Nonce), []byte(req.Nonce)) != 1 {
+		return nil, errSessionNonceMismatch
+	}
+	return session, nil
+}
+
+// validateSessionLevel checks that the session targets the next level or an already cleared one.
+// advance reports whether an accepted run should move the player to the session level.
+func (h *Handler) validateSessionLevel(
+	ctx context.Context,
+	fresh *models.User,
+	session *models.PlaySession,
+) (models.LevelInfo, bool, error) {
 	_, span := h.tracer.Start(ctx, "game.submit.validate_level")
 	defer span.End()
 
-	newLevel := fresh.CurrentLevel + 1
+	nextLevel := fresh.CurrentLevel + 1
+	advance := session.Level == nextLevel
 	span.SetAttributes(
 		attribute.Int("game.current_level", fresh.CurrentLevel),
 		attribute.Int("game.unlock_level", fresh.UnlockLevel),
-		attribute.Int("game.next_level", newLevel),
+		attribute.Int("game.next_level", nextLevel),
+		attribute.Int("game.session_level", session.Level),
+		attribute.Bool("game.advance", advance),
 	)
 
-	if newLevel > fresh.UnlockLevel {
+	if session.Level < 1 || session.Level > nextLevel {
+		span.SetStatus(codes.Error, "session level mismatch")
+		return models.LevelInfo{}, false, errSessionLevelMismatch
+	}
+	if advance && nextLevel > fresh.UnlockLevel {
 		span.SetStatus(codes.Error, "level exceeds unlock")
-		return 0, errLevelExceedsUnlock
+		return models.LevelInfo{}, false, errLevelExceedsUnlock
 	}
 
-	levelCfg, found, err := config.LevelInfo(newLevel)
+	levelCfg, found, err := config.LevelInfo(session.Level)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "load level config failed")
-		return 0, err
+		return models.LevelInfo{}, false, err
 	}
 	if !found {
 		err = errors.New("level config not found")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "load level config failed")
-		return 0, err
+		return models.LevelInfo{}, false, err
+	}
+	if chartDigest(levelCfg.Chart) != session.SheetHash {
+		span.SetStatus(codes.Error, "level sheet changed")
+		return models.LevelInfo{}, false, errSheetChanged
 	}
 
-	requiredDuration := time.Duration(levelCfg.Notes*secondsPerMinute) * time.Second / time.Duration(levelCfg.Speed)
-	elapsed := time.Since(fresh.LastPassTime)
 	span.SetAttributes(
-		attribute.Int("game.next_level.speed", levelCfg.Speed),
-		attribute.Int("game.next_level.notes", levelCfg.Notes),
-		attribute.Int64("game.required_pass_ms", requiredDuration.Milliseconds()),
-		attribute.Int64("game.elapsed_since_last_pass_ms", elapsed.Milliseconds()),
+		attribute.Int("game.session_level.speed", levelCfg.Speed),
+		attribute.Int("game.session_level.notes", levelCfg.Notes),
+	)
+
+	return levelCfg, advance, nil
+}
+
+func (h *Handler) replay(
+	ctx context.Context,
+	levelCfg models.LevelInfo,
+	hits []hitEntry,
+	elapsed time.Duration,
+) (replayResult, error) {
+	_, span := h.tracer.Start(ctx, "game.submit.replay")
+	defer span.End()
+
+	result, err := replayHitLog(levelCfg, hits, elapsed)
+	span.SetAttributes(
+		attribute.Int("game.replay.hits", len(hits)),
+		attribute.Int64("game.replay.elapsed_ms", elapsed.Milliseconds()),
+		attribute.Int("game.replay.perfect", result.Perfect),
+		attribute.Int("game.replay.great", result.Great),
+		attribute.Int("game.replay.miss", result.Miss),
 	)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return result, err
+	}
+	return result, nil
+}
 
-	if elapsed < requiredDuration {
-		span.SetStatus(codes.Error, "submission too fast")
-		return 0, errSubmissionTooFast
+// rankOnOverallBoard ranks user's progress against the cached overall snapshot, so a submission
+// does not rank every user in the database. While the board is frozen the frozen rank is returned.
+// Only a cold cache falls back to the database, which sees this transaction's own writes.
+func (h *Handler) rankOnOverallBoard(ctx context.Context, tx pgx.Tx, user models.User) (int, error) {
+	if snap, ok := h.Leaderboard.Snapshot(repository.BoardOverall); ok {
+		if snap.FrozenAt != nil {
+			row, _ := snap.Find(user.ID)
+			return row.Rank, nil
+		}
+		return snap.ProgressRank(user), nil
 	}
 
-	return newLevel, nil
+	row, err := h.Repo.GetUserWithRank(ctx, tx, repository.BoardOverall, user.ID)
+	if err != nil {
+		return 0, err
+	}
+	if row == nil {
+		return 0, errors.New("user missing from overall board")
+	}
+	return row.Rank, nil
 }
 
-func (h *Handler) issueCoupons(ctx context.Context, tx pgx.Tx, userID string, newLevel int) ([]CouponResponse, error) {
+// recordRun stores a replayed run and reports whether it is a cleared run beating the previous personal best.
+func (h *Handler) recordRun(
+	ctx context.Context,
+	tx pgx.Tx,
+	session *models.PlaySession,
+	result replayResult,
+) (*models.LevelRun, bool, error) {
+	previous, err := h.Repo.GetPersonalBest(ctx, tx, session.UserID, session.Level)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, err
+	}
+
+	run := &models.LevelRun{
+		ID:        uuid.NewString(),
+		UserID:    session.UserID,
+		SessionID: session.ID,
+		Level:     session.Level,
+		Score:     result.score(),
+		Accuracy:  result.accuracy(),
+		MaxCombo:  result.MaxCombo,
+		Perfect:   result.Perfect,
+		Great:     result.Great,
+		Miss:      result.Miss,
+		Cleared:   result.cleared(),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err = h.Repo.InsertLevelRun(ctx, tx, run); err != nil {
+		return nil, false, err
+	}
+
+	return run, run.Cleared && (previous == nil || run.Score > previous.Score), nil
+}
+
+func (h *Handler) storeRejection(
+	ctx context.Context,
+	tx pgx.Tx,
+	session *models.PlaySession,
+	hits []hitEntry,
+	reason error,
+) error {
+	hitLog, err := json.Marshal(hits)
+	if err != nil {
+		return err
+	}
+
+	return h.Repo.InsertPlayRejection(ctx, tx, &models.PlayRejection{
+		ID:        uuid.NewString(),
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		Level:     session.Level,
+		Reason:    reason.Error(),
+		HitLog:    hitLog,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+func (h *Handler) issueCoupons(ctx context.Context, tx pgx.Tx, userID string) ([]CouponResponse, error) {
 	spanCtx, span := h.tracer.Start(ctx, "game.submit.issue_coupons")
 	defer span.End()
 
-	if config.IsCouponEarningStopped(time.Now().UTC()) {
-		span.SetAttributes(attribute.Int("game.coupons.issued_count", 0))
-		return []CouponResponse{}, nil
+	coupons, err := h.Coupons.Evaluate(spanCtx, tx, userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "issue coupon failed")
+		return nil, err
 	}
 
-	issued := []CouponResponse{}
-	for _, rule := range config.GetCouponRulesByLevel(newLevel) {
-		coupon, created, err := h.Repo.CreateDiscountCoupon(
-			spanCtx,
-			tx,
-			userID,
-			rule.Amount,
-			rule.ID,
-		)
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "issue coupon failed")
-			return nil, err
-		}
-		if !created {
-			continue
-		}
-
+	issued := make([]CouponResponse, 0, len(coupons))
+	for _, coupon := range coupons {
 		issued = append(issued, CouponResponse{
 			ID:         coupon.ID,
 			Price:      coupon.Price,
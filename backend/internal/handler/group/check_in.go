@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/sitcon-tw/2026-game/internal/models"
-	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/notifications"
+	"github.com/sitcon-tw/2026-game/internal/service/qrtoken"
 	"github.com/sitcon-tw/2026-game/pkg/helpers"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
@@ -30,7 +30,7 @@ const unlockIncrementGroupCheckIn = 2
 
 // CheckIn handles POST /group/check-ins.
 // @Summary      group 互相簽到
-// @Description  掃描同 group 成員的 one-time QR code，雙方各增加 2 次 unlock_level。每對只能簽到一次。
+// @Description  掃描同 group 成員以 purpose=group_check_in 取得的 one-time QR code（每個 QR code 只能使用一次），雙方各增加 2 次 unlock_level。每對只能簽到一次。
 // @Tags         group
 // @Accept       json
 // @Produce      json
@@ -39,7 +39,7 @@ const unlockIncrementGroupCheckIn = 2
 // @Failure      400  {object}  res.ErrorResponse
 // @Failure      401  {object}  res.ErrorResponse "unauthorized"
 // @Failure      403  {object}  res.ErrorResponse "not in group / not same group"
-// @Failure      409  {object}  res.ErrorResponse "already checked in"
+// @Failure      409  {object}  res.ErrorResponse "already checked in | qr code already used"
 // @Failure      500  {object}  res.ErrorResponse
 // @Router       /group/check-ins [post]
 func (h *Handler) CheckIn(w http.ResponseWriter, r *http.Request) {
@@ -83,37 +83,19 @@ func (h *Handler) checkIn(ctx context.Context, currentUser *models.User, userQRC
 	}
 	defer h.Repo.DeferRollback(ctx, tx)
 
-	// Resolve target user via one-time QR token, using the open tx for DB lookups.
-	targetUserID, err := helpers.VerifyAndExtractUserIDFromOneTimeQRToken(
-		userQRCode,
-		time.Now().UTC(),
-		func(userID string) (string, error) {
-			u, lookupErr := h.Repo.GetUserByID(ctx, tx, userID)
-			if lookupErr != nil {
-				if errors.Is(lookupErr, repository.ErrNotFound) {
-					return "", errTargetUserNotFound
-				}
-				return "", lookupErr
-			}
-			return u.QRCodeToken, nil
-		},
-	)
+	// Resolve and consume the target's one-time QR token, using the open tx for DB lookups.
+	targetUser, err := qrtoken.Consume(ctx, h.Repo, tx, userQRCode, helpers.QRPurposeGroupCheckIn)
 	if err != nil {
-		return errTargetUserNotFound
-	}
-
-	if targetUserID == currentUser.ID {
-		return errCannotCheckInSelf
-	}
-
-	targetUser, err := h.Repo.GetUserByID(ctx, tx, targetUserID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
+		if errors.Is(err, qrtoken.ErrUserNotFound) {
 			return errTargetUserNotFound
 		}
 		return err
 	}
 
+	if targetUser.ID == currentUser.ID {
+		return errCannotCheckInSelf
+	}
+
 	// Verify target is in the same group.
 	if targetUser.Group == nil || *targetUser.Group != *currentUser.Group {
 		return errNotInSameGroup
@@ -136,6 +118,16 @@ func (h *Handler) checkIn(ctx context.Context, currentUser *models.User, userQRC
 		return err
 	}
 
+	for _, userID := range []string{currentUser.ID, targetUser.ID} {
+		if _, err = h.Coupons.Evaluate(ctx, tx, userID); err != nil {
+			return err
+		}
+	}
+
+	if err = notifications.GroupCheckIn(ctx, h.Repo, tx, targetUser.ID, currentUser); err != nil {
+		return err
+	}
+
 	return h.Repo.CommitTransaction(ctx, tx)
 }
 
@@ -151,6 +143,10 @@ func (h *Handler) respondCheckInError(w http.ResponseWriter, r *http.Request, er
 		res.Fail(w, r, http.StatusForbidden, err, "not in the same group")
 	case errors.Is(err, errAlreadyCheckedIn):
 		res.Fail(w, r, http.StatusConflict, err, "already checked in with this member")
+	case errors.Is(err, qrtoken.ErrInvalidToken):
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid qr code")
+	case errors.Is(err, qrtoken.ErrTokenUsed):
+		res.Fail(w, r, http.StatusConflict, err, "qr code already used")
 	default:
 		res.Fail(w, r, http.StatusInternalServerError, err, "failed to check in")
 	}
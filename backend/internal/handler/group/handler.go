@@ -2,6 +2,7 @@ package group
 
 import (
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/couponrules"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -9,16 +10,18 @@ import (
 
 // Handler handles group-related requests.
 type Handler struct {
-	Repo   repository.Repository
-	Logger *zap.Logger
-	tracer trace.Tracer
+	Repo    repository.Repository
+	Logger  *zap.Logger
+	Coupons *couponrules.Engine
+	tracer  trace.Tracer
 }
 
 // New wires required dependencies for the group handler.
 func New(repo repository.Repository, logger *zap.Logger) *Handler {
 	return &Handler{
-		Repo:   repo,
-		Logger: logger,
-		tracer: otel.Tracer("github.com/sitcon-tw/2026-game/group"),
+		Repo:    repo,
+		Logger:  logger,
+		Coupons: couponrules.New(repo),
+		tracer:  otel.Tracer("github.com/sitcon-tw/2026-game/group"),
 	}
 }
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/service/gameconfig"
+)
+
+const (
+	statusOK       = "ok"
+	statusDegraded = "degraded"
+)
+
+// Response is returned by GET /health.
+type Response struct {
+	// Status is degraded while the game config is a fallback; the API still serves requests.
+	Status     string           `json:"status"`
+	GameConfig GameConfigStatus `json:"game_config"`
+}
+
+// GameConfigStatus is the public part of gameconfig.Status. The last sync error can carry database
+// details, so it is only logged and shown under GET /admin/game-config/status.
+type GameConfigStatus struct {
+	Version    int                   `json:"version"`
+	LoadedFrom gameconfig.LoadedFrom `json:"loaded_from"`
+	Fallback   bool                  `json:"fallback"`
+	LoadedAt   time.Time             `json:"loaded_at"`
+}
+
+// Get handles GET /health.
+// @Summary      健康檢查
+// @Description  回傳此實例的狀態與目前提供的關卡設定來源。game_config.loaded_from 為 database（正常）、cache（上次成功載入的本機快取）或 embedded（內建預設關卡）；使用後兩者時 status 為 degraded，但 API 仍可正常遊玩，服務會持續重試資料庫。錯誤原因不對外公開，請查看 log 或 GET /admin/game-config/status。
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  Response
+// @Router       /health [get]
+func (h *Handler) Get(w http.ResponseWriter, _ *http.Request) {
+	status := h.GameConfig.Status()
+	resp := Response{
+		Status: statusOK,
+		GameConfig: GameConfigStatus{
+			Version:    status.Version,
+			LoadedFrom: status.LoadedFrom,
+			Fallback:   status.Fallback,
+			LoadedAt:   status.LoadedAt,
+		},
+	}
+	if status.Fallback {
+		resp.Status = statusDegraded
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,17 @@
+package health
+
+import (
+	"github.com/sitcon-tw/2026-game/internal/service/gameconfig"
+	"go.uber.org/zap"
+)
+
+// Handler handles health checks.
+type Handler struct {
+	Logger     *zap.Logger
+	GameConfig *gameconfig.Service
+}
+
+// New wires required dependencies for the health handler.
+func New(logger *zap.Logger, gameConfig *gameconfig.Service) *Handler {
+	return &Handler{Logger: logger, GameConfig: gameConfig}
+}
@@ -0,0 +1,164 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+)
+
+const (
+	defaultNotificationLimit = 50
+	maxNotificationLimit     = 200
+	maxMarkReadIDs           = 200
+)
+
+type notificationsResponse struct {
+	UnreadCount int                   `json:"unread_count"`
+	Items       []models.Notification `json:"items"`
+}
+
+type markNotificationsReadRequest struct {
+	IDs []string `json:"ids"`
+	All bool     `json:"all"`
+}
+
+type markNotificationsReadResponse struct {
+	Marked      int64 `json:"marked"`
+	UnreadCount int   `json:"unread_count"`
+}
+
+// Notifications godoc
+// @Summary      取得通知
+// @Description  回傳目前使用者的通知（最新的在前）與未讀數量。kind 為 coupon_issued（收到折扣券，data.source 為 rule、settlement、gift、staff_scan 或 admin）、friend_added（有人加你為好友）或 group_check_in（group 成員與你簽到）。通知保留至活動結束（NOTIFICATION_RETENTION）。需要登入。
+// @Tags         users
+// @Produce      json
+// @Param        limit  query     int  false  "最多回傳筆數，預設 50，上限 200"
+// @Success      200  {object}  notificationsResponse
+// @Failure      400  {object}  res.ErrorResponse "invalid limit"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /users/me/notifications [get]
+func (h *Handler) Notifications(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok || user == nil {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("unauthorized"), "unauthorized")
+		return
+	}
+
+	limit := defaultNotificationLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			res.Fail(w, r, http.StatusBadRequest, errors.New("invalid limit"), "invalid limit")
+			return
+		}
+		limit = min(n, maxNotificationLimit)
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	items, err := h.Repo.ListNotifications(r.Context(), tx, user.ID, limit)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to list notifications")
+		return
+	}
+	unread, err := h.Repo.CountUnreadNotifications(r.Context(), tx, user.ID)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to count unread notifications")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(notificationsResponse{UnreadCount: unread, Items: items})
+}
+
+// MarkNotificationsRead godoc
+// @Summary      將通知標為已讀
+// @Description  將 ids 中屬於目前使用者的通知標為已讀；all 為 true 時標記全部通知。ids 與 all 須擇一。回傳標記筆數與剩餘未讀數量。需要登入。
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      markNotificationsReadRequest  true  "Notification ids or all"
+// @Success      200  {object}  markNotificationsReadResponse
+// @Failure      400  {object}  res.ErrorResponse "invalid request"
+// @Failure      401  {object}  res.ErrorResponse "unauthorized"
+// @Failure      500  {object}  res.ErrorResponse
+// @Router       /users/me/notifications/read [post]
+func (h *Handler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok || user == nil {
+		res.Fail(w, r, http.StatusUnauthorized, errors.New("unauthorized"), "unauthorized")
+		return
+	}
+
+	var req markNotificationsReadRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		res.Fail(w, r, http.StatusBadRequest, err, "invalid request body")
+		return
+	}
+	if req.All == (len(req.IDs) > 0) {
+		res.Fail(w, r, http.StatusBadRequest, errors.New("either ids or all is required"), "invalid request")
+		return
+	}
+	if len(req.IDs) > maxMarkReadIDs {
+		res.Fail(w, r, http.StatusBadRequest, errors.New("too many ids"), "invalid request")
+		return
+	}
+	for _, id := range req.IDs {
+		if _, err := uuid.Parse(id); err != nil {
+			res.Fail(w, r, http.StatusBadRequest, err, "invalid notification id")
+			return
+		}
+	}
+	var ids []string
+	if !req.All {
+		ids = req.IDs
+	}
+
+	tx, err := h.Repo.StartTransaction(r.Context())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to start transaction")
+		return
+	}
+	defer h.Repo.DeferRollback(r.Context(), tx)
+
+	marked, err := h.Repo.MarkNotificationsRead(r.Context(), tx, user.ID, ids, time.Now().UTC())
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to mark notifications read")
+		return
+	}
+	unread, err := h.Repo.CountUnreadNotifications(r.Context(), tx, user.ID)
+	if err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to count unread notifications")
+		return
+	}
+
+	if err = h.Repo.CommitTransaction(r.Context(), tx); err != nil {
+		res.Fail(w, r, http.StatusInternalServerError, err, "failed to commit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(markNotificationsReadResponse{Marked: marked, UnreadCount: unread})
+}
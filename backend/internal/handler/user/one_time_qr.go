@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/sitcon-tw/2026-game/pkg/config"
 	"github.com/sitcon-tw/2026-game/pkg/helpers"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
@@ -19,11 +20,14 @@ type oneTimeQRResponse struct {
 
 // OneTimeQR godoc
 // @Summary      取得一次性 QR token
-// @Description  取得可供掃描使用的一次性 QR token。每 20 秒輪替，後端驗證時允許極小時間誤差。
+// @Description  取得可供掃描使用的一次性 QR token（qru2 開頭）。purpose 決定 token 可用於哪個掃碼流程：friend（加好友）、group_check_in（group 簽到）、booth_check_in（攤位打卡）、coupon_assignment（工作人員掃碼發券）。每個 token 在同一用途只能使用一次，有效期約 20 秒，後端驗證時允許極小時間誤差。過渡期間（LEGACY_USER_QR_UNTIL 之前；非 dev 環境必須設定，dev 未設定則不限）未帶 purpose 時回傳舊格式的 qru1 token，可用於所有掃碼流程；過渡期結束後 purpose 為必填。
 // @Tags         users
 // @Produce      json
+// @Param        purpose  query     string  false  "friend | group_check_in | booth_check_in | coupon_assignment"
 // @Success      200  {object}  oneTimeQRResponse
+// @Failure      400  {object}  res.ErrorResponse "invalid purpose"
 // @Failure      401  {object}  res.ErrorResponse
+// @Failure      500  {object}  res.ErrorResponse
 // @Router       /users/me/one-time-qr [get]
 func (h *Handler) OneTimeQR(w http.ResponseWriter, r *http.Request) {
 	user, ok := middleware.UserFromContext(r.Context())
@@ -35,7 +39,52 @@ func (h *Handler) OneTimeQR(w http.ResponseWriter, r *http.Request) {
 
 	now := time.Now().UTC()
 	resp := oneTimeQRResponse{
-		Token:     helpers.BuildUserOneTimeQRToken(user.ID, user.QRCodeToken, now),
+		ExpiresAt: helpers.QRTokenExpiry(now),
+		TTL:       int(helpers.QRTokenTTL().Seconds()),
+	}
+
+	rawPurpose := r.URL.Query().Get("purpose")
+	if rawPurpose == "" && config.IsLegacyUserQRAccepted(now) {
+		// Clients that do not send a purpose yet get the old token, valid for every scan flow.
+		resp.Token = helpers.BuildLegacyUserQRToken(user.ID, user.QRCodeToken, now)
+	} else {
+		purpose, valid := helpers.ParseQRPurpose(rawPurpose)
+		if !valid {
+			res.Fail(w, r, http.StatusBadRequest, errors.New("invalid purpose"), "invalid purpose")
+			return
+		}
+		nonce, err := helpers.NewQRNonce()
+		if err != nil {
+			res.Fail(w, r, http.StatusInternalServerError, err, "failed to generate qr token")
+			return
+		}
+		resp.Token = helpers.BuildUserOneTimeQRToken(user.ID, config.Env().UserQRSigningKey(), purpose, nonce, now)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// CouponQR godoc
+// @Summary      取得折扣券 QR token
+// @Description  取得核銷折扣券用的限時 QR token（qrc1 開頭），每 20 秒輪替，只能用於工作人員核銷或查詢折扣券，不能用於加好友等其他掃碼流程。
+// @Tags         users
+// @Produce      json
+// @Success      200  {object}  oneTimeQRResponse
+// @Failure      401  {object}  res.ErrorResponse
+// @Router       /users/me/coupon-qr [get]
+func (h *Handler) CouponQR(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok || user == nil {
+		err := errors.New("unauthorized")
+		res.Fail(w, r, http.StatusUnauthorized, err, "Unauthorized")
+		return
+	}
+
+	now := time.Now().UTC()
+	resp := oneTimeQRResponse{
+		Token:     helpers.BuildUserCouponQRToken(user.ID, user.QRCodeToken, now),
 		ExpiresAt: helpers.QRTokenExpiry(now),
 		TTL:       int(helpers.QRTokenTTL().Seconds()),
 	}
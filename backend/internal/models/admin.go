@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// AdminRole groups the permissions an admin account holds.
+type AdminRole string
+
+const (
+	// AdminRoleSuperadmin can do everything, including managing other admins.
+	AdminRoleSuperadmin AdminRole = "superadmin"
+	// AdminRoleCouponManager manages gift coupons, coupon rules and leaderboard settlement.
+	AdminRoleCouponManager AdminRole = "coupon-manager"
+	// AdminRoleViewer can only read.
+	AdminRoleViewer AdminRole = "viewer"
+	// AdminRoleSupport helps players at the counter: voids redemptions and posts announcements.
+	AdminRoleSupport AdminRole = "support"
+)
+
+// AdminPermission is what an admin route requires.
+type AdminPermission string
+
+const (
+	// AdminPermissionRead reads admin data that holds no login or redemption secrets.
+	AdminPermissionRead AdminPermission = "read"
+	// AdminPermissionManageCoupons issues coupons and edits gift coupons, coupon rules and settlements.
+	AdminPermissionManageCoupons AdminPermission = "coupons.manage"
+	// AdminPermissionVoidRedemptions voids coupon redemptions.
+	AdminPermissionVoidRedemptions AdminPermission = "redemptions.void"
+	// AdminPermissionManageAnnouncements edits announcements.
+	AdminPermissionManageAnnouncements AdminPermission = "announcements.manage"
+	// AdminPermissionManageEvent manages activities, staffs (including their tokens), scheduled jobs
+	// and the level config.
+	AdminPermissionManageEvent AdminPermission = "event.manage"
+	// AdminPermissionManageAdmins manages admin accounts.
+	AdminPermissionManageAdmins AdminPermission = "admins.manage"
+	// AdminPermissionReadAudit reads and exports the audit log.
+	AdminPermissionReadAudit AdminPermission = "audit.read"
+)
+
+//nolint:gochecknoglobals // static role table
+var adminRolePermissions = map[AdminRole][]AdminPermission{
+	AdminRoleSuperadmin: {
+		AdminPermissionRead,
+		AdminPermissionManageCoupons,
+		AdminPermissionVoidRedemptions,
+		AdminPermissionManageAnnouncements,
+		AdminPermissionManageEvent,
+		AdminPermissionManageAdmins,
+		AdminPermissionReadAudit,
+	},
+	AdminRoleCouponManager: {
+		AdminPermissionRead,
+		AdminPermissionManageCoupons,
+		AdminPermissionVoidRedemptions,
+	},
+	AdminRoleViewer: {
+		AdminPermissionRead,
+	},
+	AdminRoleSupport: {
+		AdminPermissionRead,
+		AdminPermissionVoidRedemptions,
+		AdminPermissionManageAnnouncements,
+	},
+}
+
+// Valid reports whether r is a known role.
+func (r AdminRole) Valid() bool {
+	_, ok := adminRolePermissions[r]
+	return ok
+}
+
+// Permissions returns the permissions granted to r. Unknown roles get none.
+func (r AdminRole) Permissions() []AdminPermission {
+	return append([]AdminPermission(nil), adminRolePermissions[r]...)
+}
+
+// Can reports whether r grants p.
+func (r AdminRole) Can(p AdminPermission) bool {
+	for _, granted := range adminRolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Admin mirrors the admins table. KeyHash is the SHA-256 of the admin's login key and never leaves the server.
+//
+//nolint:golines // keep struct tags aligned
+type Admin struct {
+	ID        string    `db:"id" json:"id"`
+	Username  string    `db:"username" json:"username"`
+	Role      AdminRole `db:"role" json:"role"`
+	KeyHash   string    `db:"key_hash" json:"-"`
+	Disabled  bool      `db:"disabled" json:"disabled"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+func TestAdminRoleCan(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		role models.AdminRole
+		perm models.AdminPermission
+		want bool
+	}{
+		{models.AdminRoleSuperadmin, models.AdminPermissionManageAdmins, true},
+		{models.AdminRoleSuperadmin, models.AdminPermissionReadAudit, true},
+		{models.AdminRoleCouponManager, models.AdminPermissionReadAudit, false},
+		{models.AdminRoleCouponManager, models.AdminPermissionManageCoupons, true},
+		{models.AdminRoleCouponManager, models.AdminPermissionManageEvent, false},
+		{models.AdminRoleViewer, models.AdminPermissionRead, true},
+		{models.AdminRoleViewer, models.AdminPermissionVoidRedemptions, false},
+		{models.AdminRoleSupport, models.AdminPermissionVoidRedemptions, true},
+		{models.AdminRoleSupport, models.AdminPermissionManageCoupons, false},
+		{models.AdminRole("root"), models.AdminPermissionRead, false},
+	}
+	for _, tc := range cases {
+		if got := tc.role.Can(tc.perm); got != tc.want {
+			t.Errorf("%s.Can(%s) = %v, want %v", tc.role, tc.perm, got, tc.want)
+		}
+	}
+}
+
+func TestAdminRoleValid(t *testing.T) {
+	t.Parallel()
+
+	for _, role := range []models.AdminRole{
+		models.AdminRoleSuperadmin,
+		models.AdminRoleCouponManager,
+		models.AdminRoleViewer,
+		models.AdminRoleSupport,
+	} {
+		if !role.Valid() {
+			t.Errorf("%s should be valid", role)
+		}
+	}
+	if models.AdminRole("coupon_manager").Valid() {
+		t.Error("coupon_manager should not be valid")
+	}
+}
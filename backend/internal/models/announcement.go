@@ -1,12 +1,110 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
-// Announcement mirrors the announcements table.
+// AnnouncementSeverity is how prominently clients should show an announcement.
+type AnnouncementSeverity string
+
+const (
+	// AnnouncementSeverityInfo is a regular announcement.
+	AnnouncementSeverityInfo AnnouncementSeverity = "info"
+	// AnnouncementSeverityWarning should stand out from regular announcements.
+	AnnouncementSeverityWarning AnnouncementSeverity = "warning"
+	// AnnouncementSeverityCritical needs the player's attention right away.
+	AnnouncementSeverityCritical AnnouncementSeverity = "critical"
+)
+
+// AnnouncementAudience selects who sees an announcement.
+type AnnouncementAudience string
+
+const (
+	// AnnouncementAudienceAll is shown to everyone, including visitors who are not logged in.
+	AnnouncementAudienceAll AnnouncementAudience = "all"
+	// AnnouncementAudienceGroup is shown to members of AudienceGroup.
+	AnnouncementAudienceGroup AnnouncementAudience = "group"
+	// AnnouncementAudienceMinLevel is shown to users whose current_level is at least AudienceMinLevel.
+	AnnouncementAudienceMinLevel AnnouncementAudience = "min_level"
+)
+
+// AnnouncementLocalization is the title and content of an announcement in one locale.
+type AnnouncementLocalization struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Announcement mirrors the announcements table. Title and Content are the default-locale text;
+// Localizations holds variants keyed by locale (e.g. "en", "zh-TW").
 //
 //nolint:golines // Struct tags are kept on one line for readability and consistency across models.
 type Announcement struct {
-	ID        string    `db:"id" json:"id"`
-	Content   string    `db:"content" json:"content"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ID               string                              `db:"id" json:"id"`
+	Title            string                              `db:"title" json:"title"`
+	Content          string                              `db:"content" json:"content"`
+	Localizations    map[string]AnnouncementLocalization `db:"localizations" json:"localizations,omitempty"`
+	Severity         AnnouncementSeverity                `db:"severity" json:"severity"`
+	Pinned           bool                                `db:"pinned" json:"pinned"`
+	Audience         AnnouncementAudience                `db:"audience" json:"audience"`
+	AudienceGroup    *string                             `db:"audience_group" json:"audience_group,omitempty"`
+	AudienceMinLevel *int                                `db:"audience_min_level" json:"audience_min_level,omitempty"`
+	PublishAt        *time.Time                          `db:"publish_at" json:"publish_at,omitempty"`
+	ExpireAt         *time.Time                          `db:"expire_at" json:"expire_at,omitempty"`
+	CreatedAt        time.Time                           `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time                           `db:"updated_at" json:"updated_at"`
+}
+
+// PublishedAt returns when the announcement became visible.
+func (a Announcement) PublishedAt() time.Time {
+	if a.PublishAt != nil {
+		return *a.PublishAt
+	}
+	return a.CreatedAt
+}
+
+// LiveAt reports whether now is within the publish/expire window.
+func (a Announcement) LiveAt(now time.Time) bool {
+	if a.PublishAt != nil && now.Before(*a.PublishAt) {
+		return false
+	}
+	if a.ExpireAt != nil && !now.Before(*a.ExpireAt) {
+		return false
+	}
+	return true
+}
+
+// VisibleTo reports whether user is in the audience. A nil user (not logged in) only sees
+// announcements for everyone.
+func (a Announcement) VisibleTo(user *User) bool {
+	switch a.Audience {
+	case AnnouncementAudienceAll:
+		return true
+	case AnnouncementAudienceGroup:
+		return user != nil && user.Group != nil && a.AudienceGroup != nil && *user.Group == *a.AudienceGroup
+	case AnnouncementAudienceMinLevel:
+		return user != nil && a.AudienceMinLevel != nil && user.CurrentLevel >= *a.AudienceMinLevel
+	default:
+		return false
+	}
+}
+
+// Localized returns the title and content for locale. It tries an exact match, then the
+// language without region ("zh-TW" -> "zh"), then falls back to the default text.
+func (a Announcement) Localized(locale string) (string, string) {
+	candidates := []string{locale}
+	if lang, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, lang)
+	}
+	for _, key := range candidates {
+		if key == "" {
+			continue
+		}
+		for k, l := range a.Localizations {
+			if strings.EqualFold(k, key) {
+				return l.Title, l.Content
+			}
+		}
+	}
+	return a.Title, a.Content
 }
@@ -0,0 +1,61 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+func TestAnnouncementVisibleTo(t *testing.T) {
+	t.Parallel()
+
+	group := "A"
+	otherGroup := "B"
+	minLevel := 10
+	byGroup := models.Announcement{Audience: models.AnnouncementAudienceGroup, AudienceGroup: &group}
+	byLevel := models.Announcement{Audience: models.AnnouncementAudienceMinLevel, AudienceMinLevel: &minLevel}
+
+	if !(models.Announcement{Audience: models.AnnouncementAudienceAll}).VisibleTo(nil) {
+		t.Fatal("everyone announcement must be visible without login")
+	}
+	if byGroup.VisibleTo(nil) || byLevel.VisibleTo(nil) {
+		t.Fatal("targeted announcements must not be visible without login")
+	}
+	if !byGroup.VisibleTo(&models.User{Group: &group}) || byGroup.VisibleTo(&models.User{Group: &otherGroup}) {
+		t.Fatal("group announcement must only be visible to its group")
+	}
+	if !byLevel.VisibleTo(&models.User{CurrentLevel: 10}) || byLevel.VisibleTo(&models.User{CurrentLevel: 9}) {
+		t.Fatal("min_level announcement must only be visible from audience_min_level")
+	}
+}
+
+func TestAnnouncementLiveAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	a := models.Announcement{PublishAt: &now, ExpireAt: &later}
+
+	if a.LiveAt(now.Add(-time.Second)) || !a.LiveAt(now) || a.LiveAt(later) {
+		t.Fatal("announcement must be live from publish_at until expire_at")
+	}
+}
+
+func TestAnnouncementLocalized(t *testing.T) {
+	t.Parallel()
+
+	a := models.Announcement{
+		Title:   "公告",
+		Content: "內容",
+		Localizations: map[string]models.AnnouncementLocalization{
+			"en": {Title: "Notice", Content: "Body"},
+		},
+	}
+
+	for locale, want := range map[string]string{"en": "Body", "en-US": "Body", "EN": "Body", "ja": "內容", "": "內容"} {
+		if _, content := a.Localized(locale); content != want {
+			t.Fatalf("Localized(%q) content = %q, want %q", locale, content, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditActorType says which kind of session performed an audited action.
+type AuditActorType string
+
+const (
+	// AuditActorAdmin is an admin account.
+	AuditActorAdmin AuditActorType = "admin"
+	// AuditActorStaff is a staff member.
+	AuditActorStaff AuditActorType = "staff"
+	// AuditActorBooth is a booth or challenge activity.
+	AuditActorBooth AuditActorType = "booth"
+)
+
+// AuditEvent mirrors the append-only audit_events table.
+//
+//nolint:golines // keep struct tags aligned
+type AuditEvent struct {
+	ID         string          `db:"id" json:"id"`
+	ActorType  AuditActorType  `db:"actor_type" json:"actor_type"`
+	ActorID    string          `db:"actor_id" json:"actor_id"`
+	ActorName  string          `db:"actor_name" json:"actor_name"`
+	Action     string          `db:"action" json:"action"`
+	TargetType string          `db:"target_type" json:"target_type"`
+	TargetID   string          `db:"target_id" json:"target_id"`
+	Before     json.RawMessage `db:"before" json:"before,omitempty"`
+	After      json.RawMessage `db:"after" json:"after,omitempty"`
+	RequestID  string          `db:"request_id" json:"request_id"`
+	TraceID    string          `db:"trace_id" json:"trace_id"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+}
@@ -3,14 +3,53 @@ package models
 import "time"
 
 // CouponHistory records a redemption event for one or more coupons (table: coupon_histories).
+// Void is set when the redemption was reversed; Total still holds the original amount.
 //
 //nolint:golines // keep tags aligned
 type CouponHistory struct {
+	ID        string             `db:"id" json:"id"`
+	UserID    string             `db:"user_id" json:"user_id"`
+	Nickname  string             `db:"nickname" json:"nickname"`
+	StaffID   string             `db:"staff_id" json:"staff_id"`
+	Total     int                `db:"total" json:"total"`
+	UsedAt    time.Time          `db:"used_at" json:"used_at"`
+	CreatedAt time.Time          `db:"created_at" json:"created_at"`
+	Void      *CouponHistoryVoid `db:"-" json:"void,omitempty"`
+}
+
+// NetTotal is the amount still redeemed after any void.
+func (h CouponHistory) NetTotal() int {
+	if h.Void != nil {
+		return 0
+	}
+	return h.Total
+}
+
+// Void actors.
+const (
+	VoidActorStaff = "staff"
+	VoidActorAdmin = "admin"
+)
+
+// CouponHistoryVoid mirrors the coupon_history_voids table. StaffID is nil when an admin voided.
+//
+//nolint:golines // keep tags aligned
+type CouponHistoryVoid struct {
 	ID        string    `db:"id" json:"id"`
-	UserID    string    `db:"user_id" json:"user_id"`
-	Nickname  string    `db:"nickname" json:"nickname"`
-	StaffID   string    `db:"staff_id" json:"staff_id"`
+	HistoryID string    `db:"history_id" json:"history_id"`
+	Actor     string    `db:"actor" json:"actor"`
+	StaffID   *string   `db:"staff_id" json:"staff_id"`
+	Reason    string    `db:"reason" json:"reason"`
 	Total     int       `db:"total" json:"total"`
-	UsedAt    time.Time `db:"used_at" json:"used_at"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	CouponIDs []string  `db:"coupon_ids" json:"coupon_ids"`
+	VoidedAt  time.Time `db:"voided_at" json:"voided_at"`
+}
+
+// CouponHistoryTotals sums a set of redemptions, netting out voided ones.
+type CouponHistoryTotals struct {
+	Count       int `json:"count"`
+	VoidedCount int `json:"voided_count"`
+	GrossTotal  int `json:"gross_total"`
+	VoidedTotal int `json:"voided_total"`
+	NetTotal    int `json:"net_total"`
 }
@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// CouponRuleTrigger is the kind of progress a coupon rule watches.
+type CouponRuleTrigger string
+
+const (
+	// CouponTriggerLevel fires once current_level reaches Threshold.
+	CouponTriggerLevel CouponRuleTrigger = "level"
+	// CouponTriggerActivityVisits fires once the user visited Threshold activities of ActivityTypes (any type when empty).
+	CouponTriggerActivityVisits CouponRuleTrigger = "activity_visits"
+	// CouponTriggerActivity fires once the user visited ActivityID.
+	CouponTriggerActivity CouponRuleTrigger = "activity"
+	// CouponTriggerFriends fires once the user has Threshold friends.
+	CouponTriggerFriends CouponRuleTrigger = "friends"
+	// CouponTriggerLeaderboardRank is issued by leaderboard settlement to ranks within Threshold.
+	CouponTriggerLeaderboardRank CouponRuleTrigger = "leaderboard_rank"
+	// CouponTriggerGroupCheckIns fires once the user has Threshold group check-ins.
+	CouponTriggerGroupCheckIns CouponRuleTrigger = "group_check_ins"
+	// CouponTriggerManual is only issued by staff.
+	CouponTriggerManual CouponRuleTrigger = "manual"
+)
+
+// CouponRule mirrors the coupon_rules table. ID doubles as the discount_id of issued coupons.
+// StockLimit caps how many coupons are issued in total and Budget caps their summed NT$ value;
+// nil means unlimited. IssuedCount and IssuedAmount include unredeemed gifts.
+//
+//nolint:golines // keep struct tags aligned
+type CouponRule struct {
+	ID            string            `db:"id" json:"id"`
+	Trigger       CouponRuleTrigger `db:"trigger" json:"trigger"`
+	Threshold     int               `db:"threshold" json:"threshold"`
+	ActivityTypes []ActivitiesTypes `db:"activity_types" json:"activity_types,omitempty"`
+	ActivityID    *string           `db:"activity_id" json:"activity_id,omitempty"`
+	Amount        int               `db:"amount" json:"amount"`
+	StockLimit    *int              `db:"stock_limit" json:"stock_limit"`
+	Budget        *int              `db:"budget" json:"budget"`
+	IssuedCount   int               `db:"issued_count" json:"issued_count"`
+	IssuedAmount  int               `db:"issued_amount" json:"issued_amount"`
+	ValidFrom     *time.Time        `db:"valid_from" json:"valid_from"`
+	ValidUntil    *time.Time        `db:"valid_until" json:"valid_until"`
+	Description   string            `db:"description" json:"description"`
+	SortOrder     int               `db:"sort_order" json:"sort_order"`
+	Enabled       bool              `db:"enabled" json:"enabled"`
+	CreatedAt     time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time         `db:"updated_at" json:"updated_at"`
+}
+
+// ActiveAt reports whether the rule is enabled and within its validity window at now.
+func (r CouponRule) ActiveAt(now time.Time) bool {
+	if !r.Enabled {
+		return false
+	}
+	if r.ValidFrom != nil && now.Before(*r.ValidFrom) {
+		return false
+	}
+	if r.ValidUntil != nil && !now.Before(*r.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// RemainingStock returns how many more coupons can be issued, or nil when unlimited.
+// A budget that cannot cover one more coupon of Amount also counts as exhausted.
+func (r CouponRule) RemainingStock() *int {
+	var remaining *int
+	if r.StockLimit != nil {
+		n := max(*r.StockLimit-r.IssuedCount, 0)
+		remaining = &n
+	}
+	if r.Budget != nil && r.Amount > 0 {
+		n := max((*r.Budget-r.IssuedAmount)/r.Amount, 0)
+		if remaining == nil || n < *remaining {
+			remaining = &n
+		}
+	}
+	return remaining
+}
+
+// RemainingBudget returns the unreserved NT$ budget, or nil when unlimited.
+func (r CouponRule) RemainingBudget() *int {
+	if r.Budget == nil {
+		return nil
+	}
+	n := max(*r.Budget-r.IssuedAmount, 0)
+	return &n
+}
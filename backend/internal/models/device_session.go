@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// DeviceSession is a staff or booth login session. OwnerID is the staff ID for staff sessions
+// and the activity ID for booth sessions. The session token itself is never stored or returned.
+//
+//nolint:golines // keep struct tags aligned
+type DeviceSession struct {
+	ID          string    `db:"id" json:"id"`
+	OwnerID     string    `db:"owner_id" json:"owner_id"`
+	DeviceLabel string    `db:"device_label" json:"device_label"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	LastSeenAt  time.Time `db:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+}
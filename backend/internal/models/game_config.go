@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// GameConfigSource says where a game config version was imported from.
+type GameConfigSource string
+
+const (
+	// GameConfigSourceURL was fetched from LEVEL_CSV_URL and SHEET_MUSIC_CSV_URL.
+	GameConfigSourceURL GameConfigSource = "url"
+	// GameConfigSourceFile was read from LEVEL_CSV_FILE and SHEET_MUSIC_CSV_FILE.
+	GameConfigSourceFile GameConfigSource = "file"
+	// GameConfigSourceUpload was uploaded by an admin.
+	GameConfigSourceUpload GameConfigSource = "upload"
+)
+
+// GameConfigVersion mirrors the game_config_versions table. Active is computed: it is the version
+// with the latest activated_at. Tracks come from game_config_tracks.
+//
+//nolint:golines // keep struct tags aligned
+type GameConfigVersion struct {
+	Version     int               `db:"version" json:"version"`
+	Source      GameConfigSource  `db:"source" json:"source"`
+	LevelsCSV   string            `db:"levels_csv" json:"-"`
+	CreatedBy   string            `db:"created_by" json:"created_by"`
+	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
+	ActivatedAt *time.Time        `db:"activated_at" json:"activated_at"`
+	Active      bool              `db:"-" json:"active"`
+	Tracks      []GameConfigTrack `db:"-" json:"tracks"`
+}
+
+// GameConfigTrack mirrors the game_config_tracks table: one sheet-music file of a version.
+type GameConfigTrack struct {
+	TrackID       string `db:"track_id" json:"track_id"`
+	Tempo         int    `db:"tempo" json:"tempo"`
+	TimeSignature string `db:"time_signature" json:"time_signature"`
+	SheetCSV      string `db:"sheet_csv" json:"-"`
+}
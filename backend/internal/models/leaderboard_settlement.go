@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// LeaderboardFreeze mirrors the leaderboard_freezes table.
+// While a board is frozen its standings are read from leaderboard_freeze_entries.
+//
+//nolint:golines // keep struct tags aligned
+type LeaderboardFreeze struct {
+	ID       string    `db:"id" json:"id"`
+	Board    string    `db:"board" json:"board"`
+	FrozenAt time.Time `db:"frozen_at" json:"frozen_at"`
+}
+
+// Settlement entry outcomes.
+const (
+	SettlementOutcomeIssued        = "issued"
+	SettlementOutcomeAlreadyIssued = "already_issued"
+	SettlementOutcomeExcludedTie   = "excluded_tie"
+	SettlementOutcomeOutOfStock    = "out_of_stock"
+)
+
+// LeaderboardSettlement mirrors the leaderboard_settlements table.
+// FrozenAt is set when the run settled against a frozen board.
+//
+//nolint:golines // keep struct tags aligned
+type LeaderboardSettlement struct {
+	ID            string                       `db:"id" json:"id"`
+	Board         string                       `db:"board" json:"board"`
+	DiscountID    string                       `db:"discount_id" json:"discount_id"`
+	Amount        int                          `db:"amount" json:"amount"`
+	RankLimit     int                          `db:"rank_limit" json:"rank_limit"`
+	TiePolicy     string                       `db:"tie_policy" json:"tie_policy"`
+	FrozenAt      *time.Time                   `db:"frozen_at" json:"frozen_at"`
+	Trigger       string                       `db:"trigger" json:"trigger"`
+	EligibleCount int                          `db:"eligible_count" json:"eligible_count"`
+	IssuedCount   int                          `db:"issued_count" json:"issued_count"`
+	SettledAt     time.Time                    `db:"settled_at" json:"settled_at"`
+	Entries       []LeaderboardSettlementEntry `db:"-" json:"entries"`
+}
+
+// LeaderboardSettlementEntry mirrors the leaderboard_settlement_entries table.
+//
+//nolint:golines // keep struct tags aligned
+type LeaderboardSettlementEntry struct {
+	SettlementID string  `db:"settlement_id" json:"-"`
+	UserID       string  `db:"user_id" json:"user_id"`
+	Nickname     string  `db:"-" json:"nickname"`
+	Rank         int     `db:"rank" json:"rank"`
+	Score        int     `db:"score" json:"score"`
+	TieSize      int     `db:"tie_size" json:"tie_size"`
+	Outcome      string  `db:"outcome" json:"outcome"`
+	CouponID     *string `db:"coupon_id" json:"coupon_id"`
+}
@@ -1,5 +1,25 @@
 package models
 
+import "strings"
+
+// DefaultTrackID is the sheet-music track used by level ranges that do not name one.
+const DefaultTrackID = "default"
+
+// ChordSeparator joins the pitches of a chord in a note label, e.g. "C4+E4+G4".
+const ChordSeparator = "+"
+
+// NoteType is how a note has to be played.
+type NoteType string
+
+const (
+	// NoteTap is a single pitch tapped once.
+	NoteTap NoteType = "tap"
+	// NoteHold is a single pitch held down for the note's duration.
+	NoteHold NoteType = "hold"
+	// NoteChord is two or more pitches tapped together.
+	NoteChord NoteType = "chord"
+)
+
 // Level represents a single row in the configured level CSV.
 // It is not persisted in the database; used for runtime gameplay config.
 type Level struct {
@@ -7,18 +27,57 @@ type Level struct {
 	EndLevel   int `json:"end_level"`
 	Speed      int `json:"speed"`
 	Notes      int `json:"notes"`
+	// Track is the sheet-music track the range plays; empty means DefaultTrackID.
+	Track string `json:"track"`
+}
+
+// Note is one entry of a chart. Beat and Duration are measured in beats; one beat lasts
+// 60/Speed seconds at the level's speed.
+type Note struct {
+	Pitches  []string `json:"pitches"`
+	Beat     float64  `json:"beat"`
+	Duration float64  `json:"duration"`
+	Type     NoteType `json:"type"`
+}
+
+// Label is the note name the client sends back for this note: the pitch, or the chord's
+// pitches joined with ChordSeparator.
+func (n Note) Label() string {
+	return strings.Join(n.Pitches, ChordSeparator)
+}
+
+// End is the beat at which the note finishes.
+func (n Note) End() float64 {
+	return n.Beat + n.Duration
 }
 
 // LevelInfo is the computed runtime payload for a single level.
 type LevelInfo struct {
-	Level int      `json:"level"`
-	Speed int      `json:"speed"`
-	Notes int      `json:"notes"`
+	Level   int    `json:"level"`
+	Speed   int    `json:"speed"`
+	Notes   int    `json:"notes"`
+	TrackID string `json:"track_id"`
+	// Sheet holds the label of every note in Chart, for clients that only play single taps.
 	Sheet []string `json:"sheet"`
+	// Chart is the level's notes with beats counted from the level's first note.
+	Chart []Note `json:"chart"`
+	// Beats is the length of the chart, from its first note to the end of its last one.
+	Beats float64 `json:"beats"`
 }
 
-// SheetMusic holds the ordered list of note names for gameplay.
-// Notes map directly to the lines in the configured sheet music CSV.
+// SheetMusic is one named track: the ordered notes for gameplay plus the tempo and time signature
+// it was written in. Notes map directly to the rows in the track's sheet music CSV.
 type SheetMusic struct {
-	Notes []string `json:"notes"`
+	TrackID       string `json:"track_id"`
+	Tempo         int    `json:"tempo"`
+	TimeSignature string `json:"time_signature"`
+	Notes         []Note `json:"notes"`
+}
+
+// Length is the number of beats from the start of the track to the end of its last note.
+func (s SheetMusic) Length() float64 {
+	if len(s.Notes) == 0 {
+		return 0
+	}
+	return s.Notes[len(s.Notes)-1].End()
 }
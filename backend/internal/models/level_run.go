@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// LevelRun mirrors the level_runs table.
+// One row is written for every submission that passed server-side replay; runs with misses are
+// stored with Cleared false and never count as a personal best.
+//
+//nolint:golines // keep struct tags aligned
+type LevelRun struct {
+	ID        string    `db:"id" json:"id"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	SessionID string    `db:"session_id" json:"session_id"`
+	Level     int       `db:"level" json:"level"`
+	Score     int       `db:"score" json:"score"`
+	Accuracy  float64   `db:"accuracy" json:"accuracy"`
+	MaxCombo  int       `db:"max_combo" json:"max_combo"`
+	Perfect   int       `db:"perfect_count" json:"perfect"`
+	Great     int       `db:"great_count" json:"great"`
+	Miss      int       `db:"miss_count" json:"miss"`
+	Cleared   bool      `db:"cleared" json:"cleared"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
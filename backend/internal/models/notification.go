@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// NotificationKind names what the notification is about.
+type NotificationKind string
+
+const (
+	// NotificationKindCouponIssued tells the user they received a discount coupon.
+	NotificationKindCouponIssued NotificationKind = "coupon_issued"
+	// NotificationKindFriendAdded tells the user someone scanned their QR code and became their friend.
+	NotificationKindFriendAdded NotificationKind = "friend_added"
+	// NotificationKindGroupCheckIn tells the user a group member checked in with them.
+	NotificationKindGroupCheckIn NotificationKind = "group_check_in"
+)
+
+// CouponSource says how a coupon reached the user.
+type CouponSource string
+
+const (
+	// CouponSourceRule is an automatic coupon from a coupon rule (level, visits, friends, ...).
+	CouponSourceRule CouponSource = "rule"
+	// CouponSourceSettlement is a leaderboard settlement coupon.
+	CouponSourceSettlement CouponSource = "settlement"
+	// CouponSourceGift is a gift coupon the user redeemed by token.
+	CouponSourceGift CouponSource = "gift"
+	// CouponSourceStaffScan is a coupon a staff member issued by scanning the user's QR code.
+	CouponSourceStaffScan CouponSource = "staff_scan"
+	// CouponSourceAdmin is a coupon an admin assigned directly.
+	CouponSourceAdmin CouponSource = "admin"
+)
+
+// NotificationData holds the kind-specific details. Clients render the text from Kind and Data.
+//
+//nolint:golines // keep struct tags aligned
+type NotificationData struct {
+	CouponID   string       `json:"coupon_id,omitempty"`
+	DiscountID string       `json:"discount_id,omitempty"`
+	Price      int          `json:"price,omitempty"`
+	Source     CouponSource `json:"source,omitempty"`
+	UserID     string       `json:"user_id,omitempty"`
+	Nickname   string       `json:"nickname,omitempty"`
+}
+
+// Notification mirrors the notifications table.
+//
+//nolint:golines // keep struct tags aligned
+type Notification struct {
+	ID        string           `db:"id" json:"id"`
+	UserID    string           `db:"user_id" json:"-"`
+	Kind      NotificationKind `db:"kind" json:"kind"`
+	Data      NotificationData `db:"data" json:"data"`
+	ReadAt    *time.Time       `db:"read_at" json:"read_at"`
+	CreatedAt time.Time        `db:"created_at" json:"created_at"`
+}
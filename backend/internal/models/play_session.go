@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PlaySession mirrors the play_sessions table.
+// A session is opened before playing a level and consumed by exactly one submission.
+//
+//nolint:golines // keep struct tags aligned
+type PlaySession struct {
+	ID         string     `db:"id" json:"id"`
+	UserID     string     `db:"user_id" json:"user_id"`
+	Level      int        `db:"level" json:"level"`
+	Nonce      string     `db:"nonce" json:"-"`
+	SheetHash  string     `db:"sheet_hash" json:"-"`
+	ExpiresAt  time.Time  `db:"expires_at" json:"expires_at"`
+	ConsumedAt *time.Time `db:"consumed_at" json:"consumed_at"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+}
+
+// PlayRejection records a hit log that failed server-side replay (table: play_rejections).
+//
+//nolint:golines // keep struct tags aligned
+type PlayRejection struct {
+	ID        string          `db:"id" json:"id"`
+	SessionID string          `db:"session_id" json:"session_id"`
+	UserID    string          `db:"user_id" json:"user_id"`
+	Level     int             `db:"level" json:"level"`
+	Reason    string          `db:"reason" json:"reason"`
+	HitLog    json.RawMessage `db:"hit_log" json:"hit_log"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Scheduled job statuses.
+const (
+	ScheduledJobPending = "pending"
+	// ScheduledJobRunning marks a job claimed by an instance until LeaseUntil.
+	ScheduledJobRunning   = "running"
+	ScheduledJobCompleted = "completed"
+	ScheduledJobFailed    = "failed"
+)
+
+// Scheduled job run statuses.
+const (
+	ScheduledJobRunSucceeded = "succeeded"
+	ScheduledJobRunFailed    = "failed"
+)
+
+// ScheduledJob mirrors the scheduled_jobs table.
+// IntervalSeconds is nil for one-shot jobs, which move to completed after their first successful run.
+//
+//nolint:golines // keep struct tags aligned
+type ScheduledJob struct {
+	Name            string     `db:"name" json:"name"`
+	Status          string     `db:"status" json:"status"`
+	RunAt           time.Time  `db:"run_at" json:"run_at"`
+	LeaseUntil      *time.Time `db:"lease_until" json:"lease_until"`
+	IntervalSeconds *int       `db:"interval_seconds" json:"interval_seconds"`
+	Attempts        int        `db:"attempts" json:"attempts"`
+	LastRunAt       *time.Time `db:"last_run_at" json:"last_run_at"`
+	LastError       *string    `db:"last_error" json:"last_error"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// ScheduledJobRun mirrors the scheduled_job_runs table. One row per execution attempt.
+//
+//nolint:golines // keep struct tags aligned
+type ScheduledJobRun struct {
+	ID           string     `db:"id" json:"id"`
+	JobName      string     `db:"job_name" json:"job_name"`
+	Trigger      string     `db:"trigger" json:"trigger"`
+	ScheduledFor *time.Time `db:"scheduled_for" json:"scheduled_for"`
+	StartedAt    time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt   time.Time  `db:"finished_at" json:"finished_at"`
+	Status       string     `db:"status" json:"status"`
+	Error        *string    `db:"error" json:"error"`
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
 )
 
 // CountVisitedActivities returns how many activities a user has visited.
@@ -18,6 +20,26 @@ func (r *PGRepository) CountVisitedActivities(ctx context.Context, tx pgx.Tx, us
 	return count, nil
 }
 
+// CountVisitedActivitiesByTypes counts visits to activities of the given types; all types when types is empty.
+func (r *PGRepository) CountVisitedActivitiesByTypes(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID string,
+	types []models.ActivitiesTypes,
+) (int, error) {
+	const query = `
+SELECT COUNT(*)
+FROM visits v
+JOIN activities a ON a.id = v.activity_id
+WHERE v.user_id = $1
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR a.type::text = ANY($2::text[]))`
+	var count int
+	if err := tx.QueryRow(ctx, query, userID, activityTypeStrings(types)).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // GetActivityByQRCode fetches an activity by its QR code token.
 func (r *PGRepository) GetActivityByQRCode(ctx context.Context, tx pgx.Tx, qr string) (*models.Activities, error) {
 	const query = `
@@ -125,3 +147,141 @@ ON CONFLICT (user_id, activity_id) DO NOTHING`
 	}
 	return ct.RowsAffected() > 0, nil
 }
+
+const activityTokenLength = 32
+
+// CreateActivity inserts activity with a new id, login token and QR code token, filling them in.
+func (r *PGRepository) CreateActivity(ctx context.Context, tx pgx.Tx, activity *models.Activities) error {
+	const stmt = `
+INSERT INTO activities (id, token, type, qrcode_token, name, floor, link, description, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+RETURNING created_at, updated_at`
+
+	token, err := helpers.RandomAlphabetToken(activityTokenLength)
+	if err != nil {
+		return err
+	}
+	qrCode, err := helpers.RandomAlphabetToken(activityTokenLength)
+	if err != nil {
+		return err
+	}
+	activity.ID = uuid.NewString()
+	activity.Token = token
+	activity.QRCodeToken = qrCode
+
+	return tx.QueryRow(ctx, stmt,
+		activity.ID,
+		activity.Token,
+		activity.Type,
+		activity.QRCodeToken,
+		activity.Name,
+		activity.Floor,
+		activity.Link,
+		activity.Description,
+	).Scan(&activity.CreatedAt, &activity.UpdatedAt)
+}
+
+// UpdateActivity overwrites an activity's editable fields and reloads the row into activity.
+// Tokens are left unchanged; see RotateActivityToken and RotateActivityQRCode.
+// Returns ErrNotFound if missing.
+func (r *PGRepository) UpdateActivity(ctx context.Context, tx pgx.Tx, activity *models.Activities) error {
+	const stmt = `
+UPDATE activities
+SET type = $2, name = $3, floor = $4, link = $5, description = $6, updated_at = NOW()
+WHERE id = $1
+RETURNING id, token, type, qrcode_token, name, floor, link, description, created_at, updated_at`
+
+	row := tx.QueryRow(ctx, stmt,
+		activity.ID,
+		activity.Type,
+		activity.Name,
+		activity.Floor,
+		activity.Link,
+		activity.Description,
+	)
+	updated, err := scanActivity(row)
+	if err != nil {
+		return err
+	}
+	*activity = *updated
+	return nil
+}
+
+// DeleteActivity removes an activity. Returns ErrNotFound if missing and ErrInUse if users
+// have already visited it.
+func (r *PGRepository) DeleteActivity(ctx context.Context, tx pgx.Tx, id string) error {
+	const stmt = `DELETE FROM activities WHERE id = $1`
+
+	tag, err := tx.Exec(ctx, stmt, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrInUse
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RotateActivityToken replaces an activity's login token and revokes every booth session opened
+// with the old one. Returns ErrNotFound if missing.
+func (r *PGRepository) RotateActivityToken(ctx context.Context, tx pgx.Tx, id string) (*models.Activities, error) {
+	const stmt = `
+UPDATE activities
+SET token = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, token, type, qrcode_token, name, floor, link, description, created_at, updated_at`
+
+	token, err := helpers.RandomAlphabetToken(activityTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	activity, err := scanActivity(tx.QueryRow(ctx, stmt, id, token))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.DeleteBoothSessionsByActivity(ctx, tx, activity.ID); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// RotateActivityQRCode replaces an activity's QR code token; printed codes with the old one
+// stop working. Returns ErrNotFound if missing.
+func (r *PGRepository) RotateActivityQRCode(ctx context.Context, tx pgx.Tx, id string) (*models.Activities, error) {
+	const stmt = `
+UPDATE activities
+SET qrcode_token = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, token, type, qrcode_token, name, floor, link, description, created_at, updated_at`
+
+	qrCode, err := helpers.RandomAlphabetToken(activityTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	return scanActivity(tx.QueryRow(ctx, stmt, id, qrCode))
+}
+
+func scanActivity(row pgx.Row) (*models.Activities, error) {
+	var a models.Activities
+	if err := row.Scan(
+		&a.ID,
+		&a.Token,
+		&a.Type,
+		&a.QRCodeToken,
+		&a.Name,
+		&a.Floor,
+		&a.Link,
+		&a.Description,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
@@ -62,18 +62,22 @@ RETURNING id, token, price, discount_id`
 }
 
 // DeleteDiscountCouponGiftByID deletes gift coupon by id.
-func (r *PGRepository) DeleteDiscountCouponGiftByID(ctx context.Context, tx pgx.Tx, id string) error {
-	const stmt = `DELETE FROM discount_coupon_gift WHERE id = $1`
+func (r *PGRepository) DeleteDiscountCouponGiftByID(ctx context.Context, tx pgx.Tx, id string) (*models.DiscountCouponGift, error) {
+	const stmt = `
+DELETE FROM discount_coupon_gift
+WHERE id = $1
+RETURNING id, token, price, discount_id`
 
-	tag, err := tx.Exec(ctx, stmt, id)
+	var gift models.DiscountCouponGift
+	err := tx.QueryRow(ctx, stmt, id).Scan(&gift.ID, &gift.Token, &gift.Price, &gift.DiscountID)
 	if err != nil {
-		return err
-	}
-	if tag.RowsAffected() == 0 {
-		return ErrNotFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
 	}
 
-	return nil
+	return &gift, nil
 }
 
 // ListDiscountCouponGifts returns all gift coupons.
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+const adminColumns = `id, username, role, key_hash, disabled, created_at, updated_at`
+
+// CountAdmins returns the number of admin accounts, disabled ones included.
+func (r *PGRepository) CountAdmins(ctx context.Context, tx pgx.Tx) (int, error) {
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM admins`).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListAdmins returns all admin accounts ordered by username.
+func (r *PGRepository) ListAdmins(ctx context.Context, tx pgx.Tx) ([]models.Admin, error) {
+	const query = `
+SELECT ` + adminColumns + `
+FROM admins
+ORDER BY username ASC`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	admins := make([]models.Admin, 0)
+	for rows.Next() {
+		admin, scanErr := scanAdmin(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		admins = append(admins, *admin)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+// GetAdminByID returns an admin, enabled or not. Returns ErrNotFound if missing.
+func (r *PGRepository) GetAdminByID(ctx context.Context, tx pgx.Tx, id string) (*models.Admin, error) {
+	const query = `
+SELECT ` + adminColumns + `
+FROM admins
+WHERE id = $1`
+
+	return scanAdmin(tx.QueryRow(ctx, query, id))
+}
+
+// GetAdminByKeyHash finds an enabled admin by the hash of their login key. Returns ErrNotFound if missing.
+func (r *PGRepository) GetAdminByKeyHash(ctx context.Context, tx pgx.Tx, keyHash string) (*models.Admin, error) {
+	const query = `
+SELECT ` + adminColumns + `
+FROM admins
+WHERE key_hash = $1 AND NOT disabled`
+
+	return scanAdmin(tx.QueryRow(ctx, query, keyHash))
+}
+
+// CreateAdmin inserts admin with a new id. Returns ErrAlreadyExists if the username is taken.
+func (r *PGRepository) CreateAdmin(ctx context.Context, tx pgx.Tx, admin *models.Admin) error {
+	const stmt = `
+INSERT INTO admins (id, username, role, key_hash, disabled, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+RETURNING ` + adminColumns
+
+	created, err := scanAdmin(tx.QueryRow(ctx, stmt, uuid.NewString(), admin.Username, admin.Role, admin.KeyHash, admin.Disabled))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+	*admin = *created
+	return nil
+}
+
+// UpdateAdmin overwrites the role and disabled flag of admin.ID and reloads the row into admin.
+// Returns ErrNotFound if missing.
+func (r *PGRepository) UpdateAdmin(ctx context.Context, tx pgx.Tx, admin *models.Admin) error {
+	const stmt = `
+UPDATE admins
+SET role = $2,
+    disabled = $3,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING ` + adminColumns
+
+	updated, err := scanAdmin(tx.QueryRow(ctx, stmt, admin.ID, admin.Role, admin.Disabled))
+	if err != nil {
+		return err
+	}
+	*admin = *updated
+	return nil
+}
+
+// SetAdminKeyHash replaces the login key hash of an admin. Returns ErrNotFound if missing.
+func (r *PGRepository) SetAdminKeyHash(ctx context.Context, tx pgx.Tx, id string, keyHash string) (*models.Admin, error) {
+	const stmt = `
+UPDATE admins
+SET key_hash = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING ` + adminColumns
+
+	return scanAdmin(tx.QueryRow(ctx, stmt, id, keyHash))
+}
+
+// DeleteAdmin removes an admin account and its sessions. Returns ErrNotFound if missing.
+func (r *PGRepository) DeleteAdmin(ctx context.Context, tx pgx.Tx, id string) error {
+	tag, err := tx.Exec(ctx, `DELETE FROM admins WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateAdminSession stores a session for adminID under the hash of its cookie token.
+func (r *PGRepository) CreateAdminSession(
+	ctx context.Context,
+	tx pgx.Tx,
+	tokenHash string,
+	adminID string,
+	expiresAt time.Time,
+) error {
+	const stmt = `
+INSERT INTO admin_sessions (token_hash, admin_id, created_at, expires_at)
+VALUES ($1, $2, NOW(), $3)`
+
+	_, err := tx.Exec(ctx, stmt, tokenHash, adminID, expiresAt)
+	return err
+}
+
+// GetAdminBySessionHash returns the enabled admin owning an unexpired session. Returns ErrNotFound otherwise.
+func (r *PGRepository) GetAdminBySessionHash(
+	ctx context.Context,
+	tx pgx.Tx,
+	tokenHash string,
+	now time.Time,
+) (*models.Admin, error) {
+	const query = `
+SELECT a.id, a.username, a.role, a.key_hash, a.disabled, a.created_at, a.updated_at
+FROM admin_sessions s
+JOIN admins a ON a.id = s.admin_id
+WHERE s.token_hash = $1 AND s.expires_at > $2 AND NOT a.disabled`
+
+	return scanAdmin(tx.QueryRow(ctx, query, tokenHash, now))
+}
+
+// DeleteAdminSession revokes one session. Missing sessions are ignored.
+func (r *PGRepository) DeleteAdminSession(ctx context.Context, tx pgx.Tx, tokenHash string) error {
+	_, err := tx.Exec(ctx, `DELETE FROM admin_sessions WHERE token_hash = $1`, tokenHash)
+	return err
+}
+
+// DeleteAdminSessionsByAdmin revokes every session of an admin and returns how many were removed.
+func (r *PGRepository) DeleteAdminSessionsByAdmin(ctx context.Context, tx pgx.Tx, adminID string) (int64, error) {
+	tag, err := tx.Exec(ctx, `DELETE FROM admin_sessions WHERE admin_id = $1`, adminID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteExpiredAdminSessions removes sessions that expired before now.
+func (r *PGRepository) DeleteExpiredAdminSessions(ctx context.Context, tx pgx.Tx, now time.Time) (int64, error) {
+	tag, err := tx.Exec(ctx, `DELETE FROM admin_sessions WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanAdmin(row pgx.Row) (*models.Admin, error) {
+	var a models.Admin
+	if err := row.Scan(
+		&a.ID,
+		&a.Username,
+		&a.Role,
+		&a.KeyHash,
+		&a.Disabled,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
@@ -2,17 +2,23 @@ package repository
 
 import (
 	"context"
+	"errors"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
 )
 
-// ListAnnouncements returns all announcements ordered by newest first.
+const announcementColumns = `id, title, content, localizations, severity, pinned, audience, audience_group,
+       audience_min_level, publish_at, expire_at, created_at, updated_at`
+
+// ListAnnouncements returns all announcements, pinned first, then newest published first.
+// Callers filter by audience and publish window.
 func (r *PGRepository) ListAnnouncements(ctx context.Context, tx pgx.Tx) ([]models.Announcement, error) {
 	const query = `
-SELECT id, content, created_at
+SELECT ` + announcementColumns + `
 FROM announcements
-ORDER BY created_at DESC`
+ORDER BY pinned DESC, COALESCE(publish_at, created_at) DESC, id ASC`
 
 	rows, err := tx.Query(ctx, query)
 	if err != nil {
@@ -22,12 +28,11 @@ ORDER BY created_at DESC`
 
 	items := make([]models.Announcement, 0)
 	for rows.Next() {
-		var item models.Announcement
-		scanErr := rows.Scan(&item.ID, &item.Content, &item.CreatedAt)
+		item, scanErr := scanAnnouncement(rows)
 		if scanErr != nil {
 			return nil, scanErr
 		}
-		items = append(items, item)
+		items = append(items, *item)
 	}
 	rowsErr := rows.Err()
 	if rowsErr != nil {
@@ -36,3 +41,126 @@ ORDER BY created_at DESC`
 
 	return items, nil
 }
+
+// GetAnnouncement returns one announcement. Returns ErrNotFound if missing.
+func (r *PGRepository) GetAnnouncement(ctx context.Context, tx pgx.Tx, id string) (*models.Announcement, error) {
+	const query = `
+SELECT ` + announcementColumns + `
+FROM announcements
+WHERE id = $1`
+
+	return scanAnnouncement(tx.QueryRow(ctx, query, id))
+}
+
+// CreateAnnouncement inserts item with a new id and reloads the stored row into it.
+func (r *PGRepository) CreateAnnouncement(ctx context.Context, tx pgx.Tx, item *models.Announcement) error {
+	const stmt = `
+INSERT INTO announcements (id, title, content, localizations, severity, pinned, audience, audience_group,
+                           audience_min_level, publish_at, expire_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+RETURNING ` + announcementColumns
+
+	created, err := scanAnnouncement(tx.QueryRow(ctx, stmt,
+		uuid.NewString(),
+		item.Title,
+		item.Content,
+		localizationsOrEmpty(item.Localizations),
+		item.Severity,
+		item.Pinned,
+		item.Audience,
+		item.AudienceGroup,
+		item.AudienceMinLevel,
+		item.PublishAt,
+		item.ExpireAt,
+	))
+	if err != nil {
+		return err
+	}
+	*item = *created
+	return nil
+}
+
+// UpdateAnnouncement overwrites every editable field of item.ID and reloads the row into item.
+// Returns ErrNotFound if missing.
+func (r *PGRepository) UpdateAnnouncement(ctx context.Context, tx pgx.Tx, item *models.Announcement) error {
+	const stmt = `
+UPDATE announcements
+SET title = $2,
+    content = $3,
+    localizations = $4,
+    severity = $5,
+    pinned = $6,
+    audience = $7,
+    audience_group = $8,
+    audience_min_level = $9,
+    publish_at = $10,
+    expire_at = $11,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING ` + announcementColumns
+
+	updated, err := scanAnnouncement(tx.QueryRow(ctx, stmt,
+		item.ID,
+		item.Title,
+		item.Content,
+		localizationsOrEmpty(item.Localizations),
+		item.Severity,
+		item.Pinned,
+		item.Audience,
+		item.AudienceGroup,
+		item.AudienceMinLevel,
+		item.PublishAt,
+		item.ExpireAt,
+	))
+	if err != nil {
+		return err
+	}
+	*item = *updated
+	return nil
+}
+
+// DeleteAnnouncement removes an announcement. Returns ErrNotFound if missing.
+func (r *PGRepository) DeleteAnnouncement(ctx context.Context, tx pgx.Tx, id string) error {
+	const stmt = `DELETE FROM announcements WHERE id = $1`
+
+	tag, err := tx.Exec(ctx, stmt, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanAnnouncement(row pgx.Row) (*models.Announcement, error) {
+	var item models.Announcement
+	if err := row.Scan(
+		&item.ID,
+		&item.Title,
+		&item.Content,
+		&item.Localizations,
+		&item.Severity,
+		&item.Pinned,
+		&item.Audience,
+		&item.AudienceGroup,
+		&item.AudienceMinLevel,
+		&item.PublishAt,
+		&item.ExpireAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func localizationsOrEmpty(l map[string]models.AnnouncementLocalization) map[string]models.AnnouncementLocalization {
+	if l == nil {
+		return map[string]models.AnnouncementLocalization{}
+	}
+	return l
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+// AuditEventFilter narrows ListAuditEvents. Zero fields do not filter.
+// Before and BeforeID page backwards: only events ordered after (Before, BeforeID) are returned.
+// Without BeforeID, only events created strictly before Before are returned.
+type AuditEventFilter struct {
+	ActorType  models.AuditActorType
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Since      *time.Time
+	Until      *time.Time
+	Before     *time.Time
+	BeforeID   string
+	// Limit caps the number of rows; 0 returns every match.
+	Limit int
+}
+
+// InsertAuditEvent appends event, filling in ID and CreatedAt.
+func (r *PGRepository) InsertAuditEvent(ctx context.Context, tx pgx.Tx, event *models.AuditEvent) error {
+	const stmt = `
+INSERT INTO audit_events (id, actor_type, actor_id, actor_name, action, target_type, target_id,
+                          before, after, request_id, trace_id, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	event.ID = uuid.NewString()
+	event.CreatedAt = time.Now().UTC()
+	_, err := tx.Exec(ctx, stmt,
+		event.ID,
+		event.ActorType,
+		event.ActorID,
+		event.ActorName,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		nullableJSON(event.Before),
+		nullableJSON(event.After),
+		event.RequestID,
+		event.TraceID,
+		event.CreatedAt,
+	)
+	return err
+}
+
+// ListAuditEvents calls fn for each event matching filter, newest first. Rows are streamed,
+// so an export of the whole table does not have to fit in memory.
+func (r *PGRepository) ListAuditEvents(
+	ctx context.Context,
+	tx pgx.Tx,
+	filter AuditEventFilter,
+	fn func(models.AuditEvent) error,
+) error {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.ActorType != "" {
+		add("actor_type = $%d", filter.ActorType)
+	}
+	if filter.ActorID != "" {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if filter.Since != nil {
+		add("created_at >= $%d", *filter.Since)
+	}
+	if filter.Until != nil {
+		add("created_at < $%d", *filter.Until)
+	}
+	switch {
+	case filter.Before != nil && filter.BeforeID != "":
+		args = append(args, *filter.Before, filter.BeforeID)
+		conds = append(conds, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	case filter.Before != nil:
+		add("created_at < $%d", *filter.Before)
+	}
+
+	query := `
+SELECT id, actor_type, actor_id, actor_name, action, target_type, target_id,
+       before, after, request_id, trace_id, created_at
+FROM audit_events`
+	if len(conds) > 0 {
+		query += "\nWHERE " + strings.Join(conds, " AND ")
+	}
+	query += "\nORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf("\nLIMIT $%d", len(args))
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.AuditEvent
+		if err = rows.Scan(
+			&e.ID,
+			&e.ActorType,
+			&e.ActorID,
+			&e.ActorName,
+			&e.Action,
+			&e.TargetType,
+			&e.TargetID,
+			&e.Before,
+			&e.After,
+			&e.RequestID,
+			&e.TraceID,
+			&e.CreatedAt,
+		); err != nil {
+			return err
+		}
+		if err = fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func nullableJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
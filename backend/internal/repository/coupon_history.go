@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+const couponHistoryColumns = `
+ch.id, ch.user_id, COALESCE(u.nickname, ''), ch.staff_id, ch.total, ch.used_at, ch.created_at,
+v.id, v.actor, v.staff_id, v.reason, v.total, v.coupon_ids::text[], v.voided_at`
+
+// ListCouponHistoryByStaff returns redemption history for a given staff ordered by used_at desc.
+// Voided redemptions are included with their void attached.
+func (r *PGRepository) ListCouponHistoryByStaff(
+	ctx context.Context,
+	tx pgx.Tx,
+	staffID string,
+) ([]models.CouponHistory, error) {
+	const query = `
+SELECT ` + couponHistoryColumns + `
+FROM coupon_histories ch
+LEFT JOIN users u ON u.id = ch.user_id
+LEFT JOIN coupon_history_voids v ON v.history_id = ch.id
+WHERE ch.staff_id = $1
+ORDER BY ch.used_at DESC`
+
+	rows, err := tx.Query(ctx, query, staffID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var histories []models.CouponHistory
+	for rows.Next() {
+		h, scanErr := scanCouponHistory(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		histories = append(histories, *h)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+// GetCouponHistoryForUpdate fetches a redemption with a row lock. Returns ErrNotFound if missing.
+func (r *PGRepository) GetCouponHistoryForUpdate(ctx context.Context, tx pgx.Tx, id string) (*models.CouponHistory, error) {
+	const query = `
+SELECT ` + couponHistoryColumns + `
+FROM coupon_histories ch
+LEFT JOIN users u ON u.id = ch.user_id
+LEFT JOIN coupon_history_voids v ON v.history_id = ch.id
+WHERE ch.id = $1
+FOR UPDATE OF ch`
+
+	h, err := scanCouponHistory(tx.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return h, nil
+}
+
+// SumCouponHistoryByStaff totals a staff member's redemptions, netting out voided ones.
+func (r *PGRepository) SumCouponHistoryByStaff(
+	ctx context.Context,
+	tx pgx.Tx,
+	staffID string,
+) (*models.CouponHistoryTotals, error) {
+	const query = `
+SELECT COUNT(*),
+       COUNT(v.id),
+       COALESCE(SUM(ch.total), 0),
+       COALESCE(SUM(ch.total) FILTER (WHERE v.id IS NOT NULL), 0)
+FROM coupon_histories ch
+LEFT JOIN coupon_history_voids v ON v.history_id = ch.id
+WHERE ch.staff_id = $1`
+
+	var t models.CouponHistoryTotals
+	if err := tx.QueryRow(ctx, query, staffID).Scan(
+		&t.Count,
+		&t.VoidedCount,
+		&t.GrossTotal,
+		&t.VoidedTotal,
+	); err != nil {
+		return nil, err
+	}
+	t.NetTotal = t.GrossTotal - t.VoidedTotal
+	return &t, nil
+}
+
+// RestoreDiscountsByHistory returns every coupon redeemed by a history row to unused.
+func (r *PGRepository) RestoreDiscountsByHistory(
+	ctx context.Context,
+	tx pgx.Tx,
+	historyID string,
+) ([]models.DiscountCoupon, error) {
+	const stmt = `
+UPDATE discount_coupons
+SET used_at = NULL, used_by = NULL, history_id = NULL
+WHERE history_id = $1
+RETURNING id, discount_id, user_id, price, used_by, used_at, history_id, created_at`
+
+	rows, err := tx.Query(ctx, stmt, historyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coupons []models.DiscountCoupon
+	for rows.Next() {
+		var c models.DiscountCoupon
+		if err = rows.Scan(
+			&c.ID,
+			&c.DiscountID,
+			&c.UserID,
+			&c.Price,
+			&c.UsedBy,
+			&c.UsedAt,
+			&c.HistoryID,
+			&c.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+// InsertCouponHistoryVoid records a reversal. Returns ErrAlreadyExists if the redemption is already voided.
+func (r *PGRepository) InsertCouponHistoryVoid(ctx context.Context, tx pgx.Tx, void *models.CouponHistoryVoid) error {
+	const stmt = `
+INSERT INTO coupon_history_voids (id, history_id, actor, staff_id, reason, total, coupon_ids, voided_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7::uuid[], $8)
+ON CONFLICT (history_id) DO NOTHING`
+
+	tag, err := tx.Exec(ctx, stmt,
+		void.ID,
+		void.HistoryID,
+		void.Actor,
+		void.StaffID,
+		void.Reason,
+		void.Total,
+		void.CouponIDs,
+		void.VoidedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+func scanCouponHistory(row pgx.Row) (*models.CouponHistory, error) {
+	var (
+		h         models.CouponHistory
+		voidID    *string
+		actor     *string
+		voidStaff *string
+		reason    *string
+		voidTotal *int
+		couponIDs []string
+		voidedAt  *time.Time
+	)
+	if err := row.Scan(
+		&h.ID,
+		&h.UserID,
+		&h.Nickname,
+		&h.StaffID,
+		&h.Total,
+		&h.UsedAt,
+		&h.CreatedAt,
+		&voidID,
+		&actor,
+		&voidStaff,
+		&reason,
+		&voidTotal,
+		&couponIDs,
+		&voidedAt,
+	); err != nil {
+		return nil, err
+	}
+	if voidID != nil {
+		h.Void = &models.CouponHistoryVoid{
+			ID:        *voidID,
+			HistoryID: h.ID,
+			Actor:     *actor,
+			StaffID:   voidStaff,
+			Reason:    *reason,
+			Total:     *voidTotal,
+			CouponIDs: couponIDs,
+			VoidedAt:  *voidedAt,
+		}
+	}
+	return &h, nil
+}
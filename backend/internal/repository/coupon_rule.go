@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+const couponRuleColumns = `
+id, trigger, threshold, activity_types, activity_id::text, amount, stock_limit,
+budget, issued_count, issued_amount, valid_from, valid_until, description, sort_order, enabled, created_at, updated_at`
+
+// ListCouponRules returns every coupon rule, including disabled ones, in display order.
+func (r *PGRepository) ListCouponRules(ctx context.Context, tx pgx.Tx) ([]models.CouponRule, error) {
+	query := `SELECT ` + couponRuleColumns + `
+FROM coupon_rules
+ORDER BY sort_order, id`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.CouponRule
+	for rows.Next() {
+		rule, scanErr := scanCouponRule(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		rules = append(rules, *rule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetCouponRule fetches a coupon rule by id.
+func (r *PGRepository) GetCouponRule(ctx context.Context, tx pgx.Tx, id string) (*models.CouponRule, error) {
+	query := `SELECT ` + couponRuleColumns + `
+FROM coupon_rules
+WHERE id = $1`
+
+	rule, err := scanCouponRule(tx.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpsertCouponRule creates or replaces a coupon rule and fills in its timestamps.
+func (r *PGRepository) UpsertCouponRule(ctx context.Context, tx pgx.Tx, rule *models.CouponRule) error {
+	query := `
+INSERT INTO coupon_rules (
+    id, trigger, threshold, activity_types, activity_id, amount, stock_limit,
+    budget, valid_from, valid_until, description, sort_order, enabled, created_at, updated_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $14)
+ON CONFLICT (id) DO UPDATE SET
+    trigger = EXCLUDED.trigger,
+    threshold = EXCLUDED.threshold,
+    activity_types = EXCLUDED.activity_types,
+    activity_id = EXCLUDED.activity_id,
+    amount = EXCLUDED.amount,
+    stock_limit = EXCLUDED.stock_limit,
+    budget = EXCLUDED.budget,
+    valid_from = EXCLUDED.valid_from,
+    valid_until = EXCLUDED.valid_until,
+    description = EXCLUDED.description,
+    sort_order = EXCLUDED.sort_order,
+    enabled = EXCLUDED.enabled,
+    updated_at = EXCLUDED.updated_at
+RETURNING issued_count, issued_amount, created_at, updated_at`
+
+	return tx.QueryRow(ctx, query,
+		rule.ID,
+		rule.Trigger,
+		rule.Threshold,
+		activityTypeStrings(rule.ActivityTypes),
+		rule.ActivityID,
+		rule.Amount,
+		rule.StockLimit,
+		rule.Budget,
+		rule.ValidFrom,
+		rule.ValidUntil,
+		rule.Description,
+		rule.SortOrder,
+		rule.Enabled,
+		time.Now().UTC(),
+	).Scan(&rule.IssuedCount, &rule.IssuedAmount, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// DeleteCouponRule removes a coupon rule. Coupons already issued under it are kept.
+func (r *PGRepository) DeleteCouponRule(ctx context.Context, tx pgx.Tx, id string) error {
+	tag, err := tx.Exec(ctx, `DELETE FROM coupon_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ReserveCouponStock takes one coupon worth amount out of the rule's stock and budget in a single
+// conditional update, so concurrent callers cannot overshoot either cap. It returns false when the
+// rule is exhausted and ErrNotFound when no rule exists for discountID.
+func (r *PGRepository) ReserveCouponStock(ctx context.Context, tx pgx.Tx, discountID string, amount int) (bool, error) {
+	const stmt = `
+UPDATE coupon_rules
+SET issued_count = issued_count + 1,
+    issued_amount = issued_amount + $2
+WHERE id = $1
+  AND (stock_limit IS NULL OR issued_count < stock_limit)
+  AND (budget IS NULL OR issued_amount + $2 <= budget)`
+
+	tag, err := tx.Exec(ctx, stmt, discountID, amount)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() > 0 {
+		return true, nil
+	}
+
+	var exists bool
+	if err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM coupon_rules WHERE id = $1)`, discountID).Scan(&exists); err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, ErrNotFound
+	}
+	return false, nil
+}
+
+// ReleaseCouponStock returns a reservation made by ReserveCouponStock. Unknown rules are ignored.
+func (r *PGRepository) ReleaseCouponStock(ctx context.Context, tx pgx.Tx, discountID string, amount int) error {
+	const stmt = `
+UPDATE coupon_rules
+SET issued_count = GREATEST(issued_count - 1, 0),
+    issued_amount = GREATEST(issued_amount - $2, 0)
+WHERE id = $1`
+
+	_, err := tx.Exec(ctx, stmt, discountID, amount)
+	return err
+}
+
+func scanCouponRule(row pgx.Row) (*models.CouponRule, error) {
+	var (
+		rule  models.CouponRule
+		types []string
+	)
+	if err := row.Scan(
+		&rule.ID,
+		&rule.Trigger,
+		&rule.Threshold,
+		&types,
+		&rule.ActivityID,
+		&rule.Amount,
+		&rule.StockLimit,
+		&rule.Budget,
+		&rule.IssuedCount,
+		&rule.IssuedAmount,
+		&rule.ValidFrom,
+		&rule.ValidUntil,
+		&rule.Description,
+		&rule.SortOrder,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		rule.ActivityTypes = append(rule.ActivityTypes, models.ActivitiesTypes(t))
+	}
+	return &rule, nil
+}
+
+func activityTypeStrings(types []models.ActivitiesTypes) []string {
+	if len(types) == 0 {
+		return nil
+	}
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+// DeviceSessionTouchInterval is how stale last_seen_at must be before a request updates it,
+// so that every request does not write the session row.
+const DeviceSessionTouchInterval = time.Minute
+
+// CreateStaffSession stores session for session.OwnerID under the hash of its cookie token.
+func (r *PGRepository) CreateStaffSession(
+	ctx context.Context,
+	tx pgx.Tx,
+	tokenHash string,
+	session *models.DeviceSession,
+) error {
+	const stmt = `
+INSERT INTO staff_sessions (id, token_hash, staff_id, device_label, created_at, last_seen_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $5, $6)`
+
+	_, err := tx.Exec(ctx, stmt,
+		session.ID, tokenHash, session.OwnerID, session.DeviceLabel, session.CreatedAt, session.ExpiresAt)
+	return err
+}
+
+// GetStaffBySessionHash returns the staff owning an unexpired session and the session's last_seen_at.
+// Returns ErrNotFound otherwise.
+func (r *PGRepository) GetStaffBySessionHash(
+	ctx context.Context,
+	tx pgx.Tx,
+	tokenHash string,
+	now time.Time,
+) (*models.Staff, time.Time, error) {
+	const query = `
+SELECT st.id, st.name, st.token, st.created_at, st.updated_at, s.last_seen_at
+FROM staff_sessions s
+JOIN staffs st ON st.id = s.staff_id
+WHERE s.token_hash = $1 AND s.expires_at > $2`
+
+	var (
+		st       models.Staff
+		lastSeen time.Time
+	)
+	err := tx.QueryRow(ctx, query, tokenHash, now).
+		Scan(&st.ID, &st.Name, &st.Token, &st.CreatedAt, &st.UpdatedAt, &lastSeen)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, time.Time{}, ErrNotFound
+		}
+		return nil, time.Time{}, err
+	}
+	return &st, lastSeen, nil
+}
+
+// TouchStaffSession records that a session was used at now, at most once per minute.
+func (r *PGRepository) TouchStaffSession(ctx context.Context, tx pgx.Tx, tokenHash string, now time.Time) error {
+	const stmt = `
+UPDATE staff_sessions
+SET last_seen_at = $2
+WHERE token_hash = $1 AND last_seen_at < $3`
+
+	_, err := tx.Exec(ctx, stmt, tokenHash, now, now.Add(-DeviceSessionTouchInterval))
+	return err
+}
+
+// ListStaffSessions returns a staff member's unexpired sessions, most recently used first.
+func (r *PGRepository) ListStaffSessions(
+	ctx context.Context,
+	tx pgx.Tx,
+	staffID string,
+	now time.Time,
+) ([]models.DeviceSession, error) {
+	const query = `
+SELECT id, staff_id, device_label, created_at, last_seen_at, expires_at
+FROM staff_sessions
+WHERE staff_id = $1 AND expires_at > $2
+ORDER BY last_seen_at DESC, id ASC`
+
+	return collectDeviceSessions(tx.Query(ctx, query, staffID, now))
+}
+
+// DeleteStaffSession revokes one session of a staff member. Returns ErrNotFound if missing.
+func (r *PGRepository) DeleteStaffSession(ctx context.Context, tx pgx.Tx, staffID string, id string) error {
+	tag, err := tx.Exec(ctx, `DELETE FROM staff_sessions WHERE id = $1 AND staff_id = $2`, id, staffID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteStaffSessionByHash revokes the session behind a cookie token. Missing sessions are ignored.
+func (r *PGRepository) DeleteStaffSessionByHash(ctx context.Context, tx pgx.Tx, tokenHash string) error {
+	_, err := tx.Exec(ctx, `DELETE FROM staff_sessions WHERE token_hash = $1`, tokenHash)
+	return err
+}
+
+// DeleteStaffSessionsByStaff revokes every session of a staff member and returns how many were removed.
+func (r *PGRepository) DeleteStaffSessionsByStaff(ctx context.Context, tx pgx.Tx, staffID string) (int64, error) {
+	tag, err := tx.Exec(ctx, `DELETE FROM staff_sessions WHERE staff_id = $1`, staffID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// CreateBoothSession stores session for session.OwnerID under the hash of its cookie token.
+func (r *PGRepository) CreateBoothSession(
+	ctx context.Context,
+	tx pgx.Tx,
+	tokenHash string,
+	session *models.DeviceSession,
+) error {
+	const stmt = `
+INSERT INTO booth_sessions (id, token_hash, activity_id, device_label, created_at, last_seen_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $5, $6)`
+
+	_, err := tx.Exec(ctx, stmt,
+		session.ID, tokenHash, session.OwnerID, session.DeviceLabel, session.CreatedAt, session.ExpiresAt)
+	return err
+}
+
+// GetActivityBySessionHash returns the activity owning an unexpired booth session.
+// Returns ErrNotFound otherwise.
+func (r *PGRepository) GetActivityBySessionHash(
+	ctx context.Context,
+	tx pgx.Tx,
+	tokenHash string,
+	now time.Time,
+) (*models.Activities, time.Time, error) {
+	const query = `
+SELECT a.id, a.token, a.type, a.qrcode_token, a.name, a.floor, a.link, a.description, a.created_at, a.updated_at,
+       s.last_seen_at
+FROM booth_sessions s
+JOIN activities a ON a.id = s.activity_id
+WHERE s.token_hash = $1 AND s.expires_at > $2`
+
+	var (
+		a        models.Activities
+		lastSeen time.Time
+	)
+	err := tx.QueryRow(ctx, query, tokenHash, now).Scan(
+		&a.ID,
+		&a.Token,
+		&a.Type,
+		&a.QRCodeToken,
+		&a.Name,
+		&a.Floor,
+		&a.Link,
+		&a.Description,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+		&lastSeen,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, time.Time{}, ErrNotFound
+		}
+		return nil, time.Time{}, err
+	}
+	return &a, lastSeen, nil
+}
+
+// TouchBoothSession records that a session was used at now, at most once per minute.
+func (r *PGRepository) TouchBoothSession(ctx context.Context, tx pgx.Tx, tokenHash string, now time.Time) error {
+	const stmt = `
+UPDATE booth_sessions
+SET last_seen_at = $2
+WHERE token_hash = $1 AND last_seen_at < $3`
+
+	_, err := tx.Exec(ctx, stmt, tokenHash, now, now.Add(-DeviceSessionTouchInterval))
+	return err
+}
+
+// ListBoothSessions returns an activity's unexpired booth sessions, most recently used first.
+func (r *PGRepository) ListBoothSessions(
+	ctx context.Context,
+	tx pgx.Tx,
+	activityID string,
+	now time.Time,
+) ([]models.DeviceSession, error) {
+	const query = `
+SELECT id, activity_id, device_label, created_at, last_seen_at, expires_at
+FROM booth_sessions
+WHERE activity_id = $1 AND expires_at > $2
+ORDER BY last_seen_at DESC, id ASC`
+
+	return collectDeviceSessions(tx.Query(ctx, query, activityID, now))
+}
+
+// DeleteBoothSession revokes one booth session of an activity. Returns ErrNotFound if missing.
+func (r *PGRepository) DeleteBoothSession(ctx context.Context, tx pgx.Tx, activityID string, id string) error {
+	tag, err := tx.Exec(ctx, `DELETE FROM booth_sessions WHERE id = $1 AND activity_id = $2`, id, activityID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteBoothSessionByHash revokes the session behind a cookie token. Missing sessions are ignored.
+func (r *PGRepository) DeleteBoothSessionByHash(ctx context.Context, tx pgx.Tx, tokenHash string) error {
+	_, err := tx.Exec(ctx, `DELETE FROM booth_sessions WHERE token_hash = $1`, tokenHash)
+	return err
+}
+
+// DeleteBoothSessionsByActivity revokes every booth session of an activity and returns how many
+// were removed.
+func (r *PGRepository) DeleteBoothSessionsByActivity(ctx context.Context, tx pgx.Tx, activityID string) (int64, error) {
+	tag, err := tx.Exec(ctx, `DELETE FROM booth_sessions WHERE activity_id = $1`, activityID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteExpiredDeviceSessions removes staff and booth sessions that expired before now.
+func (r *PGRepository) DeleteExpiredDeviceSessions(ctx context.Context, tx pgx.Tx, now time.Time) (int64, error) {
+	staffTag, err := tx.Exec(ctx, `DELETE FROM staff_sessions WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	boothTag, err := tx.Exec(ctx, `DELETE FROM booth_sessions WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return staffTag.RowsAffected() + boothTag.RowsAffected(), nil
+}
+
+func collectDeviceSessions(rows pgx.Rows, err error) ([]models.DeviceSession, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]models.DeviceSession, 0)
+	for rows.Next() {
+		var s models.DeviceSession
+		if err = rows.Scan(
+			&s.ID,
+			&s.OwnerID,
+			&s.DeviceLabel,
+			&s.CreatedAt,
+			&s.LastSeenAt,
+			&s.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
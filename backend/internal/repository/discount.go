@@ -163,11 +163,13 @@ FOR UPDATE`
 	return coupons, nil
 }
 
-// MarkDiscountsUsedByUser sets used_at, used_by, and history_id for all unused coupons of a user.
-func (r *PGRepository) MarkDiscountsUsedByUser(
+// MarkDiscountsUsedByIDs sets used_at, used_by, and history_id for the given unused coupons of a user.
+// Coupons that belong to someone else or are already used are skipped; callers compare the result length.
+func (r *PGRepository) MarkDiscountsUsedByIDs(
 	ctx context.Context,
 	tx pgx.Tx,
 	userID string,
+	couponIDs []string,
 	staffID string,
 	historyID string,
 	usedAt time.Time,
@@ -175,10 +177,10 @@ func (r *PGRepository) MarkDiscountsUsedByUser(
 	const stmt = `
 UPDATE discount_coupons
 SET used_at = $3, used_by = $2, history_id = $4
-WHERE user_id = $1 AND used_at IS NULL
+WHERE user_id = $1 AND id = ANY($5) AND used_at IS NULL
 RETURNING id, discount_id, user_id, price, used_by, used_at, history_id, created_at`
 
-	rows, err := tx.Query(ctx, stmt, userID, staffID, usedAt, historyID)
+	rows, err := tx.Query(ctx, stmt, userID, staffID, usedAt, historyID, couponIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -228,54 +230,6 @@ VALUES ($1, $2, $3, $4, $5, $6)`
 	return err
 }
 
-// ListCouponHistoryByStaff returns redemption history for a given staff ordered by used_at desc.
-func (r *PGRepository) ListCouponHistoryByStaff(
-	ctx context.Context,
-	tx pgx.Tx,
-	staffID string,
-) ([]models.CouponHistory, error) {
-	const query = `
-SELECT ch.id,
-       ch.user_id,
-       u.nickname,
-       ch.staff_id,
-       ch.total,
-       ch.used_at,
-       ch.created_at
-FROM coupon_histories ch
-LEFT JOIN users u ON u.id = ch.user_id
-WHERE ch.staff_id = $1
-ORDER BY ch.used_at DESC`
-
-	rows, err := tx.Query(ctx, query, staffID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var histories []models.CouponHistory
-	for rows.Next() {
-		var h models.CouponHistory
-		if scanErr := rows.Scan(
-			&h.ID,
-			&h.UserID,
-			&h.Nickname,
-			&h.StaffID,
-			&h.Total,
-			&h.UsedAt,
-			&h.CreatedAt,
-		); scanErr != nil {
-			return nil, scanErr
-		}
-		histories = append(histories, h)
-	}
-	err = rows.Err()
-	if err != nil {
-		return nil, err
-	}
-	return histories, nil
-}
-
 // CreateDiscountCoupon inserts a new discount coupon row for a user when
 // the user does not already own a coupon with this discount_id.
 // Returns (coupon, true, nil) when created; (nil, false, nil) when already owned.
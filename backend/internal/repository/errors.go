@@ -1,6 +1,33 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
 
 // ErrNotFound indicates the requested record does not exist.
 var ErrNotFound = errors.New("record not found")
+
+// ErrAlreadyExists indicates a record with the same unique key already exists.
+var ErrAlreadyExists = errors.New("record already exists")
+
+// ErrInUse indicates a record cannot be deleted because other records still reference it.
+var ErrInUse = errors.New("record still referenced")
+
+// isForeignKeyViolation reports whether err is a PostgreSQL foreign_key_violation.
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique_violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
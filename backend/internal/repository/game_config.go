@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+// activeGameConfigVersion selects the version that was activated last.
+const activeGameConfigVersion = `
+SELECT version FROM game_config_versions
+WHERE activated_at IS NOT NULL
+ORDER BY activated_at DESC, version DESC
+LIMIT 1`
+
+// InsertGameConfigVersion stores cfg and its tracks as the next version number and fills in Version
+// and CreatedAt. The new version is not served until ActivateGameConfigVersion is called.
+func (r *PGRepository) InsertGameConfigVersion(ctx context.Context, tx pgx.Tx, cfg *models.GameConfigVersion) error {
+	// Serialise concurrent imports so two admins cannot both take MAX+1.
+	if _, err := tx.Exec(ctx, `LOCK TABLE game_config_versions IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	const stmt = `
+INSERT INTO game_config_versions (version, source, levels_csv, created_by, created_at)
+SELECT COALESCE(MAX(version), 0) + 1, $1, $2, $3, NOW()
+FROM game_config_versions
+RETURNING version, created_at`
+
+	if err := tx.QueryRow(ctx, stmt, cfg.Source, cfg.LevelsCSV, cfg.CreatedBy).
+		Scan(&cfg.Version, &cfg.CreatedAt); err != nil {
+		return err
+	}
+
+	const trackStmt = `
+INSERT INTO game_config_tracks (version, track_id, tempo, time_signature, sheet_csv)
+VALUES ($1, $2, $3, $4, $5)`
+
+	for _, track := range cfg.Tracks {
+		if _, err := tx.Exec(ctx, trackStmt,
+			cfg.Version, track.TrackID, track.Tempo, track.TimeSignature, track.SheetCSV); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ActivateGameConfigVersion makes version the served config. Returns ErrNotFound if missing.
+func (r *PGRepository) ActivateGameConfigVersion(ctx context.Context, tx pgx.Tx, version int) error {
+	const stmt = `
+UPDATE game_config_versions
+SET activated_at = NOW()
+WHERE version = $1`
+
+	tag, err := tx.Exec(ctx, stmt, version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetActiveGameConfigVersion returns the served config with its CSV and tracks. Returns ErrNotFound
+// before any version has been activated.
+func (r *PGRepository) GetActiveGameConfigVersion(ctx context.Context, tx pgx.Tx) (*models.GameConfigVersion, error) {
+	const query = `
+SELECT version, source, levels_csv, created_by, created_at, activated_at
+FROM game_config_versions
+WHERE version = (` + activeGameConfigVersion + `)`
+
+	cfg, err := scanGameConfigVersion(tx.QueryRow(ctx, query))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Active = true
+	if cfg.Tracks, err = r.listGameConfigTracks(ctx, tx, cfg.Version); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// GetGameConfigVersion returns one version with its CSV and tracks. Returns ErrNotFound if missing.
+func (r *PGRepository) GetGameConfigVersion(
+	ctx context.Context,
+	tx pgx.Tx,
+	version int,
+) (*models.GameConfigVersion, error) {
+	const query = `
+SELECT version, source, levels_csv, created_by, created_at, activated_at
+FROM game_config_versions
+WHERE version = $1`
+
+	cfg, err := scanGameConfigVersion(tx.QueryRow(ctx, query, version))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Tracks, err = r.listGameConfigTracks(ctx, tx, cfg.Version); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ListGameConfigVersions returns every version with its track settings but without CSV, newest first.
+func (r *PGRepository) ListGameConfigVersions(ctx context.Context, tx pgx.Tx) ([]models.GameConfigVersion, error) {
+	const query = `
+SELECT version, source, created_by, created_at, activated_at,
+       version IS NOT DISTINCT FROM (` + activeGameConfigVersion + `)
+FROM game_config_versions
+ORDER BY version DESC`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []models.GameConfigVersion{}
+	for rows.Next() {
+		var v models.GameConfigVersion
+		if err = rows.Scan(&v.Version, &v.Source, &v.CreatedBy, &v.CreatedAt, &v.ActivatedAt, &v.Active); err != nil {
+			return nil, err
+		}
+		v.Tracks = []models.GameConfigTrack{}
+		versions = append(versions, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	const trackQuery = `
+SELECT version, track_id, tempo, time_signature
+FROM game_config_tracks
+ORDER BY version, track_id`
+
+	trackRows, err := tx.Query(ctx, trackQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer trackRows.Close()
+
+	index := make(map[int]int, len(versions))
+	for i, v := range versions {
+		index[v.Version] = i
+	}
+	for trackRows.Next() {
+		var (
+			version int
+			track   models.GameConfigTrack
+		)
+		if err = trackRows.Scan(&version, &track.TrackID, &track.Tempo, &track.TimeSignature); err != nil {
+			return nil, err
+		}
+		if i, ok := index[version]; ok {
+			versions[i].Tracks = append(versions[i].Tracks, track)
+		}
+	}
+	return versions, trackRows.Err()
+}
+
+func (r *PGRepository) listGameConfigTracks(
+	ctx context.Context,
+	tx pgx.Tx,
+	version int,
+) ([]models.GameConfigTrack, error) {
+	const query = `
+SELECT track_id, tempo, time_signature, sheet_csv
+FROM game_config_tracks
+WHERE version = $1
+ORDER BY track_id`
+
+	rows, err := tx.Query(ctx, query, version)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tracks := []models.GameConfigTrack{}
+	for rows.Next() {
+		var track models.GameConfigTrack
+		if err = rows.Scan(&track.TrackID, &track.Tempo, &track.TimeSignature, &track.SheetCSV); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, rows.Err()
+}
+
+func scanGameConfigVersion(row pgx.Row) (*models.GameConfigVersion, error) {
+	var v models.GameConfigVersion
+	if err := row.Scan(
+		&v.Version,
+		&v.Source,
+		&v.LevelsCSV,
+		&v.CreatedBy,
+		&v.CreatedAt,
+		&v.ActivatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
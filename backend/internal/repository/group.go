@@ -88,6 +88,16 @@ ON CONFLICT DO NOTHING`
 	return ct.RowsAffected() > 0, nil
 }
 
+// CountGroupCheckIns counts the group check-ins the user took part in.
+func (r *PGRepository) CountGroupCheckIns(ctx context.Context, tx pgx.Tx, userID string) (int, error) {
+	const query = `SELECT COUNT(*) FROM group_check_ins WHERE user_a_id = $1 OR user_b_id = $1`
+	var count int
+	if err := tx.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // ListGroupCheckInsByUser returns all group_check_ins records involving a given user.
 func (r *PGRepository) ListGroupCheckInsByUser(ctx context.Context, tx pgx.Tx, userID string) ([]models.GroupCheckIn, error) {
 	const query = `
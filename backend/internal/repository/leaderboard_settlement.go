@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+// GetActiveFreeze returns the freeze on a board. Returns ErrNotFound if the board is live.
+func (r *PGRepository) GetActiveFreeze(ctx context.Context, tx pgx.Tx, board Board) (*models.LeaderboardFreeze, error) {
+	const query = `SELECT id, board, frozen_at FROM leaderboard_freezes WHERE board = $1`
+
+	var f models.LeaderboardFreeze
+	if err := tx.QueryRow(ctx, query, string(board)).Scan(&f.ID, &f.Board, &f.FrozenAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &f, nil
+}
+
+// CreateFreeze pins the current standings of an unscoped board.
+// Returns ErrAlreadyExists if the board is already frozen.
+func (r *PGRepository) CreateFreeze(
+	ctx context.Context,
+	tx pgx.Tx,
+	board Board,
+	frozenAt time.Time,
+) (*models.LeaderboardFreeze, error) {
+	strategy, ok := LookupRankingStrategy(board)
+	if !ok {
+		return nil, ErrUnknownBoard
+	}
+
+	const stmt = `
+INSERT INTO leaderboard_freezes (id, board, frozen_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (board) DO NOTHING`
+
+	freeze := models.LeaderboardFreeze{ID: uuid.NewString(), Board: string(board), FrozenAt: frozenAt}
+	tag, err := tx.Exec(ctx, stmt, freeze.ID, freeze.Board, freeze.FrozenAt)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrAlreadyExists
+	}
+
+	copyStmt := strategy.rankedCTE() + `
+INSERT INTO leaderboard_freeze_entries (freeze_id, user_id, position, rank, score, current_level, last_pass_time)
+SELECT @freeze, id, rn, rank, score, current_level, last_pass_time
+FROM ranked`
+
+	if _, err = tx.Exec(ctx, copyStmt, pgx.NamedArgs{"freeze": freeze.ID, "viewer": nil}); err != nil {
+		return nil, err
+	}
+	return &freeze, nil
+}
+
+// DeleteFreeze returns a board to live standings. Returns ErrNotFound if it was not frozen.
+func (r *PGRepository) DeleteFreeze(ctx context.Context, tx pgx.Tx, board Board) error {
+	const stmt = `DELETE FROM leaderboard_freezes WHERE board = $1`
+
+	tag, err := tx.Exec(ctx, stmt, string(board))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListFreezeEntries returns the frozen standings in ranking order.
+// Profile fields come from the live users row; ranking fields come from the freeze.
+func (r *PGRepository) ListFreezeEntries(ctx context.Context, tx pgx.Tx, freezeID string) ([]RankedUser, error) {
+	const query = `
+SELECT u.id, u.nickname, u.avatar, e.current_level, u.unlock_level, u.namecard_bio, u.namecard_links, u.namecard_email,
+       e.last_pass_time, e.score, e.rank
+FROM leaderboard_freeze_entries e
+JOIN users u ON u.id = e.user_id
+WHERE e.freeze_id = @freeze
+ORDER BY e.position`
+
+	return queryRankedUsers(ctx, tx, query, pgx.NamedArgs{"freeze": freezeID})
+}
+
+// ListUserIDsWithDiscount reports which of userIDs already hold a coupon with discountID.
+func (r *PGRepository) ListUserIDsWithDiscount(
+	ctx context.Context,
+	tx pgx.Tx,
+	discountID string,
+	userIDs []string,
+) (map[string]bool, error) {
+	const query = `
+SELECT DISTINCT user_id
+FROM discount_coupons
+WHERE discount_id = $1 AND user_id = ANY($2::uuid[])`
+
+	rows, err := tx.Query(ctx, query, discountID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InsertLeaderboardSettlement stores a settlement run together with its entries.
+func (r *PGRepository) InsertLeaderboardSettlement(
+	ctx context.Context,
+	tx pgx.Tx,
+	settlement *models.LeaderboardSettlement,
+) error {
+	const stmt = `
+INSERT INTO leaderboard_settlements (id, board, discount_id, amount, rank_limit, tie_policy, frozen_at,
+                                     trigger, eligible_count, issued_count, settled_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := tx.Exec(ctx, stmt,
+		settlement.ID,
+		settlement.Board,
+		settlement.DiscountID,
+		settlement.Amount,
+		settlement.RankLimit,
+		settlement.TiePolicy,
+		settlement.FrozenAt,
+		settlement.Trigger,
+		settlement.EligibleCount,
+		settlement.IssuedCount,
+		settlement.SettledAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	const entryStmt = `
+INSERT INTO leaderboard_settlement_entries (settlement_id, user_id, rank, score, tie_size, outcome, coupon_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	batch := &pgx.Batch{}
+	for _, e := range settlement.Entries {
+		batch.Queue(entryStmt, settlement.ID, e.UserID, e.Rank, e.Score, e.TieSize, e.Outcome, e.CouponID)
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// ListLeaderboardSettlements returns the most recent settlement runs with their entries, newest first.
+func (r *PGRepository) ListLeaderboardSettlements(
+	ctx context.Context,
+	tx pgx.Tx,
+	limit int,
+) ([]models.LeaderboardSettlement, error) {
+	const query = `
+SELECT id, board, discount_id, amount, rank_limit, tie_policy, frozen_at,
+       trigger, eligible_count, issued_count, settled_at
+FROM leaderboard_settlements
+ORDER BY settled_at DESC
+LIMIT $1`
+
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LeaderboardSettlement
+	index := make(map[string]int)
+	for rows.Next() {
+		var s models.LeaderboardSettlement
+		if err = rows.Scan(
+			&s.ID,
+			&s.Board,
+			&s.DiscountID,
+			&s.Amount,
+			&s.RankLimit,
+			&s.TiePolicy,
+			&s.FrozenAt,
+			&s.Trigger,
+			&s.EligibleCount,
+			&s.IssuedCount,
+			&s.SettledAt,
+		); err != nil {
+			return nil, err
+		}
+		s.Entries = []models.LeaderboardSettlementEntry{}
+		index[s.ID] = len(out)
+		out = append(out, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	ids := make([]string, 0, len(out))
+	for _, s := range out {
+		ids = append(ids, s.ID)
+	}
+
+	const entryQuery = `
+SELECT e.settlement_id, e.user_id, u.nickname, e.rank, e.score, e.tie_size, e.outcome, e.coupon_id
+FROM leaderboard_settlement_entries e
+JOIN users u ON u.id = e.user_id
+WHERE e.settlement_id = ANY($1::uuid[])
+ORDER BY e.rank, u.nickname`
+
+	entryRows, err := tx.Query(ctx, entryQuery, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer entryRows.Close()
+
+	for entryRows.Next() {
+		var e models.LeaderboardSettlementEntry
+		if err = entryRows.Scan(
+			&e.SettlementID,
+			&e.UserID,
+			&e.Nickname,
+			&e.Rank,
+			&e.Score,
+			&e.TieSize,
+			&e.Outcome,
+			&e.CouponID,
+		); err != nil {
+			return nil, err
+		}
+		i := index[e.SettlementID]
+		out[i].Entries = append(out[i].Entries, e)
+	}
+	if err = entryRows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+const levelRunColumns = `id, user_id, session_id, level, score, accuracy, max_combo,
+       perfect_count, great_count, miss_count, cleared, created_at`
+
+// InsertLevelRun stores a submission that passed replay, cleared or not.
+func (r *PGRepository) InsertLevelRun(ctx context.Context, tx pgx.Tx, run *models.LevelRun) error {
+	const stmt = `
+INSERT INTO level_runs (id, user_id, session_id, level, score, accuracy, max_combo,
+                        perfect_count, great_count, miss_count, cleared, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := tx.Exec(ctx, stmt,
+		run.ID,
+		run.UserID,
+		run.SessionID,
+		run.Level,
+		run.Score,
+		run.Accuracy,
+		run.MaxCombo,
+		run.Perfect,
+		run.Great,
+		run.Miss,
+		run.Cleared,
+		run.CreatedAt,
+	)
+	return err
+}
+
+// GetPersonalBest returns the user's highest-scoring cleared run on a level.
+// Ties go to the earlier run. Returns ErrNotFound if the user has not cleared that level.
+func (r *PGRepository) GetPersonalBest(ctx context.Context, tx pgx.Tx, userID string, level int) (*models.LevelRun, error) {
+	const query = `
+SELECT ` + levelRunColumns + `
+FROM level_runs
+WHERE user_id = $1 AND level = $2 AND cleared
+ORDER BY score DESC, created_at ASC
+LIMIT 1`
+
+	run, err := scanLevelRun(tx.QueryRow(ctx, query, userID, level))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+// ListPersonalBests returns the user's best run for every level they have cleared, ordered by level.
+func (r *PGRepository) ListPersonalBests(ctx context.Context, tx pgx.Tx, userID string) ([]models.LevelRun, error) {
+	const query = `
+SELECT DISTINCT ON (level) ` + levelRunColumns + `
+FROM level_runs
+WHERE user_id = $1 AND cleared
+ORDER BY level ASC, score DESC, created_at ASC`
+
+	return queryLevelRuns(ctx, tx, query, userID)
+}
+
+// ListLevelRuns returns the user's most recent runs on a level, newest first.
+func (r *PGRepository) ListLevelRuns(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID string,
+	level int,
+	limit int,
+) ([]models.LevelRun, error) {
+	const query = `
+SELECT ` + levelRunColumns + `
+FROM level_runs
+WHERE user_id = $1 AND level = $2
+ORDER BY created_at DESC
+LIMIT $3`
+
+	return queryLevelRuns(ctx, tx, query, userID, level, limit)
+}
+
+func queryLevelRuns(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]models.LevelRun, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.LevelRun
+	for rows.Next() {
+		run, scanErr := scanLevelRun(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		out = append(out, *run)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func scanLevelRun(row pgx.Row) (*models.LevelRun, error) {
+	var run models.LevelRun
+	if err := row.Scan(
+		&run.ID,
+		&run.UserID,
+		&run.SessionID,
+		&run.Level,
+		&run.Score,
+		&run.Accuracy,
+		&run.MaxCombo,
+		&run.Perfect,
+		&run.Great,
+		&run.Miss,
+		&run.Cleared,
+		&run.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+// InsertNotification adds n to its user's inbox, filling in ID and CreatedAt.
+func (r *PGRepository) InsertNotification(ctx context.Context, tx pgx.Tx, n *models.Notification) error {
+	const stmt = `
+INSERT INTO notifications (id, user_id, kind, data, created_at)
+VALUES ($1, $2, $3, $4, $5)`
+
+	n.ID = uuid.NewString()
+	n.CreatedAt = time.Now().UTC()
+	_, err := tx.Exec(ctx, stmt, n.ID, n.UserID, n.Kind, n.Data, n.CreatedAt)
+	return err
+}
+
+// ListNotifications returns the user's newest notifications first, at most limit rows.
+func (r *PGRepository) ListNotifications(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID string,
+	limit int,
+) ([]models.Notification, error) {
+	const query = `
+SELECT id, user_id, kind, data, read_at, created_at
+FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC, id ASC
+LIMIT $2`
+
+	rows, err := tx.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.Notification, 0)
+	for rows.Next() {
+		var n models.Notification
+		if err = rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Data, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CountUnreadNotifications returns how many of the user's notifications have not been read.
+func (r *PGRepository) CountUnreadNotifications(ctx context.Context, tx pgx.Tx, userID string) (int, error) {
+	const query = `
+SELECT COUNT(*)
+FROM notifications
+WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := tx.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkNotificationsRead marks the user's unread notifications in ids as read, or all of them when ids is nil.
+// IDs that belong to other users are ignored. Returns the number of rows changed.
+func (r *PGRepository) MarkNotificationsRead(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID string,
+	ids []string,
+	readAt time.Time,
+) (int64, error) {
+	const stmt = `
+UPDATE notifications
+SET read_at = $3
+WHERE user_id = $1
+  AND read_at IS NULL
+  AND ($2::uuid[] IS NULL OR id = ANY($2::uuid[]))`
+
+	tag, err := tx.Exec(ctx, stmt, userID, ids, readAt)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteNotificationsBefore removes notifications created before cutoff.
+func (r *PGRepository) DeleteNotificationsBefore(ctx context.Context, tx pgx.Tx, cutoff time.Time) (int64, error) {
+	const stmt = `
+DELETE FROM notifications
+WHERE created_at < $1`
+
+	tag, err := tx.Exec(ctx, stmt, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
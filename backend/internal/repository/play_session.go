@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+// InsertPlaySession stores a newly opened play session.
+func (r *PGRepository) InsertPlaySession(ctx context.Context, tx pgx.Tx, session *models.PlaySession) error {
+	const stmt = `
+INSERT INTO play_sessions (id, user_id, level, nonce, sheet_hash, expires_at, consumed_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, NULL, $7)`
+
+	_, err := tx.Exec(ctx, stmt,
+		session.ID,
+		session.UserID,
+		session.Level,
+		session.Nonce,
+		session.SheetHash,
+		session.ExpiresAt,
+		session.CreatedAt,
+	)
+	return err
+}
+
+// GetPlaySessionForUpdate fetches a play session with a row lock. Returns ErrNotFound if missing.
+func (r *PGRepository) GetPlaySessionForUpdate(ctx context.Context, tx pgx.Tx, id string) (*models.PlaySession, error) {
+	const query = `
+SELECT id, user_id, level, nonce, sheet_hash, expires_at, consumed_at, created_at
+FROM play_sessions
+WHERE id = $1
+FOR UPDATE`
+
+	var s models.PlaySession
+	if err := tx.QueryRow(ctx, query, id).Scan(
+		&s.ID,
+		&s.UserID,
+		&s.Level,
+		&s.Nonce,
+		&s.SheetHash,
+		&s.ExpiresAt,
+		&s.ConsumedAt,
+		&s.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ConsumePlaySession marks a play session as used. Returns ErrNotFound if it was already consumed.
+func (r *PGRepository) ConsumePlaySession(ctx context.Context, tx pgx.Tx, id string) error {
+	const stmt = `
+UPDATE play_sessions
+SET consumed_at = NOW()
+WHERE id = $1 AND consumed_at IS NULL`
+
+	tag, err := tx.Exec(ctx, stmt, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// InsertPlayRejection stores a hit log that failed replay for later review.
+func (r *PGRepository) InsertPlayRejection(ctx context.Context, tx pgx.Tx, rejection *models.PlayRejection) error {
+	const stmt = `
+INSERT INTO play_rejections (id, session_id, user_id, level, reason, hit_log, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := tx.Exec(ctx, stmt,
+		rejection.ID,
+		rejection.SessionID,
+		rejection.UserID,
+		rejection.Level,
+		rejection.Reason,
+		rejection.HitLog,
+		rejection.CreatedAt,
+	)
+	return err
+}
+
+// DeleteStalePlaySessions removes never-submitted play sessions that expired before cutoff.
+func (r *PGRepository) DeleteStalePlaySessions(ctx context.Context, tx pgx.Tx, cutoff time.Time) (int64, error) {
+	const stmt = `
+DELETE FROM play_sessions
+WHERE consumed_at IS NULL AND expires_at < $1`
+
+	tag, err := tx.Exec(ctx, stmt, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ConsumeQRTokenNonce records a one-time QR token as used for purpose.
+// Returns ErrAlreadyExists if the same token was already consumed for that purpose.
+func (r *PGRepository) ConsumeQRTokenNonce(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID string,
+	purpose string,
+	nonce string,
+	expiresAt time.Time,
+) error {
+	const stmt = `
+INSERT INTO qr_token_nonces (user_id, purpose, nonce, used_at, expires_at)
+VALUES ($1, $2, $3, NOW(), $4)
+ON CONFLICT DO NOTHING`
+
+	tag, err := tx.Exec(ctx, stmt, userID, purpose, nonce, expiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+// DeleteExpiredQRTokenNonces removes nonces whose tokens can no longer verify.
+func (r *PGRepository) DeleteExpiredQRTokenNonces(ctx context.Context, tx pgx.Tx, now time.Time) (int64, error) {
+	const stmt = `
+DELETE FROM qr_token_nonces
+WHERE expires_at < $1`
+
+	tag, err := tx.Exec(ctx, stmt, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -5,104 +5,111 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/sitcon-tw/2026-game/internal/models"
 )
 
-// GetTopUsers returns users ordered by pass level, unlock level, then last_pass_time with pagination.
-func (r *PGRepository) GetTopUsers(ctx context.Context, tx pgx.Tx, limit, offset int) ([]RankedUser, error) {
-	const query = `
-WITH ranked AS (
-	    SELECT id, nickname, avatar, unlock_level, current_level, namecard_bio, namecard_links, namecard_email, last_pass_time,
-	           RANK() OVER (ORDER BY current_level DESC, unlock_level DESC, last_pass_time ASC) AS rank
-	    FROM users
-)
-SELECT id, nickname, avatar, current_level, namecard_bio, namecard_links, namecard_email, last_pass_time, rank
+// ErrUnknownBoard is returned when a board has no registered ranking strategy.
+var ErrUnknownBoard = errors.New("unknown leaderboard board")
+
+const rankedColumns = `id, nickname, avatar, current_level, unlock_level, namecard_bio, namecard_links, namecard_email, last_pass_time, score, rank`
+
+// GetTopUsers returns a page of the board as seen by viewerID.
+func (r *PGRepository) GetTopUsers(
+	ctx context.Context,
+	tx pgx.Tx,
+	board Board,
+	viewerID string,
+	limit, offset int,
+) ([]RankedUser, error) {
+	strategy, ok := LookupRankingStrategy(board)
+	if !ok {
+		return nil, ErrUnknownBoard
+	}
+
+	query := strategy.rankedCTE() + `
+SELECT ` + rankedColumns + `
 FROM ranked
-ORDER BY current_level DESC, unlock_level DESC, last_pass_time ASC, id ASC
-LIMIT $1 OFFSET $2`
+ORDER BY rn
+LIMIT @limit OFFSET @offset`
 
-	rows, err := tx.Query(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
+	return queryRankedUsers(ctx, tx, query, pgx.NamedArgs{
+		"viewer": viewerID,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ListRankedUsers returns the whole board in ranking order. Only meaningful for unscoped boards.
+func (r *PGRepository) ListRankedUsers(ctx context.Context, tx pgx.Tx, board Board) ([]RankedUser, error) {
+	strategy, ok := LookupRankingStrategy(board)
+	if !ok {
+		return nil, ErrUnknownBoard
 	}
-	defer rows.Close()
 
-	var out []RankedUser
-	for rows.Next() {
-		var ru RankedUser
-		err = rows.Scan(
-			&ru.User.ID,
-			&ru.User.Nickname,
-			&ru.User.Avatar,
-			&ru.User.CurrentLevel,
-			&ru.User.NamecardBio,
-			&ru.User.NamecardLinks,
-			&ru.User.NamecardEmail,
-			&ru.User.LastPassTime,
-			&ru.Rank,
-		)
-		if err != nil {
-			return nil, err
-		}
-		out = append(out, ru)
+	query := strategy.rankedCTE() + `
+SELECT ` + rankedColumns + `
+FROM ranked
+ORDER BY rn`
+
+	return queryRankedUsers(ctx, tx, query, pgx.NamedArgs{"viewer": nil})
+}
+
+// GetUserWithRank fetches a user with their score and rank on the board.
+// Returns nil when the user is not on the board.
+func (r *PGRepository) GetUserWithRank(
+	ctx context.Context,
+	tx pgx.Tx,
+	board Board,
+	userID string,
+) (*RankedUser, error) {
+	strategy, ok := LookupRankingStrategy(board)
+	if !ok {
+		return nil, ErrUnknownBoard
 	}
-	err = rows.Err()
+
+	query := strategy.rankedCTE() + `
+SELECT ` + rankedColumns + `
+FROM ranked
+WHERE id = @viewer`
+
+	rows, err := queryRankedUsers(ctx, tx, query, pgx.NamedArgs{"viewer": userID})
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
 }
 
-// GetUserWithRank fetches a user and their rank.
-// Ranking rule: higher pass level first, then higher unlock level, then earlier last_pass_time.
-func (r *PGRepository) GetUserWithRank(ctx context.Context, tx pgx.Tx, userID string) (*models.User, int, error) {
-	const query = `
-SELECT id, nickname, avatar, current_level, namecard_bio, namecard_links, namecard_email, last_pass_time, rank FROM (
-	    SELECT id, nickname, avatar, unlock_level, current_level, namecard_bio, namecard_links, namecard_email, last_pass_time,
-	           RANK() OVER (ORDER BY current_level DESC, unlock_level DESC, last_pass_time ASC) AS rank
-	    FROM users
-) ranked
-WHERE id = $1`
-
-	var u models.User
-	var rank int
-	err := tx.QueryRow(ctx, query, userID).Scan(
-		&u.ID,
-		&u.Nickname,
-		&u.Avatar,
-		&u.CurrentLevel,
-		&u.NamecardBio,
-		&u.NamecardLinks,
-		&u.NamecardEmail,
-		&u.LastPassTime,
-		&rank,
-	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, 0, nil
-		}
-		return nil, 0, err
+// GetAroundUsers returns users within ±span rows around the given user on the board.
+func (r *PGRepository) GetAroundUsers(
+	ctx context.Context,
+	tx pgx.Tx,
+	board Board,
+	userID string,
+	span int,
+) ([]RankedUser, error) {
+	strategy, ok := LookupRankingStrategy(board)
+	if !ok {
+		return nil, ErrUnknownBoard
 	}
-	return &u, rank, nil
-}
 
-// GetAroundUsers returns users within ±span rows around the given user.
-func (r *PGRepository) GetAroundUsers(ctx context.Context, tx pgx.Tx, userID string, span int) ([]RankedUser, error) {
-	const query = `
-WITH ranked AS (
-	    SELECT id, nickname, avatar, unlock_level, current_level, namecard_bio, namecard_links, namecard_email, last_pass_time,
-	           RANK() OVER (ORDER BY current_level DESC, unlock_level DESC, last_pass_time ASC) AS rank,
-	           ROW_NUMBER() OVER (ORDER BY current_level DESC, unlock_level DESC, last_pass_time ASC, id ASC) AS rn
-	    FROM users
-), my_row AS (
-    SELECT rn FROM ranked WHERE id = $1
+	query := strategy.rankedCTE() + `, my_row AS (
+    SELECT rn FROM ranked WHERE id = @viewer
 )
-SELECT id, nickname, avatar, current_level, namecard_bio, namecard_links, namecard_email, last_pass_time, rank
+SELECT ` + rankedColumns + `
 FROM ranked, my_row
-WHERE ranked.rn BETWEEN my_row.rn - $2 AND my_row.rn + $2
+WHERE ranked.rn BETWEEN my_row.rn - @span AND my_row.rn + @span
 ORDER BY ranked.rn`
 
-	rows, err := tx.Query(ctx, query, userID, span)
+	return queryRankedUsers(ctx, tx, query, pgx.NamedArgs{
+		"viewer": userID,
+		"span":   span,
+	})
+}
+
+func queryRankedUsers(ctx context.Context, tx pgx.Tx, query string, args pgx.NamedArgs) ([]RankedUser, error) {
+	rows, err := tx.Query(ctx, query, args)
 	if err != nil {
 		return nil, err
 	}
@@ -116,10 +123,12 @@ ORDER BY ranked.rn`
 			&ru.User.Nickname,
 			&ru.User.Avatar,
 			&ru.User.CurrentLevel,
+			&ru.User.UnlockLevel,
 			&ru.User.NamecardBio,
 			&ru.User.NamecardLinks,
 			&ru.User.NamecardEmail,
 			&ru.User.LastPassTime,
+			&ru.Score,
 			&ru.Rank,
 		)
 		if err != nil {
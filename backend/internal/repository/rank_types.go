@@ -3,7 +3,115 @@ package repository
 import "github.com/sitcon-tw/2026-game/internal/models"
 
 // RankedUser carries leaderboard row data with computed rank.
+// Score is the value the board ranks on (level, visited activities, friends, ...).
 type RankedUser struct {
-	User models.User
-	Rank int
+	User  models.User
+	Score int
+	Rank  int
+}
+
+// Board names a leaderboard.
+type Board string
+
+// Board values.
+const (
+	BoardOverall     Board = "overall"
+	BoardFriends     Board = "friends"
+	BoardGroup       Board = "group"
+	BoardActivities  Board = "activities"
+	BoardMostFriends Board = "most_friends"
+)
+
+// progressOrder is the classic game ranking: higher pass level, then higher unlock level, then earlier pass.
+const progressOrder = `current_level DESC, unlock_level DESC, last_pass_time ASC`
+
+// ProgressAhead reports whether a ranks strictly ahead of b under progressOrder.
+func ProgressAhead(a, b models.User) bool {
+	if a.CurrentLevel != b.CurrentLevel {
+		return a.CurrentLevel > b.CurrentLevel
+	}
+	if a.UnlockLevel != b.UnlockLevel {
+		return a.UnlockLevel > b.UnlockLevel
+	}
+	return a.LastPassTime.Before(b.LastPassTime)
+}
+
+// RankingStrategy describes how a board picks its users and orders them.
+// The SQL fragments are expanded inside the shared ranking query; @viewer is the requesting user.
+type RankingStrategy struct {
+	Board Board
+	// score is an expression over users u that becomes the board score.
+	score string
+	// order is the ranking window order; it may reference score.
+	order string
+	// scope filters candidate users. Empty means every user is on the board.
+	scope string
+}
+
+// Scoped reports whether the board depends on who is looking at it.
+// Scoped boards cannot be settled globally.
+func (s RankingStrategy) Scoped() bool {
+	return s.scope != ""
+}
+
+//nolint:gochecknoglobals // static board registry
+var rankingStrategies = map[Board]RankingStrategy{
+	BoardOverall: {
+		Board: BoardOverall,
+		score: `u.current_level`,
+		order: progressOrder,
+	},
+	BoardFriends: {
+		Board: BoardFriends,
+		score: `u.current_level`,
+		order: progressOrder,
+		scope: `u.id = @viewer OR u.id IN (SELECT friend_id FROM friends WHERE user_id = @viewer)`,
+	},
+	BoardGroup: {
+		Board: BoardGroup,
+		score: `u.current_level`,
+		order: progressOrder,
+		scope: `u.id = @viewer OR u."group" = (SELECT "group" FROM users WHERE id = @viewer)`,
+	},
+	BoardActivities: {
+		Board: BoardActivities,
+		score: `(SELECT COUNT(*) FROM visits v WHERE v.user_id = u.id)`,
+		order: `score DESC, ` + progressOrder,
+	},
+	BoardMostFriends: {
+		Board: BoardMostFriends,
+		score: `(SELECT COUNT(*) FROM friends f WHERE f.user_id = u.id)`,
+		order: `score DESC, ` + progressOrder,
+	},
+}
+
+// LookupRankingStrategy returns the strategy for a board name.
+func LookupRankingStrategy(board Board) (RankingStrategy, bool) {
+	s, ok := rankingStrategies[board]
+	return s, ok
+}
+
+// Boards lists every registered board in a stable order.
+func Boards() []Board {
+	return []Board{BoardOverall, BoardFriends, BoardGroup, BoardActivities, BoardMostFriends}
+}
+
+// rankedCTE expands the strategy into a CTE named ranked with rank and row-number columns.
+func (s RankingStrategy) rankedCTE() string {
+	scope := "TRUE"
+	if s.scope != "" {
+		scope = s.scope
+	}
+	return `
+WITH scored AS (
+	    SELECT u.id, u.nickname, u.avatar, u.unlock_level, u.current_level, u.namecard_bio, u.namecard_links,
+	           u.namecard_email, u.last_pass_time, (` + s.score + `)::int AS score
+	    FROM users u
+	    WHERE ` + scope + `
+), ranked AS (
+	    SELECT scored.*,
+	           RANK() OVER (ORDER BY ` + s.order + `) AS rank,
+	           ROW_NUMBER() OVER (ORDER BY ` + s.order + `, id ASC) AS rn
+	    FROM scored
+)`
 }
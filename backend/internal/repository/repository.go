@@ -38,18 +38,51 @@ type Repository interface {
 	// Game operations
 	IncrementUnlockLevel(ctx context.Context, tx pgx.Tx, userID string) error
 	IncrementUnlockLevelBy(ctx context.Context, tx pgx.Tx, userID string, amount int) error
-	GetTopUsers(ctx context.Context, tx pgx.Tx, limit, offset int) ([]RankedUser, error)
+	GetTopUsers(
+		ctx context.Context,
+		tx pgx.Tx,
+		board Board,
+		viewerID string,
+		limit, offset int,
+	) ([]RankedUser, error)
+	ListRankedUsers(ctx context.Context, tx pgx.Tx, board Board) ([]RankedUser, error)
 	UpdateCurrentLevel(ctx context.Context, tx pgx.Tx, userID string, newLevel int) error
-	GetUserWithRank(ctx context.Context, tx pgx.Tx, userID string) (*models.User, int, error)
+	GetUserWithRank(ctx context.Context, tx pgx.Tx, board Board, userID string) (*RankedUser, error)
 	GetAroundUsers(
 		ctx context.Context,
 		tx pgx.Tx,
+		board Board,
 		userID string,
 		span int,
 	) ([]RankedUser, error)
+	GetActiveFreeze(ctx context.Context, tx pgx.Tx, board Board) (*models.LeaderboardFreeze, error)
+	CreateFreeze(ctx context.Context, tx pgx.Tx, board Board, frozenAt time.Time) (*models.LeaderboardFreeze, error)
+	DeleteFreeze(ctx context.Context, tx pgx.Tx, board Board) error
+	ListFreezeEntries(ctx context.Context, tx pgx.Tx, freezeID string) ([]RankedUser, error)
+	InsertLeaderboardSettlement(ctx context.Context, tx pgx.Tx, settlement *models.LeaderboardSettlement) error
+	ListLeaderboardSettlements(ctx context.Context, tx pgx.Tx, limit int) ([]models.LeaderboardSettlement, error)
+	InsertPlaySession(ctx context.Context, tx pgx.Tx, session *models.PlaySession) error
+	GetPlaySessionForUpdate(ctx context.Context, tx pgx.Tx, id string) (*models.PlaySession, error)
+	ConsumePlaySession(ctx context.Context, tx pgx.Tx, id string) error
+	InsertPlayRejection(ctx context.Context, tx pgx.Tx, rejection *models.PlayRejection) error
+	DeleteStalePlaySessions(ctx context.Context, tx pgx.Tx, cutoff time.Time) (int64, error)
+	ConsumeQRTokenNonce(
+		ctx context.Context,
+		tx pgx.Tx,
+		userID string,
+		purpose string,
+		nonce string,
+		expiresAt time.Time,
+	) error
+	DeleteExpiredQRTokenNonces(ctx context.Context, tx pgx.Tx, now time.Time) (int64, error)
+	InsertLevelRun(ctx context.Context, tx pgx.Tx, run *models.LevelRun) error
+	GetPersonalBest(ctx context.Context, tx pgx.Tx, userID string, level int) (*models.LevelRun, error)
+	ListPersonalBests(ctx context.Context, tx pgx.Tx, userID string) ([]models.LevelRun, error)
+	ListLevelRuns(ctx context.Context, tx pgx.Tx, userID string, level int, limit int) ([]models.LevelRun, error)
 
 	// Activity operations
 	CountVisitedActivities(ctx context.Context, tx pgx.Tx, userID string) (int, error)
+	CountVisitedActivitiesByTypes(ctx context.Context, tx pgx.Tx, userID string, types []models.ActivitiesTypes) (int, error)
 	GetActivityByQRCode(ctx context.Context, tx pgx.Tx, qr string) (*models.Activities, error)
 	GetActivityByID(ctx context.Context, tx pgx.Tx, id string) (*models.Activities, error)
 	GetActivityByToken(ctx context.Context, tx pgx.Tx, token string) (*models.Activities, error)
@@ -57,7 +90,26 @@ type Repository interface {
 	CountVisitedByActivity(ctx context.Context, tx pgx.Tx, activityID string) (int, error)
 	ListActivities(ctx context.Context, tx pgx.Tx) ([]models.Activities, error)
 	ListVisitedActivityIDs(ctx context.Context, tx pgx.Tx, userID string) ([]string, error)
+	CreateActivity(ctx context.Context, tx pgx.Tx, activity *models.Activities) error
+	UpdateActivity(ctx context.Context, tx pgx.Tx, activity *models.Activities) error
+	DeleteActivity(ctx context.Context, tx pgx.Tx, id string) error
+	RotateActivityToken(ctx context.Context, tx pgx.Tx, id string) (*models.Activities, error)
+	RotateActivityQRCode(ctx context.Context, tx pgx.Tx, id string) (*models.Activities, error)
+
+	// Announcement operations
 	ListAnnouncements(ctx context.Context, tx pgx.Tx) ([]models.Announcement, error)
+	GetAnnouncement(ctx context.Context, tx pgx.Tx, id string) (*models.Announcement, error)
+	CreateAnnouncement(ctx context.Context, tx pgx.Tx, item *models.Announcement) error
+	UpdateAnnouncement(ctx context.Context, tx pgx.Tx, item *models.Announcement) error
+	DeleteAnnouncement(ctx context.Context, tx pgx.Tx, id string) error
+
+	// Coupon rule operations
+	ListCouponRules(ctx context.Context, tx pgx.Tx) ([]models.CouponRule, error)
+	GetCouponRule(ctx context.Context, tx pgx.Tx, id string) (*models.CouponRule, error)
+	UpsertCouponRule(ctx context.Context, tx pgx.Tx, rule *models.CouponRule) error
+	DeleteCouponRule(ctx context.Context, tx pgx.Tx, id string) error
+	ReserveCouponStock(ctx context.Context, tx pgx.Tx, discountID string, amount int) (bool, error)
+	ReleaseCouponStock(ctx context.Context, tx pgx.Tx, discountID string, amount int) error
 
 	// Discount operations
 	CreateDiscountCoupon(
@@ -68,10 +120,11 @@ type Repository interface {
 		discountID string,
 	) (*models.DiscountCoupon, bool, error)
 	MarkDiscountUsed(ctx context.Context, tx pgx.Tx, id string, staffID string) (*models.DiscountCoupon, error)
-	MarkDiscountsUsedByUser(
+	MarkDiscountsUsedByIDs(
 		ctx context.Context,
 		tx pgx.Tx,
 		userID string,
+		couponIDs []string,
 		staffID string,
 		historyID string,
 		usedAt time.Time,
@@ -82,9 +135,19 @@ type Repository interface {
 		tx pgx.Tx,
 		discountIDs []string,
 	) (map[string]int, error)
+	ListUserIDsWithDiscount(
+		ctx context.Context,
+		tx pgx.Tx,
+		discountID string,
+		userIDs []string,
+	) (map[string]bool, error)
 	InsertCouponHistory(ctx context.Context, tx pgx.Tx, history *models.CouponHistory) error
 	ListUnusedDiscountsByUser(ctx context.Context, tx pgx.Tx, userID string) ([]models.DiscountCoupon, error)
 	ListCouponHistoryByStaff(ctx context.Context, tx pgx.Tx, staffID string) ([]models.CouponHistory, error)
+	GetCouponHistoryForUpdate(ctx context.Context, tx pgx.Tx, id string) (*models.CouponHistory, error)
+	SumCouponHistoryByStaff(ctx context.Context, tx pgx.Tx, staffID string) (*models.CouponHistoryTotals, error)
+	RestoreDiscountsByHistory(ctx context.Context, tx pgx.Tx, historyID string) ([]models.DiscountCoupon, error)
+	InsertCouponHistoryVoid(ctx context.Context, tx pgx.Tx, void *models.CouponHistoryVoid) error
 	ConsumeDiscountCouponGiftByToken(ctx context.Context, tx pgx.Tx, token string) (*models.DiscountCouponGift, error)
 	InsertDiscountCouponForUser(
 		ctx context.Context,
@@ -99,7 +162,7 @@ type Repository interface {
 		price int,
 		discountID string,
 	) (*models.DiscountCouponGift, error)
-	DeleteDiscountCouponGiftByID(ctx context.Context, tx pgx.Tx, id string) error
+	DeleteDiscountCouponGiftByID(ctx context.Context, tx pgx.Tx, id string) (*models.DiscountCouponGift, error)
 	ListDiscountCouponGifts(ctx context.Context, tx pgx.Tx) ([]models.DiscountCouponGift, error)
 	SearchUsersByNickname(ctx context.Context, tx pgx.Tx, query string, limit int) ([]models.User, error)
 	TryMarkStaffScanCouponIssued(
@@ -115,14 +178,80 @@ type Repository interface {
 		staffID string,
 	) ([]models.StaffQRCouponGrant, error)
 
+	// Notification operations
+	InsertNotification(ctx context.Context, tx pgx.Tx, n *models.Notification) error
+	ListNotifications(ctx context.Context, tx pgx.Tx, userID string, limit int) ([]models.Notification, error)
+	CountUnreadNotifications(ctx context.Context, tx pgx.Tx, userID string) (int, error)
+	MarkNotificationsRead(ctx context.Context, tx pgx.Tx, userID string, ids []string, readAt time.Time) (int64, error)
+	DeleteNotificationsBefore(ctx context.Context, tx pgx.Tx, cutoff time.Time) (int64, error)
+
+	// Scheduled job operations
+	UpsertScheduledJob(ctx context.Context, tx pgx.Tx, job *models.ScheduledJob) error
+	GetDueScheduledJob(ctx context.Context, tx pgx.Tx, now time.Time, names []string) (*models.ScheduledJob, error)
+	ListScheduledJobs(ctx context.Context, tx pgx.Tx) ([]models.ScheduledJob, error)
+	GetScheduledJobForUpdate(ctx context.Context, tx pgx.Tx, name string) (*models.ScheduledJob, error)
+	UpdateScheduledJob(ctx context.Context, tx pgx.Tx, job *models.ScheduledJob) error
+	InsertScheduledJobRun(ctx context.Context, tx pgx.Tx, run *models.ScheduledJobRun) error
+	ListScheduledJobRuns(ctx context.Context, tx pgx.Tx, jobName string, limit int) ([]models.ScheduledJobRun, error)
+
+	// Admin account operations
+	CountAdmins(ctx context.Context, tx pgx.Tx) (int, error)
+	ListAdmins(ctx context.Context, tx pgx.Tx) ([]models.Admin, error)
+	GetAdminByID(ctx context.Context, tx pgx.Tx, id string) (*models.Admin, error)
+	GetAdminByKeyHash(ctx context.Context, tx pgx.Tx, keyHash string) (*models.Admin, error)
+	CreateAdmin(ctx context.Context, tx pgx.Tx, admin *models.Admin) error
+	UpdateAdmin(ctx context.Context, tx pgx.Tx, admin *models.Admin) error
+	SetAdminKeyHash(ctx context.Context, tx pgx.Tx, id string, keyHash string) (*models.Admin, error)
+	DeleteAdmin(ctx context.Context, tx pgx.Tx, id string) error
+	CreateAdminSession(ctx context.Context, tx pgx.Tx, tokenHash string, adminID string, expiresAt time.Time) error
+	GetAdminBySessionHash(ctx context.Context, tx pgx.Tx, tokenHash string, now time.Time) (*models.Admin, error)
+	DeleteAdminSession(ctx context.Context, tx pgx.Tx, tokenHash string) error
+	DeleteAdminSessionsByAdmin(ctx context.Context, tx pgx.Tx, adminID string) (int64, error)
+	DeleteExpiredAdminSessions(ctx context.Context, tx pgx.Tx, now time.Time) (int64, error)
+
+	// Audit log operations
+	InsertAuditEvent(ctx context.Context, tx pgx.Tx, event *models.AuditEvent) error
+	ListAuditEvents(ctx context.Context, tx pgx.Tx, filter AuditEventFilter, fn func(models.AuditEvent) error) error
+
 	// Staff operations
 	GetStaffByToken(ctx context.Context, tx pgx.Tx, token string) (*models.Staff, error)
+	GetStaffByID(ctx context.Context, tx pgx.Tx, id string) (*models.Staff, error)
+	ListStaffs(ctx context.Context, tx pgx.Tx) ([]models.Staff, error)
+	CreateStaff(ctx context.Context, tx pgx.Tx, name string) (*models.Staff, error)
+	UpdateStaffName(ctx context.Context, tx pgx.Tx, id string, name string) (*models.Staff, error)
+	RotateStaffToken(ctx context.Context, tx pgx.Tx, id string) (*models.Staff, error)
+	DeleteStaff(ctx context.Context, tx pgx.Tx, id string) error
+
+	// Staff and booth session operations
+	CreateStaffSession(ctx context.Context, tx pgx.Tx, tokenHash string, session *models.DeviceSession) error
+	GetStaffBySessionHash(ctx context.Context, tx pgx.Tx, tokenHash string, now time.Time) (*models.Staff, time.Time, error)
+	TouchStaffSession(ctx context.Context, tx pgx.Tx, tokenHash string, now time.Time) error
+	ListStaffSessions(ctx context.Context, tx pgx.Tx, staffID string, now time.Time) ([]models.DeviceSession, error)
+	DeleteStaffSession(ctx context.Context, tx pgx.Tx, staffID string, id string) error
+	DeleteStaffSessionByHash(ctx context.Context, tx pgx.Tx, tokenHash string) error
+	DeleteStaffSessionsByStaff(ctx context.Context, tx pgx.Tx, staffID string) (int64, error)
+	CreateBoothSession(ctx context.Context, tx pgx.Tx, tokenHash string, session *models.DeviceSession) error
+	GetActivityBySessionHash(ctx context.Context, tx pgx.Tx, tokenHash string, now time.Time) (*models.Activities, time.Time, error)
+	TouchBoothSession(ctx context.Context, tx pgx.Tx, tokenHash string, now time.Time) error
+	ListBoothSessions(ctx context.Context, tx pgx.Tx, activityID string, now time.Time) ([]models.DeviceSession, error)
+	DeleteBoothSession(ctx context.Context, tx pgx.Tx, activityID string, id string) error
+	DeleteBoothSessionByHash(ctx context.Context, tx pgx.Tx, tokenHash string) error
+	DeleteBoothSessionsByActivity(ctx context.Context, tx pgx.Tx, activityID string) (int64, error)
+	DeleteExpiredDeviceSessions(ctx context.Context, tx pgx.Tx, now time.Time) (int64, error)
+
+	// Game config operations
+	InsertGameConfigVersion(ctx context.Context, tx pgx.Tx, cfg *models.GameConfigVersion) error
+	ActivateGameConfigVersion(ctx context.Context, tx pgx.Tx, version int) error
+	GetActiveGameConfigVersion(ctx context.Context, tx pgx.Tx) (*models.GameConfigVersion, error)
+	GetGameConfigVersion(ctx context.Context, tx pgx.Tx, version int) (*models.GameConfigVersion, error)
+	ListGameConfigVersions(ctx context.Context, tx pgx.Tx) ([]models.GameConfigVersion, error)
 
 	// Group operations
 	ListGroupMembers(ctx context.Context, tx pgx.Tx, userID string, groupName string) ([]models.User, error)
 	GetGroupCheckIn(ctx context.Context, tx pgx.Tx, userIDA, userIDB string) (*models.GroupCheckIn, error)
 	InsertGroupCheckIn(ctx context.Context, tx pgx.Tx, userIDA, userIDB string) (bool, error)
 	ListGroupCheckInsByUser(ctx context.Context, tx pgx.Tx, userID string) ([]models.GroupCheckIn, error)
+	CountGroupCheckIns(ctx context.Context, tx pgx.Tx, userID string) (int, error)
 }
 
 // PGRepository is the production repository backed by pgx.
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+const scheduledJobColumns = `name, status, run_at, lease_until, interval_seconds, attempts, last_run_at, last_error,
+       created_at, updated_at`
+
+// UpsertScheduledJob registers a job. An existing job keeps its state, except that a one-shot job
+// which has never been attempted picks up a changed run_at.
+func (r *PGRepository) UpsertScheduledJob(ctx context.Context, tx pgx.Tx, job *models.ScheduledJob) error {
+	const stmt = `
+INSERT INTO scheduled_jobs (name, status, run_at, interval_seconds, attempts, created_at, updated_at)
+VALUES ($1, $2, $3, $4, 0, NOW(), NOW())
+ON CONFLICT (name) DO UPDATE
+SET interval_seconds = EXCLUDED.interval_seconds,
+    run_at = CASE
+        WHEN scheduled_jobs.interval_seconds IS NULL
+         AND EXCLUDED.interval_seconds IS NULL
+         AND scheduled_jobs.status = 'pending'
+         AND scheduled_jobs.last_run_at IS NULL
+        THEN EXCLUDED.run_at
+        ELSE scheduled_jobs.run_at
+    END,
+    updated_at = NOW()`
+
+	_, err := tx.Exec(ctx, stmt, job.Name, job.Status, job.RunAt, job.IntervalSeconds)
+	return err
+}
+
+// GetDueScheduledJob locks and returns the earliest due job among names: a pending job whose run_at
+// has passed, or a running job whose lease expired because the instance running it went away.
+// Returns ErrNotFound when none is due.
+func (r *PGRepository) GetDueScheduledJob(
+	ctx context.Context,
+	tx pgx.Tx,
+	now time.Time,
+	names []string,
+) (*models.ScheduledJob, error) {
+	const query = `
+SELECT ` + scheduledJobColumns + `
+FROM scheduled_jobs
+WHERE name = ANY($2)
+  AND ((status = 'pending' AND run_at <= $1) OR (status = 'running' AND lease_until <= $1))
+ORDER BY run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED`
+
+	jobs, err := queryScheduledJobs(ctx, tx, query, now, names)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrNotFound
+	}
+	return &jobs[0], nil
+}
+
+// ListScheduledJobs returns every registered job ordered by name.
+func (r *PGRepository) ListScheduledJobs(ctx context.Context, tx pgx.Tx) ([]models.ScheduledJob, error) {
+	const query = `
+SELECT ` + scheduledJobColumns + `
+FROM scheduled_jobs
+ORDER BY name`
+
+	return queryScheduledJobs(ctx, tx, query)
+}
+
+// GetScheduledJobForUpdate fetches a job with a row lock. Returns ErrNotFound if missing.
+func (r *PGRepository) GetScheduledJobForUpdate(ctx context.Context, tx pgx.Tx, name string) (*models.ScheduledJob, error) {
+	const query = `
+SELECT ` + scheduledJobColumns + `
+FROM scheduled_jobs
+WHERE name = $1
+FOR UPDATE`
+
+	jobs, err := queryScheduledJobs(ctx, tx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrNotFound
+	}
+	return &jobs[0], nil
+}
+
+// UpdateScheduledJob persists a job's state after a run, retry or trigger.
+func (r *PGRepository) UpdateScheduledJob(ctx context.Context, tx pgx.Tx, job *models.ScheduledJob) error {
+	const stmt = `
+UPDATE scheduled_jobs
+SET status = $2,
+    run_at = $3,
+    lease_until = $4,
+    attempts = $5,
+    last_run_at = $6,
+    last_error = $7,
+    updated_at = NOW()
+WHERE name = $1`
+
+	tag, err := tx.Exec(ctx, stmt,
+		job.Name, job.Status, job.RunAt, job.LeaseUntil, job.Attempts, job.LastRunAt, job.LastError)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// InsertScheduledJobRun records one execution of a job.
+func (r *PGRepository) InsertScheduledJobRun(ctx context.Context, tx pgx.Tx, run *models.ScheduledJobRun) error {
+	const stmt = `
+INSERT INTO scheduled_job_runs (id, job_name, trigger, scheduled_for, started_at, finished_at, status, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := tx.Exec(ctx, stmt,
+		run.ID,
+		run.JobName,
+		run.Trigger,
+		run.ScheduledFor,
+		run.StartedAt,
+		run.FinishedAt,
+		run.Status,
+		run.Error,
+	)
+	return err
+}
+
+// ListScheduledJobRuns returns the most recent runs of a job, newest first.
+func (r *PGRepository) ListScheduledJobRuns(
+	ctx context.Context,
+	tx pgx.Tx,
+	jobName string,
+	limit int,
+) ([]models.ScheduledJobRun, error) {
+	const query = `
+SELECT id, job_name, trigger, scheduled_for, started_at, finished_at, status, error
+FROM scheduled_job_runs
+WHERE job_name = $1
+ORDER BY started_at DESC
+LIMIT $2`
+
+	rows, err := tx.Query(ctx, query, jobName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.ScheduledJobRun
+	for rows.Next() {
+		var run models.ScheduledJobRun
+		if err = rows.Scan(
+			&run.ID,
+			&run.JobName,
+			&run.Trigger,
+			&run.ScheduledFor,
+			&run.StartedAt,
+			&run.FinishedAt,
+			&run.Status,
+			&run.Error,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, run)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func queryScheduledJobs(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]models.ScheduledJob, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.ScheduledJob
+	for rows.Next() {
+		var job models.ScheduledJob
+		if err = rows.Scan(
+			&job.Name,
+			&job.Status,
+			&job.RunAt,
+			&job.LeaseUntil,
+			&job.IntervalSeconds,
+			&job.Attempts,
+			&job.LastRunAt,
+			&job.LastError,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, job)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
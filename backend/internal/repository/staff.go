@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
 )
 
 // GetStaffByToken finds a staff by token for authentication.
@@ -96,3 +98,119 @@ ORDER BY g.created_at DESC`
 	}
 	return grants, nil
 }
+
+const staffTokenLength = 32
+
+// ListStaffs returns all staff ordered by name.
+func (r *PGRepository) ListStaffs(ctx context.Context, tx pgx.Tx) ([]models.Staff, error) {
+	const query = `
+SELECT id, name, token, created_at, updated_at
+FROM staffs
+ORDER BY name ASC, id ASC`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	staffs := []models.Staff{}
+	for rows.Next() {
+		var s models.Staff
+		if scanErr := rows.Scan(&s.ID, &s.Name, &s.Token, &s.CreatedAt, &s.UpdatedAt); scanErr != nil {
+			return nil, scanErr
+		}
+		staffs = append(staffs, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return staffs, nil
+}
+
+// GetStaffByID finds a staff member by id. Returns ErrNotFound if missing.
+func (r *PGRepository) GetStaffByID(ctx context.Context, tx pgx.Tx, id string) (*models.Staff, error) {
+	const query = `
+SELECT id, name, token, created_at, updated_at
+FROM staffs
+WHERE id = $1`
+
+	return scanStaff(tx.QueryRow(ctx, query, id))
+}
+
+// CreateStaff inserts a staff member with a new id and login token.
+func (r *PGRepository) CreateStaff(ctx context.Context, tx pgx.Tx, name string) (*models.Staff, error) {
+	const stmt = `
+INSERT INTO staffs (id, name, token, created_at, updated_at)
+VALUES ($1, $2, $3, NOW(), NOW())
+RETURNING id, name, token, created_at, updated_at`
+
+	token, err := helpers.RandomAlphabetToken(staffTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	return scanStaff(tx.QueryRow(ctx, stmt, uuid.NewString(), name, token))
+}
+
+// UpdateStaffName renames a staff member. Returns ErrNotFound if missing.
+func (r *PGRepository) UpdateStaffName(ctx context.Context, tx pgx.Tx, id string, name string) (*models.Staff, error) {
+	const stmt = `
+UPDATE staffs
+SET name = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, token, created_at, updated_at`
+
+	return scanStaff(tx.QueryRow(ctx, stmt, id, name))
+}
+
+// RotateStaffToken replaces a staff member's login token and revokes every session opened with
+// the old one. Returns ErrNotFound if missing.
+func (r *PGRepository) RotateStaffToken(ctx context.Context, tx pgx.Tx, id string) (*models.Staff, error) {
+	const stmt = `
+UPDATE staffs
+SET token = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, token, created_at, updated_at`
+
+	token, err := helpers.RandomAlphabetToken(staffTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := scanStaff(tx.QueryRow(ctx, stmt, id, token))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.DeleteStaffSessionsByStaff(ctx, tx, staff.ID); err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// DeleteStaff removes a staff member. Returns ErrNotFound if missing and ErrInUse if the
+// staff member has already redeemed or issued coupons.
+func (r *PGRepository) DeleteStaff(ctx context.Context, tx pgx.Tx, id string) error {
+	const stmt = `DELETE FROM staffs WHERE id = $1`
+
+	tag, err := tx.Exec(ctx, stmt, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrInUse
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanStaff(row pgx.Row) (*models.Staff, error) {
+	var s models.Staff
+	if err := row.Scan(&s.ID, &s.Name, &s.Token, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
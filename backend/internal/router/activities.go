@@ -6,23 +6,32 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/sitcon-tw/2026-game/internal/handler/activities"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"go.uber.org/zap"
 )
 
 // ActivityRoutes wires activity-related endpoints.
-func ActivityRoutes(repo repository.Repository, logger *zap.Logger, sessionRateLimit func(http.Handler) http.Handler) http.Handler {
+func ActivityRoutes(
+	repo repository.Repository,
+	logger *zap.Logger,
+	bus *events.Bus,
+	sessionRateLimit func(http.Handler) http.Handler,
+) http.Handler {
 	r := chi.NewRouter()
 
-	h := activities.New(repo, logger)
+	h := activities.New(repo, logger, bus)
 
 	r.Route("/booth", func(r chi.Router) {
 		r.Post("/session", h.BoothLogin)
+		r.Delete("/session", h.BoothLogout)
 
 		r.Group(func(r chi.Router) {
 			r.Use(middleware.BoothAuth(repo, logger))
 			r.Use(sessionRateLimit)
 			r.Post("/user/check-ins", h.BoothCheckIn)
+			r.Post("/user/check-ins/batch", h.BoothCheckInBatch)
+			r.Get("/offline-bundle", h.BoothOfflineBundle)
 			r.Get("/stats", h.BoothCount)
 		})
 	})
@@ -5,29 +5,118 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/sitcon-tw/2026-game/internal/handler/admin"
+	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/couponsettlement"
+	"github.com/sitcon-tw/2026-game/internal/service/gameconfig"
+	"github.com/sitcon-tw/2026-game/internal/service/scheduler"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"go.uber.org/zap"
 )
 
-// AdminRoutes wires admin-only endpoints.
-func AdminRoutes(repo repository.Repository, logger *zap.Logger, sessionRateLimit func(http.Handler) http.Handler) http.Handler {
+// AdminRoutes wires admin-only endpoints. Every route after login declares the permission it requires.
+func AdminRoutes(
+	repo repository.Repository,
+	logger *zap.Logger,
+	settlement *couponsettlement.Service,
+	sched *scheduler.Scheduler,
+	gameConfig *gameconfig.Service,
+	sessionRateLimit func(http.Handler) http.Handler,
+) http.Handler {
 	r := chi.NewRouter()
-	h := admin.New(repo, logger)
+	h := admin.New(repo, logger, settlement, sched, gameConfig)
 
 	r.Post("/session", h.Login)
 
 	r.Group(func(r chi.Router) {
-		r.Use(middleware.AdminAuth)
+		r.Use(middleware.AdminAuth(repo, logger))
 		r.Use(sessionRateLimit)
 
-		r.Post("/gift-coupons", h.CreateGiftCoupon)
-		r.Delete("/gift-coupons/{id}", h.DeleteGiftCoupon)
-		r.Get("/gift-coupons", h.ListGiftCoupons)
+		read := middleware.RequireAdminPermission(models.AdminPermissionRead)
+		manageCoupons := middleware.RequireAdminPermission(models.AdminPermissionManageCoupons)
+		voidRedemptions := middleware.RequireAdminPermission(models.AdminPermissionVoidRedemptions)
+		manageAnnouncements := middleware.RequireAdminPermission(models.AdminPermissionManageAnnouncements)
+		manageEvent := middleware.RequireAdminPermission(models.AdminPermissionManageEvent)
+		manageAdmins := middleware.RequireAdminPermission(models.AdminPermissionManageAdmins)
+		readAudit := middleware.RequireAdminPermission(models.AdminPermissionReadAudit)
+
+		// Current session
+		r.Get("/session", h.Session)
+		r.Delete("/session", h.Logout)
+
+		// Gift tokens are redeemable, so listing them needs coupons.manage.
+		r.With(manageCoupons).Post("/gift-coupons", h.CreateGiftCoupon)
+		r.With(manageCoupons).Delete("/gift-coupons/{id}", h.DeleteGiftCoupon)
+		r.With(manageCoupons).Get("/gift-coupons", h.ListGiftCoupons)
 		// Legacy alias: kept for backward compatibility.
-		r.Post("/gift-coupons/assignments", h.AssignCouponToUser)
-		r.Post("/discount-coupons/assignments", h.AssignCouponToUser)
-		r.Get("/users", h.SearchUsers)
+		r.With(manageCoupons).Post("/gift-coupons/assignments", h.AssignCouponToUser)
+		r.With(manageCoupons).Post("/discount-coupons/assignments", h.AssignCouponToUser)
+		r.With(read).Get("/users", h.SearchUsers)
+
+		// Leaderboard freeze and top-N coupon settlement
+		r.With(manageCoupons).Post("/leaderboard/freeze", h.FreezeLeaderboard)
+		r.With(manageCoupons).Delete("/leaderboard/freeze", h.UnfreezeLeaderboard)
+		r.With(read).Get("/leaderboard/settlements/preview", h.PreviewLeaderboardSettlement)
+		r.With(read).Get("/leaderboard/settlements", h.ListLeaderboardSettlements)
+		r.With(manageCoupons).Post("/leaderboard/settlements", h.SettleLeaderboard)
+
+		// Coupon rules
+		r.With(read).Get("/coupon-rules", h.ListCouponRules)
+		r.With(manageCoupons).Put("/coupon-rules/{id}", h.PutCouponRule)
+		r.With(manageCoupons).Delete("/coupon-rules/{id}", h.DeleteCouponRule)
+
+		// Redemption voids
+		r.With(voidRedemptions).Post("/coupon-histories/{id}/voids", h.VoidRedemption)
+
+		// Activities; responses include login tokens, so reads need event.manage too.
+		r.With(manageEvent).Get("/activities", h.ListActivities)
+		r.With(manageEvent).Post("/activities", h.CreateActivity)
+		r.With(manageEvent).Put("/activities/{id}", h.UpdateActivity)
+		r.With(manageEvent).Delete("/activities/{id}", h.DeleteActivity)
+		r.With(manageEvent).Post("/activities/{id}/token-rotations", h.RotateActivityToken)
+		r.With(manageEvent).Post("/activities/{id}/qrcode-rotations", h.RotateActivityQRCode)
+		r.With(manageEvent).Get("/activities/{id}/sessions", h.ListActivitySessions)
+		r.With(manageEvent).Delete("/activities/{id}/sessions", h.RevokeActivitySessions)
+		r.With(manageEvent).Delete("/activities/{id}/sessions/{sessionID}", h.RevokeActivitySession)
+
+		// Staffs; responses include login tokens, so reads need event.manage too.
+		r.With(manageEvent).Get("/staffs", h.ListStaffs)
+		r.With(manageEvent).Post("/staffs", h.CreateStaff)
+		r.With(manageEvent).Put("/staffs/{id}", h.UpdateStaff)
+		r.With(manageEvent).Delete("/staffs/{id}", h.DeleteStaff)
+		r.With(manageEvent).Post("/staffs/{id}/token-rotations", h.RotateStaffToken)
+		r.With(manageEvent).Get("/staffs/{id}/sessions", h.ListStaffSessions)
+		r.With(manageEvent).Delete("/staffs/{id}/sessions", h.RevokeStaffSessions)
+		r.With(manageEvent).Delete("/staffs/{id}/sessions/{sessionID}", h.RevokeStaffSession)
+
+		// Announcements
+		r.With(read).Get("/announcements", h.ListAnnouncements)
+		r.With(manageAnnouncements).Post("/announcements", h.CreateAnnouncement)
+		r.With(manageAnnouncements).Put("/announcements/{id}", h.UpdateAnnouncement)
+		r.With(manageAnnouncements).Delete("/announcements/{id}", h.DeleteAnnouncement)
+
+		// Scheduled jobs
+		r.With(read).Get("/jobs", h.ListScheduledJobs)
+		r.With(read).Get("/jobs/{name}/runs", h.ListScheduledJobRuns)
+		r.With(manageEvent).Post("/jobs/{name}/runs", h.TriggerScheduledJob)
+		r.With(manageEvent).Post("/jobs/{name}/retry", h.RetryScheduledJob)
+
+		// Level and sheet-music config versions
+		r.With(manageEvent).Get("/game-config/status", h.GameConfigStatus)
+		r.With(manageEvent).Get("/game-config/versions", h.ListGameConfigVersions)
+		r.With(manageEvent).Post("/game-config/versions", h.ImportGameConfig)
+		r.With(manageEvent).Post("/game-config/versions/{version}/activation", h.ActivateGameConfigVersion)
+
+		// Admin accounts
+		r.With(manageAdmins).Get("/admins", h.ListAdmins)
+		r.With(manageAdmins).Post("/admins", h.CreateAdmin)
+		r.With(manageAdmins).Put("/admins/{id}", h.UpdateAdmin)
+		r.With(manageAdmins).Delete("/admins/{id}", h.DeleteAdmin)
+		r.With(manageAdmins).Post("/admins/{id}/key-rotations", h.RotateAdminKey)
+
+		// Audit log
+		r.With(readAudit).Get("/audit-events", h.ListAuditEvents)
+		r.With(readAudit).Get("/audit-events/export", h.ExportAuditEvents)
 	})
 
 	return r
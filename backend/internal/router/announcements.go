@@ -6,6 +6,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/sitcon-tw/2026-game/internal/handler/announcements"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"go.uber.org/zap"
 )
 
@@ -14,7 +15,7 @@ func AnnouncementRoutes(repo repository.Repository, logger *zap.Logger) http.Han
 	r := chi.NewRouter()
 
 	h := announcements.New(repo, logger)
-	r.Get("/", h.List)
+	r.With(middleware.OptionalAuth(repo, logger)).Get("/", h.List)
 
 	return r
 }
@@ -18,6 +18,7 @@ func DiscountRoutes(repo repository.Repository, logger *zap.Logger, sessionRateL
 
 	r.Route("/staff", func(r chi.Router) {
 		r.Post("/session", h.StaffLogin)
+		r.Delete("/session", h.StaffLogout)
 
 		r.Group(func(r chi.Router) {
 			r.Use(middleware.StaffAuth(repo, logger))
@@ -27,8 +28,12 @@ func DiscountRoutes(repo repository.Repository, logger *zap.Logger, sessionRateL
 			r.Post("/redemptions", h.DiscountUsed)
 			// Staff previews user's available coupons
 			r.Post("/coupon-tokens/query", h.GetUserCoupons)
+			// Staff voids one of their own redemptions, restoring its coupons
+			r.Post("/redemptions/{id}/voids", h.VoidRedemption)
 			// Staff sees their own redemption history
 			r.Get("/current/redemptions", h.ListStaffHistory)
+			// Staff sees their redemption totals with voids netted out
+			r.Get("/current/redemption-totals", h.StaffRedemptionTotals)
 			// Staff scans attendee's one-time QR code to issue a coupon
 			r.Post("/scan-assignments", h.AssignCouponByQRCode)
 			// Staff sees their own scan-assignment history
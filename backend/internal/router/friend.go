@@ -6,18 +6,24 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/sitcon-tw/2026-game/internal/handler/friend"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"go.uber.org/zap"
 )
 
 // FriendRoutes wires friend-related endpoints.
-func FriendRoutes(repo repository.Repository, logger *zap.Logger, sessionRateLimit func(http.Handler) http.Handler) http.Handler {
+func FriendRoutes(
+	repo repository.Repository,
+	logger *zap.Logger,
+	bus *events.Bus,
+	sessionRateLimit func(http.Handler) http.Handler,
+) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Auth(repo, logger))
 	r.Use(sessionRateLimit)
 
-	h := friend.New(repo, logger)
+	h := friend.New(repo, logger, bus)
 
 	// Friend count for the current user
 	r.Get("/stats", h.Count)
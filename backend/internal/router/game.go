@@ -6,24 +6,38 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/sitcon-tw/2026-game/internal/handler/game"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/internal/service/leaderboard"
 	"github.com/sitcon-tw/2026-game/pkg/middleware"
 	"go.uber.org/zap"
 )
 
 // GameRoutes wires game-related endpoints.
-func GameRoutes(repo repository.Repository, logger *zap.Logger, sessionRateLimit func(http.Handler) http.Handler) http.Handler {
+func GameRoutes(
+	repo repository.Repository,
+	logger *zap.Logger,
+	board *leaderboard.Service,
+	bus *events.Bus,
+	sessionRateLimit func(http.Handler) http.Handler,
+) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Auth(repo, logger))
 	r.Use(sessionRateLimit)
 
-	h := game.New(repo, logger)
+	h := game.New(repo, logger, board, bus)
 
+	// Open a single-use play session before playing the next level
+	r.Post("/sessions", h.StartSession)
 	// Submit game to next level
 	r.Post("/submissions", h.Submit)
 	// Get the users rank in the game
 	r.Get("/leaderboards", h.Rank)
+	// Push leaderboard and personal rank changes over SSE
+	r.Get("/leaderboards/stream", h.StreamRank)
 	r.Get("/levels/{level}", h.GetLevelInfo)
+	// Personal best and recent runs for a level
+	r.Get("/levels/{level}/runs", h.ListLevelRuns)
 
 	return r
 }
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sitcon-tw/2026-game/internal/handler/health"
+	"github.com/sitcon-tw/2026-game/internal/service/gameconfig"
+	"go.uber.org/zap"
+)
+
+// HealthRoutes wires the unauthenticated health check.
+func HealthRoutes(logger *zap.Logger, gameConfig *gameconfig.Service) http.Handler {
+	r := chi.NewRouter()
+
+	h := health.New(logger, gameConfig)
+	r.Get("/", h.Get)
+
+	return r
+}
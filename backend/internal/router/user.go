@@ -24,6 +24,11 @@ func UserRoutes(repo repository.Repository, logger *zap.Logger, sessionRateLimit
 	r.With(middleware.Auth(repo, logger), sessionRateLimit).Patch("/me/namecard", h.UpdateNamecard)
 	// Get short-lived one-time token for friend QR scan
 	r.With(middleware.Auth(repo, logger), sessionRateLimit).Get("/me/one-time-qr", h.OneTimeQR)
+	// Get short-lived token for coupon redemption QR
+	r.With(middleware.Auth(repo, logger), sessionRateLimit).Get("/me/coupon-qr", h.CouponQR)
+	// Notification inbox
+	r.With(middleware.Auth(repo, logger), sessionRateLimit).Get("/me/notifications", h.Notifications)
+	r.With(middleware.Auth(repo, logger), sessionRateLimit).Post("/me/notifications/read", h.MarkNotificationsRead)
 
 	return r
 }
@@ -0,0 +1,112 @@
+// Package adminaccounts issues admin login keys and sessions and creates the bootstrap superadmin.
+package adminaccounts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
+	"go.uber.org/zap"
+)
+
+const (
+	loginKeyLength     = 40
+	sessionTokenLength = 48
+)
+
+// ErrInvalidKey is returned by Login for an unknown login key or a disabled account.
+var ErrInvalidKey = errors.New("invalid admin login key")
+
+// NewLoginKey returns a fresh login key and the hash to store for it. Show the key to the admin once.
+func NewLoginKey() (string, string, error) {
+	key, err := helpers.RandomAlphabetToken(loginKeyLength)
+	if err != nil {
+		return "", "", err
+	}
+	return key, helpers.HashSecret(key), nil
+}
+
+// Login checks a login key and opens a session that expires after ADMIN_SESSION_TTL.
+// It returns the admin, the session token for the admin_token cookie, and the session expiry.
+func Login(
+	ctx context.Context,
+	repo repository.Repository,
+	loginKey string,
+) (*models.Admin, string, time.Time, error) {
+	tx, err := repo.StartTransaction(ctx)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	defer repo.DeferRollback(ctx, tx)
+
+	admin, err := repo.GetAdminByKeyHash(ctx, tx, helpers.HashSecret(loginKey))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, "", time.Time{}, ErrInvalidKey
+		}
+		return nil, "", time.Time{}, err
+	}
+
+	token, err := helpers.RandomAlphabetToken(sessionTokenLength)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	expiresAt := time.Now().UTC().Add(config.Env().AdminSessionTTL)
+	if err = repo.CreateAdminSession(ctx, tx, helpers.HashSecret(token), admin.ID, expiresAt); err != nil {
+		return nil, "", time.Time{}, err
+	}
+
+	if err = repo.CommitTransaction(ctx, tx); err != nil {
+		return nil, "", time.Time{}, err
+	}
+	return admin, token, expiresAt, nil
+}
+
+// Bootstrap creates the ADMIN_BOOTSTRAP_USERNAME superadmin with ADMIN_BOOTSTRAP_KEY as its login key
+// when no admin account exists yet. It does nothing once any account exists or when the key is unset.
+func Bootstrap(ctx context.Context, repo repository.Repository, logger *zap.Logger) error {
+	cfg := config.Env()
+	if cfg.AdminKey != "" {
+		logger.Warn("ADMIN_KEY is deprecated and only seeds the bootstrap superadmin; set ADMIN_BOOTSTRAP_KEY and log in with a personal account")
+	}
+	if cfg.AdminBootstrapKey == "" {
+		return nil
+	}
+
+	tx, err := repo.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer repo.DeferRollback(ctx, tx)
+
+	count, err := repo.CountAdmins(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	admin := &models.Admin{
+		Username: cfg.AdminBootstrapUsername,
+		Role:     models.AdminRoleSuperadmin,
+		KeyHash:  helpers.HashSecret(cfg.AdminBootstrapKey),
+	}
+	if err = repo.CreateAdmin(ctx, tx, admin); err != nil {
+		// Another instance bootstrapped at the same time.
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil
+		}
+		return err
+	}
+	if err = repo.CommitTransaction(ctx, tx); err != nil {
+		return err
+	}
+
+	logger.Info("Created bootstrap superadmin", zap.String("username", admin.Username))
+	return nil
+}
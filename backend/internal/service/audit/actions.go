@@ -0,0 +1,55 @@
+package audit
+
+// Target types.
+const (
+	TargetGiftCoupon    = "gift_coupon"
+	TargetCoupon        = "discount_coupon"
+	TargetCouponRule    = "coupon_rule"
+	TargetCouponHistory = "coupon_history"
+	TargetLeaderboard   = "leaderboard"
+	TargetActivity      = "activity"
+	TargetStaff         = "staff"
+	TargetAnnouncement  = "announcement"
+	TargetScheduledJob  = "scheduled_job"
+	TargetAdmin         = "admin"
+	TargetUser          = "user"
+	TargetGameConfig    = "game_config"
+)
+
+// Actions, named <target>.<verb>.
+const (
+	ActionGiftCouponCreate       = "gift_coupon.create"
+	ActionGiftCouponDelete       = "gift_coupon.delete"
+	ActionCouponAssign           = "discount_coupon.assign"
+	ActionCouponScanAssign       = "discount_coupon.scan_assign"
+	ActionCouponRedeem           = "discount_coupon.redeem"
+	ActionCouponRulePut          = "coupon_rule.put"
+	ActionCouponRuleDelete       = "coupon_rule.delete"
+	ActionRedemptionVoid         = "coupon_history.void"
+	ActionLeaderboardFreeze      = "leaderboard.freeze"
+	ActionLeaderboardUnfreeze    = "leaderboard.unfreeze"
+	ActionLeaderboardSettle      = "leaderboard.settle"
+	ActionActivityCreate         = "activity.create"
+	ActionActivityUpdate         = "activity.update"
+	ActionActivityDelete         = "activity.delete"
+	ActionActivityTokenRotate    = "activity.token_rotate"
+	ActionActivityQRCodeRotate   = "activity.qrcode_rotate"
+	ActionActivitySessionsRevoke = "activity.sessions_revoke"
+	ActionStaffCreate            = "staff.create"
+	ActionStaffUpdate            = "staff.update"
+	ActionStaffDelete            = "staff.delete"
+	ActionStaffTokenRotate       = "staff.token_rotate"
+	ActionStaffSessionsRevoke    = "staff.sessions_revoke"
+	ActionAnnouncementCreate     = "announcement.create"
+	ActionAnnouncementUpdate     = "announcement.update"
+	ActionAnnouncementDelete     = "announcement.delete"
+	ActionScheduledJobTrigger    = "scheduled_job.trigger"
+	ActionScheduledJobRetry      = "scheduled_job.retry"
+	ActionAdminCreate            = "admin.create"
+	ActionAdminUpdate            = "admin.update"
+	ActionAdminDelete            = "admin.delete"
+	ActionAdminKeyRotate         = "admin.key_rotate"
+	ActionBoothCheckIn           = "user.booth_check_in"
+	ActionGameConfigImport       = "game_config.import"
+	ActionGameConfigActivate     = "game_config.activate"
+)
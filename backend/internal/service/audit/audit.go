@@ -0,0 +1,140 @@
+// Package audit appends admin, staff and booth actions to the audit_events table.
+// The actor, request ID and trace ID are taken from the request context.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrNoActor is returned when the context carries no admin, staff or booth session.
+var ErrNoActor = errors.New("audit: no authenticated actor in context")
+
+// redactedKeys are top-level JSON fields never written to the audit log.
+//
+//nolint:gochecknoglobals // static lookup table
+var redactedKeys = map[string]struct{}{
+	"token":        {},
+	"qrcode_token": {},
+	"login_key":    {},
+	"key_hash":     {},
+}
+
+// Entry describes one change. Before and After are marshalled to JSON; leave them nil when not
+// applicable (nothing existed before a create, nothing remains after a delete).
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     any
+	After      any
+}
+
+// Record appends e inside tx, so the audit event is only kept if the change itself is committed.
+func Record(ctx context.Context, repo repository.Repository, tx pgx.Tx, e Entry) error {
+	event, err := newEvent(ctx, e)
+	if err != nil {
+		return err
+	}
+	return repo.InsertAuditEvent(ctx, tx, event)
+}
+
+// RecordSeparately appends e in its own transaction. Use it for actions whose change is committed
+// by a service that owns its transaction (leaderboard settlement, scheduler triggers).
+func RecordSeparately(ctx context.Context, repo repository.Repository, e Entry) error {
+	tx, err := repo.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer repo.DeferRollback(ctx, tx)
+
+	if err = Record(ctx, repo, tx, e); err != nil {
+		return err
+	}
+	return repo.CommitTransaction(ctx, tx)
+}
+
+func newEvent(ctx context.Context, e Entry) (*models.AuditEvent, error) {
+	event := &models.AuditEvent{
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		RequestID:  chimiddleware.GetReqID(ctx),
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		event.TraceID = spanCtx.TraceID().String()
+	}
+
+	switch {
+	case actorAdmin(ctx, event):
+	case actorStaff(ctx, event):
+	case actorBooth(ctx, event):
+	default:
+		return nil, ErrNoActor
+	}
+
+	var err error
+	if event.Before, err = snapshot(e.Before); err != nil {
+		return nil, err
+	}
+	if event.After, err = snapshot(e.After); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func actorAdmin(ctx context.Context, event *models.AuditEvent) bool {
+	admin, ok := middleware.AdminFromContext(ctx)
+	if !ok || admin == nil {
+		return false
+	}
+	event.ActorType, event.ActorID, event.ActorName = models.AuditActorAdmin, admin.ID, admin.Username
+	return true
+}
+
+func actorStaff(ctx context.Context, event *models.AuditEvent) bool {
+	staff, ok := middleware.StaffFromContext(ctx)
+	if !ok || staff == nil {
+		return false
+	}
+	event.ActorType, event.ActorID, event.ActorName = models.AuditActorStaff, staff.ID, staff.Name
+	return true
+}
+
+func actorBooth(ctx context.Context, event *models.AuditEvent) bool {
+	booth, ok := middleware.BoothFromContext(ctx)
+	if !ok || booth == nil {
+		return false
+	}
+	event.ActorType, event.ActorID, event.ActorName = models.AuditActorBooth, booth.ID, booth.Name
+	return true
+}
+
+// snapshot marshals v and drops redactedKeys from the top-level object.
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(raw, &fields) != nil {
+		// Not an object; nothing to redact.
+		return raw, nil
+	}
+	for key := range redactedKeys {
+		delete(fields, key)
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,48 @@
+package audit //nolint:testpackage // tests exercise unexported redaction
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSnapshotRedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	raw, err := snapshot(map[string]any{
+		"id":           "s1",
+		"name":         "Alice",
+		"token":        "secret",
+		"qrcode_token": "qr-secret",
+	})
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	var got map[string]any
+	if err = json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := got["token"]; ok {
+		t.Fatal("token must be redacted")
+	}
+	if _, ok := got["qrcode_token"]; ok {
+		t.Fatal("qrcode_token must be redacted")
+	}
+	if got["name"] != "Alice" || got["id"] != "s1" {
+		t.Fatalf("unexpected snapshot %s", raw)
+	}
+}
+
+func TestSnapshotNilAndNonObject(t *testing.T) {
+	t.Parallel()
+
+	raw, err := snapshot(nil)
+	if err != nil || raw != nil {
+		t.Fatalf("snapshot(nil) = %s, %v", raw, err)
+	}
+
+	raw, err = snapshot([]int{1, 2})
+	if err != nil || string(raw) != "[1,2]" {
+		t.Fatalf("snapshot(array) = %s, %v", raw, err)
+	}
+}
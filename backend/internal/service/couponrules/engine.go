@@ -0,0 +1,186 @@
+// Package couponrules evaluates the coupon_rules table against a user's progress and issues coupons.
+package couponrules
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/notifications"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+)
+
+var (
+	// ErrOutOfStock is returned when a rule's stock limit has been reached.
+	ErrOutOfStock = errors.New("coupon rule is out of stock")
+	// ErrRuleNotFound is returned when no enabled rule matches the lookup.
+	ErrRuleNotFound = errors.New("coupon rule not found")
+)
+
+// Engine issues coupons from data-driven rules. It holds no state of its own.
+type Engine struct {
+	Repo repository.Repository
+}
+
+func New(repo repository.Repository) *Engine {
+	return &Engine{Repo: repo}
+}
+
+// Automatic reports whether rules with this trigger are issued by Evaluate.
+// Leaderboard rules are issued by settlement and manual rules by staff.
+func Automatic(trigger models.CouponRuleTrigger) bool {
+	switch trigger {
+	case models.CouponTriggerLevel,
+		models.CouponTriggerActivityVisits,
+		models.CouponTriggerActivity,
+		models.CouponTriggerFriends,
+		models.CouponTriggerGroupCheckIns:
+		return true
+	case models.CouponTriggerLeaderboardRank, models.CouponTriggerManual:
+		return false
+	}
+	return false
+}
+
+// Evaluate issues every automatic coupon the user now qualifies for and returns the newly issued ones.
+// Call it inside the transaction that changed the user's progress, after the change is written.
+func (e *Engine) Evaluate(ctx context.Context, tx pgx.Tx, userID string) ([]models.DiscountCoupon, error) {
+	now := time.Now().UTC()
+	if config.IsCouponEarningStopped(now) {
+		return nil, nil
+	}
+
+	rules, err := e.Repo.ListCouponRules(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	facts := &userFacts{repo: e.Repo, userID: userID}
+	var issued []models.DiscountCoupon
+	for _, rule := range rules {
+		if !Automatic(rule.Trigger) || !rule.ActiveAt(now) {
+			continue
+		}
+
+		ok, satisfiesErr := facts.satisfies(ctx, tx, rule)
+		if satisfiesErr != nil {
+			return nil, satisfiesErr
+		}
+		if !ok {
+			continue
+		}
+
+		coupon, created, issueErr := e.Issue(ctx, tx, userID, rule)
+		if errors.Is(issueErr, ErrOutOfStock) {
+			continue
+		}
+		if issueErr != nil {
+			return nil, issueErr
+		}
+		if created {
+			issued = append(issued, *coupon)
+		}
+	}
+	return issued, nil
+}
+
+// Issue gives the user one coupon under rule and notifies them. It returns created=false if the user
+// already holds one, and ErrOutOfStock if the rule's stock limit or budget has been used up.
+func (e *Engine) Issue(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID string,
+	rule models.CouponRule,
+) (*models.DiscountCoupon, bool, error) {
+	holders, err := e.Repo.ListUserIDsWithDiscount(ctx, tx, rule.ID, []string{userID})
+	if err != nil {
+		return nil, false, err
+	}
+	if holders[userID] {
+		return nil, false, nil
+	}
+
+	if err = e.Reserve(ctx, tx, rule.ID, rule.Amount); err != nil {
+		return nil, false, err
+	}
+	coupon, created, err := e.Repo.CreateDiscountCoupon(ctx, tx, userID, rule.Amount, rule.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		// Lost a race with a concurrent issue for the same user; give the reservation back.
+		if err = e.Release(ctx, tx, rule.ID, rule.Amount); err != nil {
+			return nil, false, err
+		}
+		return coupon, false, nil
+	}
+	if err = notifications.CouponIssued(ctx, e.Repo, tx, coupon, sourceOf(rule.Trigger)); err != nil {
+		return nil, false, err
+	}
+	return coupon, true, nil
+}
+
+// sourceOf maps a rule trigger to the coupon source shown in the user's notification.
+func sourceOf(trigger models.CouponRuleTrigger) models.CouponSource {
+	if trigger == models.CouponTriggerLeaderboardRank {
+		return models.CouponSourceSettlement
+	}
+	return models.CouponSourceRule
+}
+
+// Reserve takes one coupon worth amount from the rule for discountID. It returns ErrOutOfStock once the
+// rule's stock or budget is used up. discount_ids without a rule (ad-hoc gifts) are not capped.
+func (e *Engine) Reserve(ctx context.Context, tx pgx.Tx, discountID string, amount int) error {
+	reserved, err := e.Repo.ReserveCouponStock(ctx, tx, discountID, amount)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return ErrOutOfStock
+	}
+	return nil
+}
+
+// Release returns a reservation, e.g. when an unredeemed gift is deleted.
+func (e *Engine) Release(ctx context.Context, tx pgx.Tx, discountID string, amount int) error {
+	return e.Repo.ReleaseCouponStock(ctx, tx, discountID, amount)
+}
+
+// RuleByTrigger returns the first enabled rule with trigger in display order.
+func (e *Engine) RuleByTrigger(
+	ctx context.Context,
+	tx pgx.Tx,
+	trigger models.CouponRuleTrigger,
+) (*models.CouponRule, error) {
+	rules, err := e.Repo.ListCouponRules(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Trigger == trigger && rule.Enabled {
+			return &rule, nil
+		}
+	}
+	return nil, ErrRuleNotFound
+}
+
+// Rule returns the enabled rule with id.
+func (e *Engine) Rule(ctx context.Context, tx pgx.Tx, id string) (*models.CouponRule, error) {
+	rule, err := e.Repo.GetCouponRule(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrRuleNotFound
+		}
+		return nil, err
+	}
+	if !rule.Enabled {
+		return nil, ErrRuleNotFound
+	}
+	return rule, nil
+}
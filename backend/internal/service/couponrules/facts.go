@@ -0,0 +1,122 @@
+package couponrules
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+// userFacts loads the progress counters a rule needs on first use, so one evaluation
+// costs at most one query per kind of trigger no matter how many rules there are.
+type userFacts struct {
+	repo   repository.Repository
+	userID string
+
+	level         *int
+	friends       *int
+	groupCheckIns *int
+	visited       []string
+	visitedLoaded bool
+	visitsByTypes map[string]int
+}
+
+func (f *userFacts) satisfies(ctx context.Context, tx pgx.Tx, rule models.CouponRule) (bool, error) {
+	switch rule.Trigger {
+	case models.CouponTriggerLevel:
+		level, err := f.currentLevel(ctx, tx)
+		return level >= rule.Threshold, err
+	case models.CouponTriggerActivityVisits:
+		count, err := f.visitCount(ctx, tx, rule.ActivityTypes)
+		return count >= rule.Threshold, err
+	case models.CouponTriggerActivity:
+		if rule.ActivityID == nil {
+			return false, nil
+		}
+		visited, err := f.visitedIDs(ctx, tx)
+		return slices.Contains(visited, *rule.ActivityID), err
+	case models.CouponTriggerFriends:
+		count, err := f.friendCount(ctx, tx)
+		return count >= rule.Threshold, err
+	case models.CouponTriggerGroupCheckIns:
+		count, err := f.groupCheckInCount(ctx, tx)
+		return count >= rule.Threshold, err
+	case models.CouponTriggerLeaderboardRank, models.CouponTriggerManual:
+		return false, nil
+	}
+	return false, nil
+}
+
+func (f *userFacts) currentLevel(ctx context.Context, tx pgx.Tx) (int, error) {
+	if f.level == nil {
+		user, err := f.repo.GetUserByID(ctx, tx, f.userID)
+		if err != nil {
+			return 0, err
+		}
+		f.level = &user.CurrentLevel
+	}
+	return *f.level, nil
+}
+
+func (f *userFacts) visitCount(ctx context.Context, tx pgx.Tx, types []models.ActivitiesTypes) (int, error) {
+	key := typesKey(types)
+	if count, ok := f.visitsByTypes[key]; ok {
+		return count, nil
+	}
+	count, err := f.repo.CountVisitedActivitiesByTypes(ctx, tx, f.userID, types)
+	if err != nil {
+		return 0, err
+	}
+	if f.visitsByTypes == nil {
+		f.visitsByTypes = make(map[string]int)
+	}
+	f.visitsByTypes[key] = count
+	return count, nil
+}
+
+func (f *userFacts) visitedIDs(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	if !f.visitedLoaded {
+		ids, err := f.repo.ListVisitedActivityIDs(ctx, tx, f.userID)
+		if err != nil {
+			return nil, err
+		}
+		f.visited = ids
+		f.visitedLoaded = true
+	}
+	return f.visited, nil
+}
+
+func (f *userFacts) friendCount(ctx context.Context, tx pgx.Tx) (int, error) {
+	if f.friends == nil {
+		count, err := f.repo.CountFriends(ctx, tx, f.userID)
+		if err != nil {
+			return 0, err
+		}
+		f.friends = &count
+	}
+	return *f.friends, nil
+}
+
+func (f *userFacts) groupCheckInCount(ctx context.Context, tx pgx.Tx) (int, error) {
+	if f.groupCheckIns == nil {
+		count, err := f.repo.CountGroupCheckIns(ctx, tx, f.userID)
+		if err != nil {
+			return 0, err
+		}
+		f.groupCheckIns = &count
+	}
+	return *f.groupCheckIns, nil
+}
+
+func typesKey(types []models.ActivitiesTypes) string {
+	keys := make([]string, len(types))
+	for i, t := range types {
+		keys[i] = string(t)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,129 @@
+//nolint:testpackage // exercises the unexported userFacts that Evaluate uses for every rule
+package couponrules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+// progressRepo answers the progress queries of one user; any other call panics on the nil embed.
+type progressRepo struct {
+	repository.Repository
+
+	level         int
+	friends       int
+	groupCheckIns int
+	visited       []string
+	visitsByTypes map[string]int
+}
+
+func (r *progressRepo) GetUserByID(_ context.Context, _ pgx.Tx, id string) (*models.User, error) {
+	return &models.User{ID: id, CurrentLevel: r.level}, nil
+}
+
+func (r *progressRepo) CountVisitedActivitiesByTypes(
+	_ context.Context,
+	_ pgx.Tx,
+	_ string,
+	types []models.ActivitiesTypes,
+) (int, error) {
+	return r.visitsByTypes[typesKey(types)], nil
+}
+
+func (r *progressRepo) ListVisitedActivityIDs(_ context.Context, _ pgx.Tx, _ string) ([]string, error) {
+	return r.visited, nil
+}
+
+func (r *progressRepo) CountFriends(_ context.Context, _ pgx.Tx, _ string) (int, error) {
+	return r.friends, nil
+}
+
+func (r *progressRepo) CountGroupCheckIns(_ context.Context, _ pgx.Tx, _ string) (int, error) {
+	return r.groupCheckIns, nil
+}
+
+func TestSatisfies(t *testing.T) {
+	t.Parallel()
+
+	repo := &progressRepo{
+		level:         50,
+		friends:       3,
+		groupCheckIns: 2,
+		visited:       []string{"booth-1", "tour-group"},
+		visitsByTypes: map[string]int{"booth,check": 23, "": 25},
+	}
+	tourGroup := "tour-group"
+	keynote := "keynote"
+
+	tests := []struct {
+		name string
+		rule models.CouponRule
+		want bool
+	}{
+		{"level reached", models.CouponRule{Trigger: models.CouponTriggerLevel, Threshold: 50}, true},
+		{"level not reached", models.CouponRule{Trigger: models.CouponTriggerLevel, Threshold: 51}, false},
+		{"visits of types reached", models.CouponRule{
+			Trigger:       models.CouponTriggerActivityVisits,
+			Threshold:     23,
+			ActivityTypes: []models.ActivitiesTypes{models.ActivitiesTypeCheck, models.ActivitiesTypeBooth},
+		}, true},
+		{"visits of types not reached", models.CouponRule{
+			Trigger:       models.CouponTriggerActivityVisits,
+			Threshold:     24,
+			ActivityTypes: []models.ActivitiesTypes{models.ActivitiesTypeBooth, models.ActivitiesTypeCheck},
+		}, false},
+		{"visits of any type", models.CouponRule{Trigger: models.CouponTriggerActivityVisits, Threshold: 25}, true},
+		{"activity visited", models.CouponRule{Trigger: models.CouponTriggerActivity, ActivityID: &tourGroup}, true},
+		{"activity not visited", models.CouponRule{Trigger: models.CouponTriggerActivity, ActivityID: &keynote}, false},
+		{"activity not bound", models.CouponRule{Trigger: models.CouponTriggerActivity}, false},
+		{"friends reached", models.CouponRule{Trigger: models.CouponTriggerFriends, Threshold: 3}, true},
+		{"friends not reached", models.CouponRule{Trigger: models.CouponTriggerFriends, Threshold: 4}, false},
+		{"group check-ins reached", models.CouponRule{Trigger: models.CouponTriggerGroupCheckIns, Threshold: 2}, true},
+		{"group check-ins not reached", models.CouponRule{Trigger: models.CouponTriggerGroupCheckIns, Threshold: 3}, false},
+		{"leaderboard rank", models.CouponRule{Trigger: models.CouponTriggerLeaderboardRank, Threshold: 10}, false},
+		{"manual", models.CouponRule{Trigger: models.CouponTriggerManual}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			facts := &userFacts{repo: repo, userID: "user-1"}
+			got, err := facts.satisfies(context.Background(), nil, tt.rule)
+			if err != nil {
+				t.Fatalf("satisfies: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutomatic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		trigger models.CouponRuleTrigger
+		want    bool
+	}{
+		{models.CouponTriggerLevel, true},
+		{models.CouponTriggerActivityVisits, true},
+		{models.CouponTriggerActivity, true},
+		{models.CouponTriggerFriends, true},
+		{models.CouponTriggerGroupCheckIns, true},
+		{models.CouponTriggerLeaderboardRank, false},
+		{models.CouponTriggerManual, false},
+		{"unknown", false},
+	}
+
+	for _, tt := range tests {
+		if got := Automatic(tt.trigger); got != tt.want {
+			t.Errorf("Automatic(%q) = %v, want %v", tt.trigger, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package couponsettlement
+
+import (
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+// TiePolicy decides what happens to a group of users sharing a rank that straddles the rank limit.
+type TiePolicy string
+
+// TiePolicy values.
+const (
+	// TiePolicyInclude settles everyone whose RANK() is within the limit, so ties can push the count past it.
+	TiePolicyInclude TiePolicy = "include"
+	// TiePolicyExclude only settles tie groups that fit entirely within the limit.
+	TiePolicyExclude TiePolicy = "exclude"
+)
+
+// Candidate reasons.
+const (
+	ReasonWithinLimit = "within_limit"
+	ReasonTieIncluded = "tie_included"
+	ReasonTieExcluded = "tie_excluded"
+)
+
+// Candidate is one user considered for settlement.
+type Candidate struct {
+	UserID        string `json:"user_id"`
+	Nickname      string `json:"nickname"`
+	Rank          int    `json:"rank"`
+	Score         int    `json:"score"`
+	TieSize       int    `json:"tie_size"`
+	Eligible      bool   `json:"eligible"`
+	Reason        string `json:"reason"`
+	AlreadyIssued bool   `json:"already_issued"`
+}
+
+// Preview is the dry-run result of a settlement.
+type Preview struct {
+	Board         string      `json:"board"`
+	DiscountID    string      `json:"discount_id"`
+	Amount        int         `json:"amount"`
+	RankLimit     int         `json:"rank_limit"`
+	TiePolicy     TiePolicy   `json:"tie_policy"`
+	FrozenAt      *time.Time  `json:"frozen_at"`
+	GeneratedAt   time.Time   `json:"generated_at"`
+	EligibleCount int         `json:"eligible_count"`
+	Candidates    []Candidate `json:"candidates"`
+
+	rule models.CouponRule
+}
+
+// selectCandidates returns every user whose rank is within limit. Members of a tie group that
+// extends past the limit are marked eligible according to policy. rows must be in ranking order.
+func selectCandidates(rows []repository.RankedUser, limit int, policy TiePolicy) []Candidate {
+	tieSize := make(map[int]int)
+	for _, row := range rows {
+		tieSize[row.Rank]++
+	}
+
+	out := []Candidate{}
+	for _, row := range rows {
+		if row.Rank > limit || limit <= 0 {
+			break
+		}
+
+		size := tieSize[row.Rank]
+		groupEnd := row.Rank + size - 1
+		c := Candidate{
+			UserID:   row.User.ID,
+			Nickname: row.User.Nickname,
+			Rank:     row.Rank,
+			Score:    row.Score,
+			TieSize:  size,
+			Eligible: true,
+			Reason:   ReasonWithinLimit,
+		}
+		if groupEnd > limit {
+			if policy == TiePolicyExclude {
+				c.Eligible = false
+				c.Reason = ReasonTieExcluded
+			} else {
+				c.Reason = ReasonTieIncluded
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
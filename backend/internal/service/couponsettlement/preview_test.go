@@ -0,0 +1,67 @@
+package couponsettlement //nolint:testpackage // tests need access to unexported candidate selection
+
+import (
+	"testing"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+func rankedRows(ranks ...int) []repository.RankedUser {
+	rows := make([]repository.RankedUser, len(ranks))
+	for i, rank := range ranks {
+		rows[i] = repository.RankedUser{User: models.User{ID: string(rune('a' + i))}, Rank: rank}
+	}
+	return rows
+}
+
+func TestSelectCandidatesTieAcrossLimit(t *testing.T) {
+	t.Parallel()
+
+	// Ranks 1, 2, then a three-way tie at 3 that straddles a limit of 4.
+	rows := rankedRows(1, 2, 3, 3, 3, 6)
+
+	include := selectCandidates(rows, 4, TiePolicyInclude)
+	if len(include) != 5 {
+		t.Fatalf("include: got %d candidates, want 5", len(include))
+	}
+	for _, c := range include {
+		if !c.Eligible {
+			t.Fatalf("include: user %s at rank %d should be eligible", c.UserID, c.Rank)
+		}
+	}
+	if include[2].Reason != ReasonTieIncluded || include[2].TieSize != 3 {
+		t.Fatalf("include: got reason %q tie size %d, want %q and 3", include[2].Reason, include[2].TieSize, ReasonTieIncluded)
+	}
+
+	exclude := selectCandidates(rows, 4, TiePolicyExclude)
+	eligible := 0
+	for _, c := range exclude {
+		if c.Eligible {
+			eligible++
+			continue
+		}
+		if c.Reason != ReasonTieExcluded {
+			t.Fatalf("exclude: got reason %q, want %q", c.Reason, ReasonTieExcluded)
+		}
+	}
+	if len(exclude) != 5 || eligible != 2 {
+		t.Fatalf("exclude: got %d candidates with %d eligible, want 5 with 2", len(exclude), eligible)
+	}
+}
+
+func TestSelectCandidatesTieWithinLimit(t *testing.T) {
+	t.Parallel()
+
+	rows := rankedRows(1, 1, 3, 4)
+
+	got := selectCandidates(rows, 3, TiePolicyExclude)
+	if len(got) != 3 {
+		t.Fatalf("got %d candidates, want 3", len(got))
+	}
+	for _, c := range got {
+		if !c.Eligible || c.Reason != ReasonWithinLimit {
+			t.Fatalf("user %s at rank %d: eligible=%v reason=%q", c.UserID, c.Rank, c.Eligible, c.Reason)
+		}
+	}
+}
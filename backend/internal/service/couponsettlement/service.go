@@ -2,122 +2,320 @@ package couponsettlement
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/couponrules"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/internal/service/scheduler"
 	"github.com/sitcon-tw/2026-game/pkg/config"
 	"go.uber.org/zap"
 )
 
 const (
 	leaderboardSettlementLockKey int64 = 202603281600
-	leaderboardFetchLimit              = 30
-	leaderboardTopRankLimit            = 10
 	settlementTimeout                  = time.Minute
 )
 
+// Settlement triggers.
+const (
+	TriggerScheduler = "scheduler"
+	TriggerAdmin     = "admin"
+)
+
+var (
+	// ErrScopedBoard is returned for boards whose standings depend on the viewer.
+	ErrScopedBoard = errors.New("leaderboard board depends on the viewer and cannot be settled")
+	// ErrSettlementInProgress is returned when another settlement holds the advisory lock.
+	ErrSettlementInProgress = errors.New("leaderboard settlement already in progress")
+	// ErrRuleMissing is returned when no enabled leaderboard_rank coupon rule exists.
+	ErrRuleMissing = errors.New("leaderboard coupon rule is missing")
+)
+
 type Service struct {
-	Repo   repository.Repository
-	Logger *zap.Logger
+	Repo    repository.Repository
+	Logger  *zap.Logger
+	Events  *events.Bus
+	Coupons *couponrules.Engine
 }
 
-func New(repo repository.Repository, logger *zap.Logger) *Service {
-	return &Service{Repo: repo, Logger: logger}
+func New(repo repository.Repository, logger *zap.Logger, bus *events.Bus) *Service {
+	return &Service{Repo: repo, Logger: logger, Events: bus, Coupons: couponrules.New(repo)}
 }
 
-func (s *Service) StartScheduler(ctx context.Context) {
+// Board returns the board configured for settlement.
+func Board() repository.Board {
+	return repository.Board(config.Env().LeaderboardSettlementBoard)
+}
+
+// SettlementJob is the scheduled job that freezes and settles the leaderboard at COUPON_STOP_TIME.
+const SettlementJob = "leaderboard_settlement"
+
+// RegisterJobs registers the one-shot settlement job at COUPON_STOP_TIME.
+// A run missed while the server was down is caught up by the scheduler on start.
+func (s *Service) RegisterJobs(sched *scheduler.Scheduler) {
 	stopAt, ok := config.Env().CouponStopAt()
 	if !ok {
-		s.Logger.Info("Coupon stop scheduler disabled; COUPON_STOP_TIME is not set")
+		s.Logger.Info("Leaderboard settlement job disabled; COUPON_STOP_TIME is not set")
+		return
+	}
+
+	board := Board()
+	if err := ValidateBoard(board); err != nil {
+		s.Logger.Error(
+			"Leaderboard settlement job disabled; invalid LEADERBOARD_SETTLEMENT_BOARD",
+			zap.String("board", string(board)),
+			zap.Error(err),
+		)
 		return
 	}
 
-	go s.run(ctx, stopAt)
+	sched.Register(scheduler.Job{
+		Name:    SettlementJob,
+		RunAt:   stopAt,
+		Timeout: settlementTimeout,
+		Run: func(ctx context.Context) error {
+			return s.settleAt(ctx, board, stopAt)
+		},
+	})
 }
 
-func (s *Service) run(ctx context.Context, stopAt time.Time) {
-	wait := time.Until(stopAt)
-	if wait <= 0 {
-		s.Logger.Info("Skipped coupon settlement scheduler because stop time has already passed")
-		return
+func (s *Service) settleAt(ctx context.Context, board repository.Board, stopAt time.Time) error {
+	// Freeze first so the settled standings are the ones players see from now on.
+	if _, err := s.Freeze(ctx, board, stopAt); err != nil && !errors.Is(err, repository.ErrAlreadyExists) {
+		return fmt.Errorf("freeze leaderboard: %w", err)
+	}
+	if _, err := s.Settle(ctx, board, TriggerScheduler); err != nil {
+		return fmt.Errorf("settle leaderboard coupons: %w", err)
 	}
+	return nil
+}
 
-	timer := time.NewTimer(wait)
-	defer timer.Stop()
+// ValidateBoard checks that the board exists and ranks every user the same way regardless of viewer.
+func ValidateBoard(board repository.Board) error {
+	strategy, ok := repository.LookupRankingStrategy(board)
+	if !ok {
+		return repository.ErrUnknownBoard
+	}
+	if strategy.Scoped() {
+		return ErrScopedBoard
+	}
+	return nil
+}
 
-	select {
-	case <-ctx.Done():
-		return
-	case <-timer.C:
+// Freeze pins the standings of board at frozenAt. Returns repository.ErrAlreadyExists if it is already frozen.
+func (s *Service) Freeze(ctx context.Context, board repository.Board, frozenAt time.Time) (*models.LeaderboardFreeze, error) {
+	if err := ValidateBoard(board); err != nil {
+		return nil, err
 	}
 
-	settlementCtx, cancel := context.WithTimeout(ctx, settlementTimeout)
-	defer cancel()
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
 
-	if err := s.SettleLeaderboardTopTen(settlementCtx); err != nil {
-		s.Logger.Error("Failed to settle leaderboard top ten coupons", zap.Error(err))
+	freeze, err := s.Repo.CreateFreeze(ctx, tx, board, frozenAt.UTC())
+	if err != nil {
+		return nil, err
 	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	s.Logger.Info("Leaderboard frozen", zap.String("board", string(board)), zap.Time("frozen_at", freeze.FrozenAt))
+	s.Events.Publish(ctx, events.Event{Kind: events.KindLeaderboardFrozen})
+	return freeze, nil
 }
 
-func (s *Service) SettleLeaderboardTopTen(ctx context.Context) error {
-	rule, ok := config.GetLeaderboardTopTenCouponRule()
-	if !ok {
-		s.Logger.Warn("Skipped leaderboard settlement because coupon rule is missing")
-		return nil
+// Unfreeze returns board to live standings. Returns repository.ErrNotFound if it was not frozen.
+func (s *Service) Unfreeze(ctx context.Context, board repository.Board) error {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	if err = s.Repo.DeleteFreeze(ctx, tx, board); err != nil {
+		return err
+	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return err
+	}
+
+	s.Logger.Info("Leaderboard unfrozen", zap.String("board", string(board)))
+	s.Events.Publish(ctx, events.Event{Kind: events.KindLeaderboardFrozen})
+	return nil
+}
+
+// Preview computes who would be settled right now without issuing anything.
+func (s *Service) Preview(ctx context.Context, board repository.Board) (*Preview, error) {
+	if err := ValidateBoard(board); err != nil {
+		return nil, err
 	}
 
 	tx, err := s.Repo.StartTransaction(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	preview, err := s.buildPreview(ctx, tx, board)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// Settle issues the leaderboard coupon to every eligible user and records the run.
+// Re-running is safe: users who already hold the coupon are recorded as already_issued.
+func (s *Service) Settle(ctx context.Context, board repository.Board, trigger string) (*models.LeaderboardSettlement, error) {
+	if err := ValidateBoard(board); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
 	}
 	defer s.Repo.DeferRollback(ctx, tx)
 
 	locked, err := s.Repo.TryAcquireAdvisoryXactLock(ctx, tx, leaderboardSettlementLockKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !locked {
 		s.Logger.Info("Skipped leaderboard settlement because another instance holds the lock")
-		return nil
+		return nil, ErrSettlementInProgress
 	}
 
-	rows, err := s.Repo.GetTopUsers(ctx, tx, leaderboardFetchLimit, 0)
+	preview, err := s.buildPreview(ctx, tx, board)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	issuedCount := 0
-	eligibleCount := 0
-	for _, row := range rows {
-		if row.Rank > leaderboardTopRankLimit {
-			continue
-		}
+	settlement := &models.LeaderboardSettlement{
+		ID:         uuid.NewString(),
+		Board:      string(board),
+		DiscountID: preview.DiscountID,
+		Amount:     preview.Amount,
+		RankLimit:  preview.RankLimit,
+		TiePolicy:  string(preview.TiePolicy),
+		FrozenAt:   preview.FrozenAt,
+		Trigger:    trigger,
+		SettledAt:  time.Now().UTC(),
+		Entries:    make([]models.LeaderboardSettlementEntry, 0, len(preview.Candidates)),
+	}
 
-		eligibleCount++
-		_, created, createErr := s.Repo.CreateDiscountCoupon(
-			ctx,
-			tx,
-			row.User.ID,
-			rule.Amount,
-			config.DiscountIDLeaderboardTopTen,
-		)
-		if createErr != nil {
-			return createErr
+	for _, c := range preview.Candidates {
+		entry := models.LeaderboardSettlementEntry{
+			UserID:  c.UserID,
+			Rank:    c.Rank,
+			Score:   c.Score,
+			TieSize: c.TieSize,
+			Outcome: models.SettlementOutcomeExcludedTie,
 		}
-		if created {
-			issuedCount++
+		if c.Eligible {
+			settlement.EligibleCount++
+			coupon, created, createErr := s.Coupons.Issue(ctx, tx, c.UserID, preview.rule)
+			if errors.Is(createErr, couponrules.ErrOutOfStock) {
+				entry.Outcome = models.SettlementOutcomeOutOfStock
+				settlement.Entries = append(settlement.Entries, entry)
+				continue
+			}
+			if createErr != nil {
+				return nil, createErr
+			}
+			entry.Outcome = models.SettlementOutcomeAlreadyIssued
+			if created {
+				settlement.IssuedCount++
+				entry.Outcome = models.SettlementOutcomeIssued
+				entry.CouponID = &coupon.ID
+			}
 		}
+		settlement.Entries = append(settlement.Entries, entry)
 	}
 
+	if err = s.Repo.InsertLeaderboardSettlement(ctx, tx, settlement); err != nil {
+		return nil, err
+	}
 	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
-		return err
+		return nil, err
 	}
 
 	s.Logger.Info(
-		"Leaderboard top ten settlement completed",
-		zap.Int("eligible_users", eligibleCount),
-		zap.Int("issued_coupons", issuedCount),
+		"Leaderboard settlement completed",
+		zap.String("board", string(board)),
+		zap.String("trigger", trigger),
+		zap.Int("eligible_users", settlement.EligibleCount),
+		zap.Int("issued_coupons", settlement.IssuedCount),
 	)
 
-	return nil
+	return settlement, nil
+}
+
+func (s *Service) buildPreview(ctx context.Context, tx pgx.Tx, board repository.Board) (*Preview, error) {
+	rule, err := s.Coupons.RuleByTrigger(ctx, tx, models.CouponTriggerLeaderboardRank)
+	if errors.Is(err, couponrules.ErrRuleNotFound) {
+		return nil, ErrRuleMissing
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		rows     []repository.RankedUser
+		frozenAt *time.Time
+	)
+	freeze, err := s.Repo.GetActiveFreeze(ctx, tx, board)
+	switch {
+	case err == nil:
+		frozenAt = &freeze.FrozenAt
+		rows, err = s.Repo.ListFreezeEntries(ctx, tx, freeze.ID)
+	case errors.Is(err, repository.ErrNotFound):
+		rows, err = s.Repo.ListRankedUsers(ctx, tx, board)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	limit := rule.Threshold
+	policy := TiePolicy(config.Env().LeaderboardSettlementTiePolicy)
+	candidates := selectCandidates(rows, limit, policy)
+
+	userIDs := make([]string, len(candidates))
+	for i, c := range candidates {
+		userIDs[i] = c.UserID
+	}
+	issued, err := s.Repo.ListUserIDsWithDiscount(ctx, tx, rule.ID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &Preview{
+		Board:       string(board),
+		DiscountID:  rule.ID,
+		Amount:      rule.Amount,
+		RankLimit:   limit,
+		TiePolicy:   policy,
+		FrozenAt:    frozenAt,
+		GeneratedAt: time.Now().UTC(),
+		Candidates:  candidates,
+		rule:        *rule,
+	}
+	for i := range preview.Candidates {
+		preview.Candidates[i].AlreadyIssued = issued[preview.Candidates[i].UserID]
+		if preview.Candidates[i].Eligible {
+			preview.EligibleCount++
+		}
+	}
+	return preview, nil
 }
@@ -0,0 +1,90 @@
+// Package devicesessions opens the login sessions behind the staff_token and booth_token cookies.
+// The long-lived staffs.token / activities.token is only exchanged for a session at login.
+package devicesessions
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
+)
+
+const (
+	sessionTokenLength = 48
+	// MaxDeviceLabelLength caps the stored device label, in runes.
+	MaxDeviceLabelLength = 100
+)
+
+// OpenStaff creates a session for staffID that expires after STAFF_SESSION_TTL.
+// It returns the token for the staff_token cookie and the stored session.
+func OpenStaff(
+	ctx context.Context,
+	repo repository.Repository,
+	tx pgx.Tx,
+	staffID string,
+	deviceLabel string,
+) (string, *models.DeviceSession, error) {
+	token, session, err := newSession(staffID, deviceLabel, config.Env().StaffSessionTTL)
+	if err != nil {
+		return "", nil, err
+	}
+	if err = repo.CreateStaffSession(ctx, tx, helpers.HashSecret(token), session); err != nil {
+		return "", nil, err
+	}
+	return token, session, nil
+}
+
+// OpenBooth creates a session for activityID that expires after BOOTH_SESSION_TTL.
+// It returns the token for the booth_token cookie and the stored session.
+func OpenBooth(
+	ctx context.Context,
+	repo repository.Repository,
+	tx pgx.Tx,
+	activityID string,
+	deviceLabel string,
+) (string, *models.DeviceSession, error) {
+	token, session, err := newSession(activityID, deviceLabel, config.Env().BoothSessionTTL)
+	if err != nil {
+		return "", nil, err
+	}
+	if err = repo.CreateBoothSession(ctx, tx, helpers.HashSecret(token), session); err != nil {
+		return "", nil, err
+	}
+	return token, session, nil
+}
+
+// DeviceLabel picks the label shown to admins: the one the device sent, else its User-Agent,
+// trimmed to MaxDeviceLabelLength runes.
+func DeviceLabel(requested string, userAgent string) string {
+	label := strings.TrimSpace(requested)
+	if label == "" {
+		label = strings.TrimSpace(userAgent)
+	}
+	if utf8.RuneCountInString(label) > MaxDeviceLabelLength {
+		label = string([]rune(label)[:MaxDeviceLabelLength])
+	}
+	return label
+}
+
+func newSession(ownerID string, deviceLabel string, ttl time.Duration) (string, *models.DeviceSession, error) {
+	token, err := helpers.RandomAlphabetToken(sessionTokenLength)
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now().UTC()
+	return token, &models.DeviceSession{
+		ID:          uuid.NewString(),
+		OwnerID:     ownerID,
+		DeviceLabel: deviceLabel,
+		CreatedAt:   now,
+		LastSeenAt:  now,
+		ExpiresAt:   now.Add(ttl),
+	}, nil
+}
@@ -0,0 +1,28 @@
+package devicesessions_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sitcon-tw/2026-game/internal/service/devicesessions"
+)
+
+func TestDeviceLabel(t *testing.T) {
+	t.Parallel()
+
+	if got := devicesessions.DeviceLabel("  Booth iPad 2 ", "Mozilla/5.0"); got != "Booth iPad 2" {
+		t.Errorf("requested label: got %q", got)
+	}
+	if got := devicesessions.DeviceLabel("", "Mozilla/5.0"); got != "Mozilla/5.0" {
+		t.Errorf("user agent fallback: got %q", got)
+	}
+	if got := devicesessions.DeviceLabel(" ", ""); got != "" {
+		t.Errorf("empty: got %q", got)
+	}
+
+	long := devicesessions.DeviceLabel(strings.Repeat("攤", devicesessions.MaxDeviceLabelLength+5), "")
+	if n := utf8.RuneCountInString(long); n != devicesessions.MaxDeviceLabelLength {
+		t.Errorf("long label has %d runes, want %d", n, devicesessions.MaxDeviceLabelLength)
+	}
+}
@@ -0,0 +1,189 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// notifyChannel is the PostgreSQL NOTIFY channel shared by every API instance.
+const notifyChannel = "game_events"
+
+const (
+	listenRetryMin = time.Second
+	listenRetryMax = 30 * time.Second
+)
+
+// Kind names what happened.
+type Kind string
+
+// Kind values.
+const (
+	KindLevelPassed Kind = "level_passed"
+	KindCheckIn     Kind = "check_in"
+	KindFriendAdded Kind = "friend_added"
+	// KindLeaderboardFrozen is published when a board is frozen or unfrozen.
+	KindLeaderboardFrozen Kind = "leaderboard_frozen"
+	// KindGameConfigChanged is published when another game config version is activated.
+	KindGameConfigChanged Kind = "game_config_changed"
+)
+
+// Event is a ranking-relevant change. UserIDs lists every user whose standing may have moved.
+type Event struct {
+	Kind    Kind      `json:"kind"`
+	UserIDs []string  `json:"user_ids"`
+	At      time.Time `json:"at"`
+	Origin  string    `json:"origin"`
+}
+
+// Bus delivers events to in-process subscribers and, when backed by a pool,
+// to every other instance through LISTEN/NOTIFY.
+type Bus struct {
+	Pool   *pgxpool.Pool
+	Logger *zap.Logger
+
+	origin string
+	mu     sync.RWMutex
+	subs   map[int]chan Event
+	nextID int
+}
+
+// New creates an event bus. A nil pool keeps events inside this process.
+func New(pool *pgxpool.Pool, logger *zap.Logger) *Bus {
+	return &Bus{
+		Pool:   pool,
+		Logger: logger,
+		origin: uuid.NewString(),
+		subs:   make(map[int]chan Event),
+	}
+}
+
+// Publish delivers ev locally and notifies other instances. Call it after the change is committed.
+// Failures are logged; a lost event only delays the next leaderboard refresh.
+func (b *Bus) Publish(ctx context.Context, ev Event) {
+	if b == nil {
+		return
+	}
+	ev.Origin = b.origin
+	if ev.At.IsZero() {
+		ev.At = time.Now().UTC()
+	}
+
+	b.deliver(ev)
+
+	if b.Pool == nil {
+		return
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		b.Logger.Error("Failed to encode event", zap.String("kind", string(ev.Kind)), zap.Error(err))
+		return
+	}
+	if _, err = b.Pool.Exec(ctx, `SELECT pg_notify($1, $2)`, notifyChannel, string(payload)); err != nil {
+		b.Logger.Error("Failed to notify event", zap.String("kind", string(ev.Kind)), zap.Error(err))
+	}
+}
+
+// Subscribe returns a channel of events and a function that cancels the subscription.
+// Slow subscribers miss events rather than blocking publishers.
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Listen forwards events published by other instances until ctx is cancelled.
+func (b *Bus) Listen(ctx context.Context) {
+	if b.Pool == nil {
+		return
+	}
+	go b.listen(ctx)
+}
+
+func (b *Bus) deliver(ev Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func (b *Bus) listen(ctx context.Context) {
+	retry := listenRetryMin
+	for {
+		listened, err := b.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		// A connection that was listening dropped; only back off further on repeated failures to reconnect.
+		if listened {
+			retry = listenRetryMin
+		}
+		b.Logger.Warn("Event listener disconnected; retrying", zap.Duration("retry_in", retry), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		retry = min(retry*2, listenRetryMax)
+	}
+}
+
+// listenOnce listens on one pooled connection until it fails. listened reports whether LISTEN succeeded.
+func (b *Bus) listenOnce(ctx context.Context) (bool, error) {
+	conn, err := b.Pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		// Release returns the connection to the pool, so stop listening first or the next
+		// borrower would keep receiving notifications.
+		_, _ = conn.Exec(context.WithoutCancel(ctx), "UNLISTEN "+notifyChannel)
+		conn.Release()
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return false, err
+	}
+	b.Logger.Info("Listening for events from other instances", zap.String("channel", notifyChannel))
+
+	for {
+		n, waitErr := conn.Conn().WaitForNotification(ctx)
+		if waitErr != nil {
+			return true, waitErr
+		}
+
+		var ev Event
+		if err = json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			b.Logger.Warn("Ignored malformed event notification", zap.Error(err))
+			continue
+		}
+		// Local events were already delivered by Publish.
+		if ev.Origin == b.origin {
+			continue
+		}
+		b.deliver(ev)
+	}
+}
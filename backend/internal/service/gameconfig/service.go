@@ -0,0 +1,572 @@
+// Package gameconfig keeps the served level and sheet-music config in sync with the
+// game_config_versions table. Every import is validated before it is stored, stored before it is
+// served, and swapped in as a whole, so players never see a half-applied or broken config.
+//
+// When the database has no usable version and the CSV URLs cannot be read, the last config this
+// instance served is loaded from the on-disk cache, or else the config built into the binary, and
+// the service keeps retrying the database until it recovers.
+package gameconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/loader"
+	"go.uber.org/zap"
+)
+
+const (
+	// resyncInterval bounds how long an instance can serve a stale version after a lost notification,
+	// and how often a fallback config retries the database.
+	resyncInterval = time.Minute
+	syncTimeout    = 10 * time.Second
+	eventBuffer    = 8
+	// bootstrapCreator is recorded as created_by for the version imported at first startup.
+	bootstrapCreator = "system"
+)
+
+var (
+	// ErrInvalidConfig is returned when the CSV does not parse or fails level validation.
+	ErrInvalidConfig = errors.New("invalid game config")
+	// ErrSourceUnavailable is returned when the configured URL or file cannot be read.
+	ErrSourceUnavailable = errors.New("game config source unavailable")
+)
+
+// LoadedFrom says where this instance read the config it serves.
+type LoadedFrom string
+
+const (
+	// LoadedFromDatabase is the normal state: the active version in game_config_versions.
+	LoadedFromDatabase LoadedFrom = "database"
+	// LoadedFromCache is the last-known-good copy in GAME_CONFIG_CACHE_FILE.
+	LoadedFromCache LoadedFrom = "cache"
+	// LoadedFromEmbedded is the default config built into the binary.
+	LoadedFromEmbedded LoadedFrom = "embedded"
+)
+
+// Status describes the served config for health checks.
+type Status struct {
+	Version    int        `json:"version"`
+	LoadedFrom LoadedFrom `json:"loaded_from"`
+	// Fallback is true while the config is not the database's active version.
+	Fallback bool      `json:"fallback"`
+	LoadedAt time.Time `json:"loaded_at"`
+	// LastError is why the database version could not be served; empty when healthy.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Service imports, activates and serves game config versions.
+type Service struct {
+	Repo   repository.Repository
+	Logger *zap.Logger
+	Events *events.Bus
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// New creates the game config service. Call Init before serving requests.
+func New(repo repository.Repository, logger *zap.Logger, bus *events.Bus) *Service {
+	return &Service{
+		Repo:   repo,
+		Logger: logger,
+		Events: bus,
+	}
+}
+
+// Init serves the active version. On an empty table it imports LEVEL_CSV_URL and
+// SHEET_MUSIC_CSV_URL as version 1. If neither works it falls back to the cache or the embedded
+// config with a warning; an error means not even the embedded config could be served.
+func (s *Service) Init(ctx context.Context) error {
+	err := s.load(ctx)
+	if err == nil {
+		return nil
+	}
+
+	s.Logger.Warn("Game config unavailable, serving fallback", zap.Error(err))
+	return s.fallback(err)
+}
+
+// Start follows activations made by other instances until ctx is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+// Status reports where the served config came from.
+func (s *Service) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Import reads source, validates it and activates it as a new version. levelsCSV and tracks are
+// only used for models.GameConfigSourceUpload; the other sources read the configured URLs or files.
+func (s *Service) Import(
+	ctx context.Context,
+	source models.GameConfigSource,
+	levelsCSV []byte,
+	tracks []models.GameConfigTrack,
+	createdBy string,
+) (*models.GameConfigVersion, error) {
+	if source != models.GameConfigSourceUpload {
+		var err error
+		if levelsCSV, tracks, err = readSource(source); err != nil {
+			return nil, err
+		}
+	}
+	cfg, err := parse(levelsCSV, tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.store(ctx, source, levelsCSV, tracks, createdBy, false)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Version = version.Version
+	s.serve(cfg, version)
+	s.publish(ctx)
+	return version, nil
+}
+
+// Activate serves a stored version again, e.g. to roll back a bad import.
+// Returns repository.ErrNotFound if the version does not exist.
+func (s *Service) Activate(ctx context.Context, version int) (*models.GameConfigVersion, error) {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	stored, err := s.Repo.GetGameConfigVersion(ctx, tx, version)
+	if err != nil {
+		return nil, err
+	}
+	// Stored versions were valid when imported; re-check in case validation has since tightened.
+	cfg, err := parse([]byte(stored.LevelsCSV), stored.Tracks)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.Repo.ActivateGameConfigVersion(ctx, tx, version); err != nil {
+		return nil, err
+	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	cfg.Version = stored.Version
+	s.serve(cfg, stored)
+	s.publish(ctx)
+
+	activated, err := s.get(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	activated.Active = true
+	return activated, nil
+}
+
+// load serves the database's active version, importing the configured URLs first when no version
+// exists yet.
+func (s *Service) load(ctx context.Context) error {
+	err := s.sync(ctx)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	levelsCSV, tracks, err := readSource(models.GameConfigSourceURL)
+	if err != nil {
+		return err
+	}
+	cfg, err := parse(levelsCSV, tracks)
+	if err != nil {
+		return err
+	}
+	version, err := s.store(ctx, models.GameConfigSourceURL, levelsCSV, tracks, bootstrapCreator, true)
+	if err != nil {
+		return err
+	}
+	if version == nil {
+		// Another instance imported first; serve whatever it activated.
+		return s.sync(ctx)
+	}
+	cfg.Version = version.Version
+	s.serve(cfg, version)
+	return nil
+}
+
+// fallback serves the on-disk cache, or the embedded config if there is no usable cache.
+// cause is reported in Status until the database version is served again.
+func (s *Service) fallback(cause error) error {
+	if path := config.Env().GameConfigCacheFile; path != "" {
+		cached, err := loader.ReadCache(path)
+		if err == nil {
+			var cfg *config.GameConfig
+			if cfg, err = parse([]byte(cached.LevelsCSV), fromCache(cached.Tracks)); err == nil {
+				cfg.Version = cached.Version
+				s.serveFallback(cfg, LoadedFromCache, cause)
+				return nil
+			}
+		}
+		s.Logger.Warn("Game config cache unusable", zap.String("path", path), zap.Error(err))
+	}
+
+	levelsCSV, sheetCSV := loader.DefaultCSV()
+	cfg, err := parse(levelsCSV, []models.GameConfigTrack{defaultTrack(sheetCSV)})
+	if err != nil {
+		return fmt.Errorf("embedded game config: %w", err)
+	}
+	s.serveFallback(cfg, LoadedFromEmbedded, cause)
+	return nil
+}
+
+// store inserts and activates a version in one transaction. With bootstrap set, it returns nil
+// instead when another instance has already stored a version.
+func (s *Service) store(
+	ctx context.Context,
+	source models.GameConfigSource,
+	levelsCSV []byte,
+	tracks []models.GameConfigTrack,
+	createdBy string,
+	bootstrap bool,
+) (*models.GameConfigVersion, error) {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	version := &models.GameConfigVersion{
+		Source:    source,
+		LevelsCSV: string(levelsCSV),
+		CreatedBy: createdBy,
+		Tracks:    tracks,
+	}
+	if err = s.Repo.InsertGameConfigVersion(ctx, tx, version); err != nil {
+		return nil, err
+	}
+	if bootstrap && version.Version != 1 {
+		return nil, nil //nolint:nilnil // nil version means someone else bootstrapped
+	}
+	if err = s.Repo.ActivateGameConfigVersion(ctx, tx, version.Version); err != nil {
+		return nil, err
+	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	return s.get(ctx, version.Version)
+}
+
+func (s *Service) get(ctx context.Context, version int) (*models.GameConfigVersion, error) {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	stored, err := s.Repo.GetGameConfigVersion(ctx, tx, version)
+	if err != nil {
+		return nil, err
+	}
+	return stored, s.Repo.CommitTransaction(ctx, tx)
+}
+
+func (s *Service) run(ctx context.Context) {
+	var evs <-chan events.Event
+	if s.Events != nil {
+		ch, cancel := s.Events.Subscribe(eventBuffer)
+		defer cancel()
+		evs = ch
+	}
+
+	ticker := time.NewTicker(resyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-evs:
+			if ev.Kind != events.KindGameConfigChanged {
+				continue
+			}
+		case <-ticker.C:
+		}
+
+		syncCtx, cancel := context.WithTimeout(ctx, syncTimeout)
+		var err error
+		if s.Status().Fallback {
+			err = s.load(syncCtx)
+		} else {
+			err = s.sync(syncCtx)
+		}
+		cancel()
+		if err != nil {
+			s.Logger.Error("Failed to sync game config", zap.Error(err))
+		}
+		s.recordError(err)
+	}
+}
+
+// sync serves the active version from the database if it differs from the one in memory.
+// Returns repository.ErrNotFound when no version has been activated yet.
+func (s *Service) sync(ctx context.Context) error {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	active, err := s.Repo.GetActiveGameConfigVersion(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return err
+	}
+
+	if status := s.Status(); !status.Fallback && status.Version == active.Version {
+		return nil
+	}
+	cfg, err := parse([]byte(active.LevelsCSV), active.Tracks)
+	if err != nil {
+		return fmt.Errorf("active game config version %d: %w", active.Version, err)
+	}
+	cfg.Version = active.Version
+	s.serve(cfg, active)
+	return nil
+}
+
+// serve swaps in a database version and refreshes the on-disk cache.
+func (s *Service) serve(cfg *config.GameConfig, version *models.GameConfigVersion) {
+	config.SetActiveGameConfig(cfg)
+	s.setStatus(Status{
+		Version:    cfg.Version,
+		LoadedFrom: LoadedFromDatabase,
+		LoadedAt:   time.Now().UTC(),
+	})
+	s.Logger.Info("Game config version served",
+		zap.Int("version", cfg.Version),
+		zap.Int("level_ranges", len(cfg.Levels)),
+		zap.Int("tracks", len(cfg.Tracks)),
+		zap.Int("notes", cfg.NoteCount()),
+	)
+
+	path := config.Env().GameConfigCacheFile
+	if path == "" {
+		return
+	}
+	err := loader.WriteCache(path, loader.CachedConfig{
+		Version:   version.Version,
+		LevelsCSV: version.LevelsCSV,
+		Tracks:    toCache(version.Tracks),
+		SavedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		s.Logger.Warn("Failed to write game config cache", zap.String("path", path), zap.Error(err))
+	}
+}
+
+func (s *Service) serveFallback(cfg *config.GameConfig, from LoadedFrom, cause error) {
+	config.SetActiveGameConfig(cfg)
+	s.setStatus(Status{
+		Version:    cfg.Version,
+		LoadedFrom: from,
+		Fallback:   true,
+		LoadedAt:   time.Now().UTC(),
+		LastError:  cause.Error(),
+	})
+	s.Logger.Warn("Game config fallback served",
+		zap.Error(cause),
+		zap.String("loaded_from", string(from)),
+		zap.Int("version", cfg.Version),
+		zap.Int("level_ranges", len(cfg.Levels)),
+		zap.Int("tracks", len(cfg.Tracks)),
+		zap.Int("notes", cfg.NoteCount()),
+	)
+}
+
+func (s *Service) setStatus(status Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// recordError keeps the latest sync error in Status; nil clears it once the database is healthy.
+func (s *Service) recordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case err != nil:
+		s.status.LastError = err.Error()
+	case !s.status.Fallback:
+		s.status.LastError = ""
+	}
+}
+
+func (s *Service) publish(ctx context.Context) {
+	if s.Events != nil {
+		s.Events.Publish(ctx, events.Event{Kind: events.KindGameConfigChanged})
+	}
+}
+
+// readSource reads the level CSV and sheet-music tracks from the configured URLs or files: the
+// single sheet-music file becomes the default track, and every row of the track manifest adds one.
+func readSource(source models.GameConfigSource) ([]byte, []models.GameConfigTrack, error) {
+	var read func(string) ([]byte, error)
+	var levelsPath, sheetPath, manifestPath string
+	env := config.Env()
+	switch source {
+	case models.GameConfigSourceURL:
+		read, levelsPath, sheetPath, manifestPath = loader.Fetch,
+			env.LevelCSVURL, env.SheetMusicCSVURL, env.SheetTracksCSVURL
+	case models.GameConfigSourceFile:
+		read, levelsPath, sheetPath, manifestPath = loader.ReadFile,
+			env.LevelCSVFile, env.SheetMusicCSVFile, env.SheetTracksCSVFile
+	case models.GameConfigSourceUpload:
+		return nil, nil, fmt.Errorf("%w: uploads carry their own CSV", ErrInvalidConfig)
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown source %q", ErrInvalidConfig, source)
+	}
+
+	levelsCSV, err := read(levelsPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: level csv: %w", ErrSourceUnavailable, err)
+	}
+
+	var tracks []models.GameConfigTrack
+	if sheetPath != "" {
+		sheetCSV, readErr := read(sheetPath)
+		if readErr != nil {
+			return nil, nil, fmt.Errorf("%w: sheet music csv: %w", ErrSourceUnavailable, readErr)
+		}
+		if sheetCSV, readErr = sheetToCSV(sheetPath, sheetCSV); readErr != nil {
+			return nil, nil, fmt.Errorf("%w: sheet music: %w", ErrInvalidConfig, readErr)
+		}
+		tracks = append(tracks, defaultTrack(sheetCSV))
+	}
+	if manifestPath != "" {
+		manifestTracks, readErr := readManifest(read, manifestPath)
+		if readErr != nil {
+			return nil, nil, readErr
+		}
+		tracks = append(tracks, manifestTracks...)
+	}
+	if len(tracks) == 0 {
+		return nil, nil, fmt.Errorf("%w: no sheet music csv or track manifest configured", ErrSourceUnavailable)
+	}
+	return levelsCSV, tracks, nil
+}
+
+// readManifest reads a track manifest and every sheet it lists. Sheets are always fetched by URL
+// (http, https or file://), whichever way the manifest itself was read, and MIDI sheets are stored
+// as the CSV they convert to.
+func readManifest(read func(string) ([]byte, error), path string) ([]models.GameConfigTrack, error) {
+	body, err := read(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: track manifest csv: %w", ErrSourceUnavailable, err)
+	}
+	sources, err := loader.ParseTrackManifest(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: track manifest csv: %w", ErrInvalidConfig, err)
+	}
+
+	tracks := make([]models.GameConfigTrack, 0, len(sources))
+	for _, src := range sources {
+		sheetCSV, fetchErr := loader.Fetch(src.SheetURL)
+		if fetchErr != nil {
+			return nil, fmt.Errorf("%w: track %q: %w", ErrSourceUnavailable, src.TrackID, fetchErr)
+		}
+		if sheetCSV, fetchErr = sheetToCSV(src.SheetURL, sheetCSV); fetchErr != nil {
+			return nil, fmt.Errorf("%w: track %q: %w", ErrInvalidConfig, src.TrackID, fetchErr)
+		}
+		tracks = append(tracks, models.GameConfigTrack{
+			TrackID:       src.TrackID,
+			Tempo:         src.Tempo,
+			TimeSignature: src.TimeSignature,
+			SheetCSV:      string(sheetCSV),
+		})
+	}
+	return tracks, nil
+}
+
+// sheetToCSV converts a sheet read from a .mid or .midi file into the sheet music CSV; other
+// sheets are returned unchanged.
+func sheetToCSV(name string, body []byte) ([]byte, error) {
+	if !loader.IsMIDI(name) {
+		return body, nil
+	}
+	return loader.MIDIToSheetCSV(body)
+}
+
+// defaultTrack wraps a sheet-music file configured without a manifest.
+func defaultTrack(sheetCSV []byte) models.GameConfigTrack {
+	return models.GameConfigTrack{
+		TrackID:       models.DefaultTrackID,
+		Tempo:         loader.DefaultTempo,
+		TimeSignature: loader.DefaultTimeSignature,
+		SheetCSV:      string(sheetCSV),
+	}
+}
+
+// parse validates the level CSV and tracks. The returned config has version 0 until it is stored.
+func parse(levelsCSV []byte, tracks []models.GameConfigTrack) (*config.GameConfig, error) {
+	levels, err := loader.ParseLevels(bytes.NewReader(levelsCSV))
+	if err != nil {
+		return nil, fmt.Errorf("%w: level csv: %w", ErrInvalidConfig, err)
+	}
+	sheets := make([]models.SheetMusic, 0, len(tracks))
+	for _, track := range tracks {
+		notes, parseErr := loader.ParseSheetMusic(strings.NewReader(track.SheetCSV))
+		if parseErr != nil {
+			return nil, fmt.Errorf("%w: track %q: %w", ErrInvalidConfig, track.TrackID, parseErr)
+		}
+		sheets = append(sheets, models.SheetMusic{
+			TrackID:       track.TrackID,
+			Tempo:         track.Tempo,
+			TimeSignature: track.TimeSignature,
+			Notes:         notes,
+		})
+	}
+	cfg, err := config.NewGameConfig(0, levels, sheets)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return cfg, nil
+}
+
+func toCache(tracks []models.GameConfigTrack) []loader.CachedTrack {
+	out := make([]loader.CachedTrack, len(tracks))
+	for i, t := range tracks {
+		out[i] = loader.CachedTrack{
+			TrackID:       t.TrackID,
+			Tempo:         t.Tempo,
+			TimeSignature: t.TimeSignature,
+			SheetCSV:      t.SheetCSV,
+		}
+	}
+	return out
+}
+
+func fromCache(tracks []loader.CachedTrack) []models.GameConfigTrack {
+	out := make([]models.GameConfigTrack, len(tracks))
+	for i, t := range tracks {
+		out[i] = models.GameConfigTrack{
+			TrackID:       t.TrackID,
+			Tempo:         t.Tempo,
+			TimeSignature: t.TimeSignature,
+			SheetCSV:      t.SheetCSV,
+		}
+	}
+	return out
+}
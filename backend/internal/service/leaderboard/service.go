@@ -0,0 +1,194 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/events"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"go.uber.org/zap"
+)
+
+const (
+	refreshTimeout = 10 * time.Second
+	// StreamTopN is how many leading rows are pushed to stream watchers when they change.
+	StreamTopN  = 10
+	eventBuffer = 64
+)
+
+// Service keeps in-memory snapshots of every board that ranks all users the same way.
+// Viewer-scoped boards (friends, group) are small and stay on the database path.
+// Ranking-relevant events on the bus mark the snapshots stale, and every rebuild is diffed
+// against the previous snapshot and pushed to watchers.
+type Service struct {
+	Repo   repository.Repository
+	Logger *zap.Logger
+	Events *events.Bus
+
+	mu        sync.RWMutex
+	snapshots map[repository.Board]*Snapshot
+	dirty     chan struct{}
+	watchers  watchers
+}
+
+// New creates the leaderboard cache. Call Start to load and refresh snapshots.
+func New(repo repository.Repository, logger *zap.Logger, bus *events.Bus) *Service {
+	return &Service{
+		Repo:      repo,
+		Logger:    logger,
+		Events:    bus,
+		snapshots: make(map[repository.Board]*Snapshot),
+		dirty:     make(chan struct{}, 1),
+	}
+}
+
+// Start loads the first snapshots and keeps them fresh until ctx is cancelled.
+// Boards are rebuilt on the next tick after Invalidate, and at least once per max age.
+func (s *Service) Start(ctx context.Context) {
+	s.refreshAll(ctx)
+	go s.run(ctx)
+	if s.Events != nil {
+		go s.consumeEvents(ctx)
+	}
+}
+
+// Watch returns a channel of snapshot updates and a function that stops watching.
+// Updates are dropped for watchers that fall behind; the next update carries the latest state.
+func (s *Service) Watch() (<-chan Update, func()) {
+	return s.watchers.add()
+}
+
+// Snapshot returns the latest snapshot of board, or false if the board is not cached.
+func (s *Service) Snapshot(board repository.Board) (*Snapshot, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snap, ok := s.snapshots[board]
+	return snap, ok
+}
+
+// Invalidate marks the cached boards stale after a ranking-relevant write.
+// It never blocks; bursts of writes collapse into a single rebuild.
+func (s *Service) Invalidate() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) run(ctx context.Context) {
+	interval := config.Env().LeaderboardRefreshInterval
+	maxAge := config.Env().LeaderboardMaxAge
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastRefresh := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		select {
+		case <-s.dirty:
+		default:
+			if time.Since(lastRefresh) < maxAge {
+				continue
+			}
+		}
+
+		s.refreshAll(ctx)
+		lastRefresh = time.Now()
+	}
+}
+
+func (s *Service) consumeEvents(ctx context.Context) {
+	evs, cancel := s.Events.Subscribe(eventBuffer)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-evs:
+			if ev.Kind == events.KindGameConfigChanged {
+				continue
+			}
+			s.Invalidate()
+		}
+	}
+}
+
+func (s *Service) refreshAll(ctx context.Context) {
+	for _, board := range cachedBoards() {
+		if err := s.refresh(ctx, board); err != nil {
+			s.Logger.Error("Failed to refresh leaderboard snapshot", zap.String("board", string(board)), zap.Error(err))
+		}
+	}
+}
+
+func (s *Service) refresh(ctx context.Context, board repository.Board) error {
+	refreshCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	tx, err := s.Repo.StartTransaction(refreshCtx)
+	if err != nil {
+		return err
+	}
+	defer s.Repo.DeferRollback(refreshCtx, tx)
+
+	takenAt := time.Now().UTC()
+	var (
+		rows     []repository.RankedUser
+		frozenAt *time.Time
+	)
+	freeze, err := s.Repo.GetActiveFreeze(refreshCtx, tx, board)
+	switch {
+	case err == nil:
+		frozenAt = &freeze.FrozenAt
+		rows, err = s.Repo.ListFreezeEntries(refreshCtx, tx, freeze.ID)
+	case errors.Is(err, repository.ErrNotFound):
+		rows, err = s.Repo.ListRankedUsers(refreshCtx, tx, board)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = s.Repo.CommitTransaction(refreshCtx, tx); err != nil {
+		return err
+	}
+
+	snap := newSnapshot(board, rows, takenAt)
+	snap.FrozenAt = frozenAt
+	s.mu.Lock()
+	prev := s.snapshots[board]
+	s.snapshots[board] = snap
+	s.mu.Unlock()
+
+	if update, changed := diffSnapshots(prev, snap, StreamTopN); changed {
+		s.watchers.broadcast(update)
+	}
+	return nil
+}
+
+// cachedBoards lists every registered board that does not depend on the viewer.
+func cachedBoards() []repository.Board {
+	var boards []repository.Board
+	for _, board := range repository.Boards() {
+		strategy, _ := repository.LookupRankingStrategy(board)
+		if !strategy.Scoped() {
+			boards = append(boards, board)
+		}
+	}
+	return boards
+}
@@ -0,0 +1,82 @@
+package leaderboard
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+// Snapshot is an immutable, fully ranked copy of one board.
+// Rows are ordered exactly like the database ranking, so page, rank and neighbour lookups
+// never touch the users table.
+// FrozenAt is set when the board is frozen and the rows come from the freeze.
+type Snapshot struct {
+	Board    repository.Board
+	TakenAt  time.Time
+	FrozenAt *time.Time
+
+	rows     []repository.RankedUser
+	position map[string]int
+}
+
+func newSnapshot(board repository.Board, rows []repository.RankedUser, takenAt time.Time) *Snapshot {
+	position := make(map[string]int, len(rows))
+	for i, row := range rows {
+		position[row.User.ID] = i
+	}
+	return &Snapshot{
+		Board:    board,
+		TakenAt:  takenAt,
+		rows:     rows,
+		position: position,
+	}
+}
+
+// Len returns the number of ranked users.
+func (s *Snapshot) Len() int {
+	return len(s.rows)
+}
+
+// Page returns up to limit rows starting at offset.
+func (s *Snapshot) Page(limit, offset int) []repository.RankedUser {
+	if offset < 0 || offset >= len(s.rows) || limit <= 0 {
+		return []repository.RankedUser{}
+	}
+	end := min(offset+limit, len(s.rows))
+	return s.rows[offset:end]
+}
+
+// Find returns the user's row, or false if the user was not on the board when the snapshot was taken.
+func (s *Snapshot) Find(userID string) (repository.RankedUser, bool) {
+	i, ok := s.position[userID]
+	if !ok {
+		return repository.RankedUser{}, false
+	}
+	return s.rows[i], true
+}
+
+// Around returns the rows within ±span positions of the user, including the user.
+func (s *Snapshot) Around(userID string, span int) []repository.RankedUser {
+	i, ok := s.position[userID]
+	if !ok {
+		return []repository.RankedUser{}
+	}
+	start := max(i-span, 0)
+	end := min(i+span+1, len(s.rows))
+	return s.rows[start:end]
+}
+
+// ProgressRank returns the rank user would have with their given progress on a board ordered by
+// progress, such as the overall board, against everyone else as of the snapshot. Ties share a
+// rank like the database ranking does.
+func (s *Snapshot) ProgressRank(user models.User) int {
+	ahead := sort.Search(len(s.rows), func(i int) bool {
+		return !repository.ProgressAhead(s.rows[i].User, user)
+	})
+	if i, ok := s.position[user.ID]; ok && i < ahead {
+		ahead--
+	}
+	return ahead + 1
+}
@@ -0,0 +1,49 @@
+//nolint:testpackage // builds snapshots directly instead of loading them from the database
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+func TestProgressRank(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 3, 21, 9, 0, 0, 0, time.UTC)
+	player := func(id string, level, unlock int, passedAfter time.Duration) models.User {
+		return models.User{ID: id, CurrentLevel: level, UnlockLevel: unlock, LastPassTime: start.Add(passedAfter)}
+	}
+	snap := newSnapshot(repository.BoardOverall, []repository.RankedUser{
+		{User: player("a", 10, 12, 0), Rank: 1},
+		{User: player("b", 8, 10, time.Minute), Rank: 2},
+		{User: player("me", 8, 10, 2*time.Minute), Rank: 3},
+		{User: player("c", 8, 9, 0), Rank: 4},
+		{User: player("d", 3, 5, 0), Rank: 5},
+	}, start)
+
+	tests := []struct {
+		name string
+		user models.User
+		want int
+	}{
+		{"unchanged", player("me", 8, 10, 2*time.Minute), 3},
+		{"tied with the row ahead", player("me", 8, 10, time.Minute), 2},
+		{"passed a level", player("me", 9, 10, time.Hour), 2},
+		{"took the lead", player("me", 11, 12, time.Hour), 1},
+		{"new user", player("new", 0, 1, 0), 6},
+		{"new user ties the last row", player("new", 3, 5, 0), 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := snap.ProgressRank(tt.user); got != tt.want {
+				t.Errorf("ProgressRank = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
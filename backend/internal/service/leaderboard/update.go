@@ -0,0 +1,105 @@
+package leaderboard
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+// watcherBuffer is how many updates a slow watcher may lag behind before updates are dropped.
+const watcherBuffer = 4
+
+// RankChange is one user's movement between two snapshots.
+type RankChange struct {
+	Previous int
+	Current  int
+	Score    int
+}
+
+// Update describes what changed when a board snapshot was rebuilt.
+// Top is set only when the first TopN rows changed; Changes holds every user whose rank or score moved.
+type Update struct {
+	Board      repository.Board
+	SnapshotAt time.Time
+	Top        []repository.RankedUser
+	TopChanged bool
+	Changes    map[string]RankChange
+}
+
+type watchers struct {
+	mu     sync.RWMutex
+	subs   map[int]chan Update
+	nextID int
+}
+
+func (w *watchers) add() (<-chan Update, func()) {
+	ch := make(chan Update, watcherBuffer)
+
+	w.mu.Lock()
+	if w.subs == nil {
+		w.subs = make(map[int]chan Update)
+	}
+	id := w.nextID
+	w.nextID++
+	w.subs[id] = ch
+	w.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			delete(w.subs, id)
+			w.mu.Unlock()
+		})
+	}
+}
+
+func (w *watchers) broadcast(u Update) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, ch := range w.subs {
+		select {
+		case ch <- u:
+		default:
+		}
+	}
+}
+
+// diffSnapshots compares two snapshots of the same board. prev may be nil on the first load,
+// in which case nothing is reported.
+func diffSnapshots(prev, next *Snapshot, topN int) (Update, bool) {
+	u := Update{
+		Board:      next.Board,
+		SnapshotAt: next.TakenAt,
+		Top:        next.Page(topN, 0),
+		Changes:    make(map[string]RankChange),
+	}
+	if prev == nil {
+		return u, false
+	}
+
+	prevTop := prev.Page(topN, 0)
+	if len(prevTop) != len(u.Top) {
+		u.TopChanged = true
+	} else {
+		for i := range prevTop {
+			if prevTop[i].User.ID != u.Top[i].User.ID ||
+				prevTop[i].Rank != u.Top[i].Rank ||
+				prevTop[i].Score != u.Top[i].Score {
+				u.TopChanged = true
+				break
+			}
+		}
+	}
+
+	for _, row := range next.rows {
+		old, ok := prev.Find(row.User.ID)
+		if ok && old.Rank == row.Rank && old.Score == row.Score {
+			continue
+		}
+		u.Changes[row.User.ID] = RankChange{Previous: old.Rank, Current: row.Rank, Score: row.Score}
+	}
+
+	return u, u.TopChanged || len(u.Changes) > 0
+}
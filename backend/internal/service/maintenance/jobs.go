@@ -0,0 +1,152 @@
+// Package maintenance holds housekeeping jobs that keep tables from growing without bound.
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/internal/service/scheduler"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"go.uber.org/zap"
+)
+
+const (
+	// PlaySessionCleanupJob deletes play sessions that expired without a submission.
+	PlaySessionCleanupJob = "play_session_cleanup"
+
+	playSessionCleanupInterval = time.Hour
+	// playSessionRetention keeps expired sessions around long enough to debug rejected submissions.
+	playSessionRetention = 24 * time.Hour
+
+	// QRNonceCleanupJob deletes used one-time QR nonces whose tokens have expired.
+	QRNonceCleanupJob = "qr_nonce_cleanup"
+
+	qrNonceCleanupInterval = time.Hour
+
+	// NotificationCleanupJob deletes notifications older than NOTIFICATION_RETENTION.
+	NotificationCleanupJob = "notification_cleanup"
+
+	notificationCleanupInterval = time.Hour
+
+	// AdminSessionCleanupJob deletes expired admin sessions.
+	AdminSessionCleanupJob = "admin_session_cleanup"
+
+	adminSessionCleanupInterval = time.Hour
+
+	// DeviceSessionCleanupJob deletes expired staff and booth sessions.
+	DeviceSessionCleanupJob = "device_session_cleanup"
+
+	deviceSessionCleanupInterval = time.Hour
+)
+
+// RegisterJobs registers the recurring housekeeping jobs.
+func RegisterJobs(sched *scheduler.Scheduler, repo repository.Repository, logger *zap.Logger) {
+	sched.Register(scheduler.Job{
+		Name:     PlaySessionCleanupJob,
+		RunAt:    time.Now().UTC().Truncate(playSessionCleanupInterval).Add(playSessionCleanupInterval),
+		Interval: playSessionCleanupInterval,
+		Run: func(ctx context.Context) error {
+			return cleanupPlaySessions(ctx, repo, logger)
+		},
+	})
+	sched.Register(scheduler.Job{
+		Name:     QRNonceCleanupJob,
+		RunAt:    time.Now().UTC().Truncate(qrNonceCleanupInterval).Add(qrNonceCleanupInterval),
+		Interval: qrNonceCleanupInterval,
+		Run: func(ctx context.Context) error {
+			return cleanupQRNonces(ctx, repo, logger)
+		},
+	})
+	sched.Register(scheduler.Job{
+		Name:     NotificationCleanupJob,
+		RunAt:    time.Now().UTC().Truncate(notificationCleanupInterval).Add(notificationCleanupInterval),
+		Interval: notificationCleanupInterval,
+		Run: func(ctx context.Context) error {
+			return cleanupNotifications(ctx, repo, logger)
+		},
+	})
+	sched.Register(scheduler.Job{
+		Name:     AdminSessionCleanupJob,
+		RunAt:    time.Now().UTC().Truncate(adminSessionCleanupInterval).Add(adminSessionCleanupInterval),
+		Interval: adminSessionCleanupInterval,
+		Run: func(ctx context.Context) error {
+			return cleanupAdminSessions(ctx, repo, logger)
+		},
+	})
+	sched.Register(scheduler.Job{
+		Name:     DeviceSessionCleanupJob,
+		RunAt:    time.Now().UTC().Truncate(deviceSessionCleanupInterval).Add(deviceSessionCleanupInterval),
+		Interval: deviceSessionCleanupInterval,
+		Run: func(ctx context.Context) error {
+			return cleanupDeviceSessions(ctx, repo, logger)
+		},
+	})
+}
+
+func cleanupPlaySessions(ctx context.Context, repo repository.Repository, logger *zap.Logger) error {
+	cutoff := time.Now().UTC().Add(-playSessionRetention)
+	return cleanup(ctx, repo, logger, "Deleted stale play sessions",
+		func(ctx context.Context, tx pgx.Tx) (int64, error) {
+			return repo.DeleteStalePlaySessions(ctx, tx, cutoff)
+		})
+}
+
+func cleanupQRNonces(ctx context.Context, repo repository.Repository, logger *zap.Logger) error {
+	now := time.Now().UTC()
+	return cleanup(ctx, repo, logger, "Deleted expired qr token nonces",
+		func(ctx context.Context, tx pgx.Tx) (int64, error) {
+			return repo.DeleteExpiredQRTokenNonces(ctx, tx, now)
+		})
+}
+
+func cleanupNotifications(ctx context.Context, repo repository.Repository, logger *zap.Logger) error {
+	cutoff := time.Now().UTC().Add(-config.Env().NotificationRetention)
+	return cleanup(ctx, repo, logger, "Deleted old notifications",
+		func(ctx context.Context, tx pgx.Tx) (int64, error) {
+			return repo.DeleteNotificationsBefore(ctx, tx, cutoff)
+		})
+}
+
+func cleanupAdminSessions(ctx context.Context, repo repository.Repository, logger *zap.Logger) error {
+	now := time.Now().UTC()
+	return cleanup(ctx, repo, logger, "Deleted expired admin sessions",
+		func(ctx context.Context, tx pgx.Tx) (int64, error) {
+			return repo.DeleteExpiredAdminSessions(ctx, tx, now)
+		})
+}
+
+func cleanupDeviceSessions(ctx context.Context, repo repository.Repository, logger *zap.Logger) error {
+	now := time.Now().UTC()
+	return cleanup(ctx, repo, logger, "Deleted expired staff and booth sessions",
+		func(ctx context.Context, tx pgx.Tx) (int64, error) {
+			return repo.DeleteExpiredDeviceSessions(ctx, tx, now)
+		})
+}
+
+// cleanup runs del in its own transaction and logs msg with the number of deleted rows.
+func cleanup(
+	ctx context.Context,
+	repo repository.Repository,
+	logger *zap.Logger,
+	msg string,
+	del func(context.Context, pgx.Tx) (int64, error),
+) error {
+	tx, err := repo.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer repo.DeferRollback(ctx, tx)
+
+	deleted, err := del(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if err = repo.CommitTransaction(ctx, tx); err != nil {
+		return err
+	}
+
+	logger.Info(msg, zap.Int64("count", deleted))
+	return nil
+}
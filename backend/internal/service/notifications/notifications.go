@@ -0,0 +1,49 @@
+// Package notifications writes entries to the per-user notification inbox. Every function runs inside
+// the caller's transaction, so a notification is only kept if the change it describes is committed.
+package notifications
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+// CouponIssued tells the coupon's owner that they received it.
+func CouponIssued(
+	ctx context.Context,
+	repo repository.Repository,
+	tx pgx.Tx,
+	coupon *models.DiscountCoupon,
+	source models.CouponSource,
+) error {
+	return repo.InsertNotification(ctx, tx, &models.Notification{
+		UserID: coupon.UserID,
+		Kind:   models.NotificationKindCouponIssued,
+		Data: models.NotificationData{
+			CouponID:   coupon.ID,
+			DiscountID: coupon.DiscountID,
+			Price:      coupon.Price,
+			Source:     source,
+		},
+	})
+}
+
+// FriendAdded tells userID that friend added them.
+func FriendAdded(ctx context.Context, repo repository.Repository, tx pgx.Tx, userID string, friend *models.User) error {
+	return repo.InsertNotification(ctx, tx, &models.Notification{
+		UserID: userID,
+		Kind:   models.NotificationKindFriendAdded,
+		Data:   models.NotificationData{UserID: friend.ID, Nickname: friend.Nickname},
+	})
+}
+
+// GroupCheckIn tells userID that member checked in with them.
+func GroupCheckIn(ctx context.Context, repo repository.Repository, tx pgx.Tx, userID string, member *models.User) error {
+	return repo.InsertNotification(ctx, tx, &models.Notification{
+		UserID: userID,
+		Kind:   models.NotificationKindGroupCheckIn,
+		Data:   models.NotificationData{UserID: member.ID, Nickname: member.Nickname},
+	})
+}
@@ -0,0 +1,96 @@
+// Package qrtoken verifies one-time user QR tokens and consumes them so each is accepted once per purpose.
+package qrtoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
+)
+
+var (
+	// ErrInvalidToken is returned for malformed, expired or wrongly signed tokens,
+	// and for tokens issued for another purpose.
+	ErrInvalidToken = errors.New("invalid one-time qr token")
+	// ErrTokenUsed is returned when the token was already consumed for this purpose.
+	ErrTokenUsed = errors.New("one-time qr token already used")
+	// ErrUserNotFound is returned when the token names a user that does not exist.
+	ErrUserNotFound = errors.New("qr token user not found")
+
+	errLookupFailed = errors.New("qr token user lookup failed")
+)
+
+// Consume verifies token for purpose, records its nonce in tx and returns the user it belongs to.
+// The nonce is only kept if tx commits, so a scan that fails later can be retried with the same token.
+// Purpose-less qru1 tokens are accepted while config.IsLegacyUserQRAccepted.
+func Consume(
+	ctx context.Context,
+	repo repository.Repository,
+	tx pgx.Tx,
+	token string,
+	purpose helpers.QRPurpose,
+) (*models.User, error) {
+	return ConsumeAt(ctx, repo, tx, token, purpose, time.Now().UTC(), 0)
+}
+
+// ConsumeAt is Consume for a token scanned at scannedAt, e.g. queued by an offline booth.
+// The nonce is kept for retention past the token's own expiry so the same scan cannot be
+// uploaded again while late uploads are still accepted.
+func ConsumeAt(
+	ctx context.Context,
+	repo repository.Repository,
+	tx pgx.Tx,
+	token string,
+	purpose helpers.QRPurpose,
+	scannedAt time.Time,
+	retention time.Duration,
+) (*models.User, error) {
+	var user *models.User
+	lookup := func(userID string) (string, error) {
+		u, lookupErr := repo.GetUserByID(ctx, tx, userID)
+		if lookupErr != nil {
+			if errors.Is(lookupErr, repository.ErrNotFound) {
+				return "", ErrUserNotFound
+			}
+			return "", fmt.Errorf("%w: %w", errLookupFailed, lookupErr)
+		}
+		user = u
+		return u.QRCodeToken, nil
+	}
+
+	var claims *helpers.OneTimeQRClaims
+	var err error
+	switch {
+	case !helpers.IsLegacyUserQRToken(token):
+		claims, err = helpers.VerifyOneTimeQRToken(token, purpose, scannedAt, config.Env().UserQRPublicKey())
+		if err == nil {
+			// qru2 tokens are signed by the server key, so the user is only loaded once the token checks out.
+			_, err = lookup(claims.UserID)
+		}
+	case config.IsLegacyUserQRAccepted(scannedAt):
+		claims, err = helpers.VerifyLegacyUserQRToken(token, purpose, scannedAt, lookup)
+	default:
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) || errors.Is(err, errLookupFailed) {
+			return nil, err
+		}
+		return nil, ErrInvalidToken
+	}
+
+	err = repo.ConsumeQRTokenNonce(ctx, tx, claims.UserID, string(claims.Purpose), claims.Nonce, claims.ExpiresAt.Add(retention))
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, ErrTokenUsed
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,121 @@
+// Package redemption reverses coupon redemptions made at the cashier.
+package redemption
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+)
+
+var (
+	// ErrReasonRequired is returned when a void has no reason.
+	ErrReasonRequired = errors.New("void reason is required")
+	// ErrNotOwnRedemption is returned when staff try to void a redemption they did not make.
+	ErrNotOwnRedemption = errors.New("redemption was made by another staff")
+	// ErrAlreadyVoided is returned when the redemption has already been voided.
+	ErrAlreadyVoided = errors.New("redemption already voided")
+)
+
+// VoidRequest describes who is reversing which redemption and why.
+// StaffID is required for staff voids and must be nil for admin voids.
+type VoidRequest struct {
+	HistoryID string
+	Actor     string
+	StaffID   *string
+	Reason    string
+}
+
+// Void restores every coupon of a redemption to unused and records the reversal against its history row.
+// The history row itself is kept so reports can show and net out the void.
+// Returns repository.ErrNotFound if the redemption does not exist.
+func Void(
+	ctx context.Context,
+	repo repository.Repository,
+	tx pgx.Tx,
+	req VoidRequest,
+) (*models.CouponHistoryVoid, []models.DiscountCoupon, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, nil, ErrReasonRequired
+	}
+
+	history, err := repo.GetCouponHistoryForUpdate(ctx, tx, req.HistoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req.Actor == models.VoidActorStaff && (req.StaffID == nil || *req.StaffID != history.StaffID) {
+		return nil, nil, ErrNotOwnRedemption
+	}
+	if history.Void != nil {
+		return nil, nil, ErrAlreadyVoided
+	}
+
+	coupons, err := repo.RestoreDiscountsByHistory(ctx, tx, history.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	couponIDs := make([]string, len(coupons))
+	for i, c := range coupons {
+		couponIDs[i] = c.ID
+	}
+
+	void := &models.CouponHistoryVoid{
+		ID:        uuid.NewString(),
+		HistoryID: history.ID,
+		Actor:     req.Actor,
+		StaffID:   req.StaffID,
+		Reason:    reason,
+		Total:     history.Total,
+		CouponIDs: couponIDs,
+		VoidedAt:  time.Now().UTC(),
+	}
+	if err = repo.InsertCouponHistoryVoid(ctx, tx, void); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, nil, ErrAlreadyVoided
+		}
+		return nil, nil, err
+	}
+	return void, coupons, nil
+}
+
+// VoidBody is the request body of the staff and admin void endpoints.
+type VoidBody struct {
+	Reason string `json:"reason"`
+}
+
+// VoidResponse is returned by the void endpoints and recorded in their audit events.
+type VoidResponse struct {
+	Void            models.CouponHistoryVoid `json:"void"`
+	RestoredCoupons []models.DiscountCoupon  `json:"restored_coupons"`
+}
+
+// NewVoidResponse wraps the result of Void, reporting no restored coupons as an empty list.
+func NewVoidResponse(void *models.CouponHistoryVoid, coupons []models.DiscountCoupon) VoidResponse {
+	if coupons == nil {
+		coupons = []models.DiscountCoupon{}
+	}
+	return VoidResponse{Void: *void, RestoredCoupons: coupons}
+}
+
+// VoidErrorStatus maps an error from Void to the HTTP status and message the handlers respond with.
+func VoidErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, ErrReasonRequired):
+		return http.StatusBadRequest, "reason is required"
+	case errors.Is(err, ErrNotOwnRedemption):
+		return http.StatusForbidden, "redemption was made by another staff"
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound, "redemption not found"
+	case errors.Is(err, ErrAlreadyVoided):
+		return http.StatusConflict, "redemption already voided"
+	default:
+		return http.StatusInternalServerError, "failed to void redemption"
+	}
+}
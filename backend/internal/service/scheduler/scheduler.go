@@ -0,0 +1,472 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"go.uber.org/zap"
+)
+
+// schedulerLockKey elects the instance that runs due jobs for one tick.
+const schedulerLockKey int64 = 202603281700
+
+const defaultJobTimeout = time.Minute
+
+// leaseGrace is added to a job's timeout to get how long a claim lasts. A claim that outlives it
+// belongs to an instance that died mid-run, and the job is picked up again.
+const leaseGrace = time.Minute
+
+// Run triggers.
+const (
+	TriggerSchedule = "schedule"
+	TriggerCatchUp  = "catch_up"
+	TriggerAdmin    = "admin"
+)
+
+var (
+	// ErrUnknownJob is returned for a job that is not registered in this process.
+	ErrUnknownJob = errors.New("unknown scheduled job")
+	// ErrBusy is returned when another instance is claiming jobs or the job is already running.
+	ErrBusy = errors.New("scheduler is busy")
+	// ErrJobNotFailed is returned when retrying a job that has not failed.
+	ErrJobNotFailed = errors.New("scheduled job has not failed")
+)
+
+// Job is a unit of background work. Interval is zero for one-shot jobs.
+// Run must be safe to execute more than once; retries and admin triggers may repeat it.
+type Job struct {
+	Name     string
+	RunAt    time.Time
+	Interval time.Duration
+	Timeout  time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs from the scheduled_jobs table.
+// Every instance polls; the one holding the advisory lock claims one due job, commits, and runs it
+// outside any transaction, so a slow job holds neither the lock nor a row lock. Each job is claimed
+// only when it is about to start, so its lease does not run down while earlier jobs run.
+type Scheduler struct {
+	Repo   repository.Repository
+	Logger *zap.Logger
+
+	PollInterval time.Duration
+	MaxAttempts  int
+	RetryDelay   time.Duration
+
+	mu   sync.RWMutex
+	jobs map[string]Job
+}
+
+// New creates a scheduler configured from SCHEDULER_*. Register jobs before calling Start.
+func New(repo repository.Repository, logger *zap.Logger) *Scheduler {
+	cfg := config.Env()
+	return &Scheduler{
+		Repo:         repo,
+		Logger:       logger,
+		PollInterval: cfg.SchedulerPollInterval,
+		MaxAttempts:  cfg.SchedulerMaxAttempts,
+		RetryDelay:   cfg.SchedulerRetryDelay,
+		jobs:         make(map[string]Job),
+	}
+}
+
+// Register adds a job to this process.
+func (s *Scheduler) Register(job Job) {
+	if job.Timeout <= 0 {
+		job.Timeout = defaultJobTimeout
+	}
+	s.mu.Lock()
+	s.jobs[job.Name] = job
+	s.mu.Unlock()
+}
+
+// Names returns the registered job names in order.
+func (s *Scheduler) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start persists the registered jobs and polls for due ones until ctx is cancelled.
+// Jobs whose run_at passed while no instance was running are caught up on the first tick.
+func (s *Scheduler) Start(ctx context.Context) error {
+	if err := s.persist(ctx); err != nil {
+		return err
+	}
+	go s.loop(ctx)
+	return nil
+}
+
+func (s *Scheduler) persist(ctx context.Context) error {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	for _, name := range s.Names() {
+		job, _ := s.job(name)
+		row := &models.ScheduledJob{
+			Name:   job.Name,
+			Status: models.ScheduledJobPending,
+			RunAt:  job.RunAt.UTC(),
+		}
+		if job.Interval > 0 {
+			seconds := int(job.Interval / time.Second)
+			row.IntervalSeconds = &seconds
+		}
+		if err = s.Repo.UpsertScheduledJob(ctx, tx, row); err != nil {
+			return fmt.Errorf("register job %s: %w", job.Name, err)
+		}
+	}
+	return s.Repo.CommitTransaction(ctx, tx)
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.tick(ctx); err != nil && ctx.Err() == nil {
+			s.Logger.Error("Scheduler tick failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim is a job this instance marked as running. row is the job's state before the claim.
+type claim struct {
+	job        Job
+	row        models.ScheduledJob
+	trigger    string
+	leaseUntil time.Time
+}
+
+// tick runs every due job, one at a time, until none is left or another instance holds the lock.
+func (s *Scheduler) tick(ctx context.Context) error {
+	for ctx.Err() == nil {
+		c, ok, err := s.claimNext(ctx)
+		if err != nil || !ok {
+			return err
+		}
+
+		run := s.execute(ctx, c.job, c.row.RunAt, c.trigger)
+		err = s.finish(ctx, c, run, func(row *models.ScheduledJob) {
+			row.Status = models.ScheduledJobPending
+			s.advance(row, run, c.job.Interval, time.Now().UTC())
+		})
+		if err != nil {
+			s.Logger.Error("Failed to record scheduled job run", zap.String("job", c.job.Name), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// claimNext marks the earliest due job known to this process as running and returns it.
+// ok is false when no job is due or another instance holds the scheduler lock.
+func (s *Scheduler) claimNext(ctx context.Context) (claim, bool, error) {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return claim{}, false, err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	locked, err := s.Repo.TryAcquireAdvisoryXactLock(ctx, tx, schedulerLockKey)
+	if err != nil || !locked {
+		return claim{}, false, err
+	}
+
+	// Jobs registered by a different build are left for an instance that knows them.
+	now := time.Now().UTC()
+	row, err := s.Repo.GetDueScheduledJob(ctx, tx, now, s.Names())
+	if errors.Is(err, repository.ErrNotFound) {
+		return claim{}, false, nil
+	}
+	if err != nil {
+		return claim{}, false, err
+	}
+	job, _ := s.job(row.Name)
+
+	trigger := TriggerSchedule
+	if now.Sub(row.RunAt) > 2*s.PollInterval {
+		trigger = TriggerCatchUp
+	}
+	c, err := s.claim(ctx, tx, job, row, trigger, now)
+	if err != nil {
+		return claim{}, false, err
+	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return claim{}, false, err
+	}
+	return c, true, nil
+}
+
+// claim marks row as running until its lease ends. run_at keeps the scheduled time, so a claim
+// left behind by a crashed instance is picked up again on the same schedule once its lease expires.
+func (s *Scheduler) claim(
+	ctx context.Context,
+	tx pgx.Tx,
+	job Job,
+	row *models.ScheduledJob,
+	trigger string,
+	now time.Time,
+) (claim, error) {
+	c := claim{
+		job:     job,
+		row:     *row,
+		trigger: trigger,
+		// The column keeps microseconds; truncate so finish can compare it exactly.
+		leaseUntil: now.Add(job.Timeout + leaseGrace).Truncate(time.Microsecond),
+	}
+	row.Status = models.ScheduledJobRunning
+	row.LeaseUntil = &c.leaseUntil
+	if err := s.Repo.UpdateScheduledJob(ctx, tx, row); err != nil {
+		return claim{}, err
+	}
+	return c, nil
+}
+
+// finish records a run and applies update to the job's pre-claim state, releasing the lease.
+// If the claim was lost (its lease expired and another instance took the job) only the run is recorded.
+func (s *Scheduler) finish(
+	ctx context.Context,
+	c claim,
+	run *models.ScheduledJobRun,
+	update func(row *models.ScheduledJob),
+) error {
+	ctx = context.WithoutCancel(ctx)
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	current, err := s.Repo.GetScheduledJobForUpdate(ctx, tx, c.job.Name)
+	if err != nil {
+		return err
+	}
+	if err = s.Repo.InsertScheduledJobRun(ctx, tx, run); err != nil {
+		return err
+	}
+	if current.Status == models.ScheduledJobRunning && current.LeaseUntil != nil && current.LeaseUntil.Equal(c.leaseUntil) {
+		row := c.row
+		update(&row)
+		row.LeaseUntil = nil
+		if err = s.Repo.UpdateScheduledJob(ctx, tx, &row); err != nil {
+			return err
+		}
+	} else {
+		s.Logger.Warn("Scheduled job claim expired before the run finished", zap.String("job", c.job.Name))
+	}
+	return s.Repo.CommitTransaction(ctx, tx)
+}
+
+// Trigger runs a job immediately, outside its schedule. A successful run completes a one-shot job
+// and puts a recurring job, failed or not, back on its schedule at the next slot. A failed run
+// leaves the job as it was.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*models.ScheduledJobRun, error) {
+	job, ok := s.job(name)
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+
+	c, err := s.claimOne(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	run := s.execute(ctx, job, c.row.RunAt, TriggerAdmin)
+	err = s.finish(ctx, c, run, func(row *models.ScheduledJob) {
+		row.LastRunAt = &run.StartedAt
+		row.LastError = run.Error
+		switch {
+		case run.Status != models.ScheduledJobRunSucceeded:
+			// A job reclaimed from a crashed instance must not stay running.
+			if row.Status == models.ScheduledJobRunning {
+				row.Status = models.ScheduledJobPending
+			}
+		case job.Interval <= 0:
+			row.Attempts = 0
+			row.Status = models.ScheduledJobCompleted
+		default:
+			row.Attempts = 0
+			row.Status = models.ScheduledJobPending
+			row.RunAt = nextRunAt(row.RunAt, job.Interval, run.StartedAt)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// claimOne claims a single job for an admin trigger. Returns ErrBusy if it is already running
+// under an unexpired lease.
+func (s *Scheduler) claimOne(ctx context.Context, job Job) (claim, error) {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return claim{}, err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	locked, err := s.Repo.TryAcquireAdvisoryXactLock(ctx, tx, schedulerLockKey)
+	if err != nil {
+		return claim{}, err
+	}
+	if !locked {
+		return claim{}, ErrBusy
+	}
+
+	row, err := s.Repo.GetScheduledJobForUpdate(ctx, tx, job.Name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return claim{}, ErrUnknownJob
+		}
+		return claim{}, err
+	}
+	now := time.Now().UTC()
+	if row.Status == models.ScheduledJobRunning && (row.LeaseUntil == nil || row.LeaseUntil.After(now)) {
+		return claim{}, ErrBusy
+	}
+
+	c, err := s.claim(ctx, tx, job, row, TriggerAdmin, now)
+	if err != nil {
+		return claim{}, err
+	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return claim{}, err
+	}
+	return c, nil
+}
+
+// Retry puts a failed job back in the queue with a fresh attempt budget; the next tick runs it.
+func (s *Scheduler) Retry(ctx context.Context, name string) (*models.ScheduledJob, error) {
+	tx, err := s.Repo.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Repo.DeferRollback(ctx, tx)
+
+	row, err := s.Repo.GetScheduledJobForUpdate(ctx, tx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUnknownJob
+		}
+		return nil, err
+	}
+	if row.Status != models.ScheduledJobFailed {
+		return nil, ErrJobNotFailed
+	}
+
+	row.Status = models.ScheduledJobPending
+	row.Attempts = 0
+	row.RunAt = time.Now().UTC()
+	if err = s.Repo.UpdateScheduledJob(ctx, tx, row); err != nil {
+		return nil, err
+	}
+	if err = s.Repo.CommitTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (s *Scheduler) job(name string) (Job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[name]
+	return job, ok
+}
+
+// execute runs the job and returns the run to record. A failing job is reported through the run
+// status.
+func (s *Scheduler) execute(
+	ctx context.Context,
+	job Job,
+	scheduledFor time.Time,
+	trigger string,
+) *models.ScheduledJobRun {
+	run := &models.ScheduledJobRun{
+		ID:           uuid.NewString(),
+		JobName:      job.Name,
+		Trigger:      trigger,
+		ScheduledFor: &scheduledFor,
+		StartedAt:    time.Now().UTC(),
+		Status:       models.ScheduledJobRunSucceeded,
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	err := runSafely(jobCtx, job)
+	cancel()
+
+	run.FinishedAt = time.Now().UTC()
+	if err != nil {
+		msg := err.Error()
+		run.Status = models.ScheduledJobRunFailed
+		run.Error = &msg
+		s.Logger.Error("Scheduled job failed", zap.String("job", job.Name), zap.String("trigger", trigger), zap.Error(err))
+	} else {
+		s.Logger.Info("Scheduled job completed", zap.String("job", job.Name), zap.String("trigger", trigger))
+	}
+	return run
+}
+
+func runSafely(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// advance moves a job to its next state after a scheduled run.
+func (s *Scheduler) advance(row *models.ScheduledJob, run *models.ScheduledJobRun, interval time.Duration, now time.Time) {
+	row.LastRunAt = &run.StartedAt
+	row.LastError = run.Error
+
+	if run.Status == models.ScheduledJobRunSucceeded {
+		row.Attempts = 0
+		if interval <= 0 {
+			row.Status = models.ScheduledJobCompleted
+			return
+		}
+		row.RunAt = nextRunAt(row.RunAt, interval, now)
+		return
+	}
+
+	row.Attempts++
+	if row.Attempts >= s.MaxAttempts {
+		row.Status = models.ScheduledJobFailed
+		return
+	}
+	row.RunAt = now.Add(s.RetryDelay)
+}
+
+// nextRunAt returns the first slot on the runAt+k*interval grid after now.
+// Missed slots are skipped so a long outage results in a single catch-up run.
+func nextRunAt(runAt time.Time, interval time.Duration, now time.Time) time.Time {
+	if runAt.After(now) {
+		return runAt
+	}
+	missed := now.Sub(runAt)/interval + 1
+	return runAt.Add(missed * interval)
+}
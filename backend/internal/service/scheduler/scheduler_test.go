@@ -0,0 +1,432 @@
+package scheduler //nolint:testpackage // tests need access to unexported scheduling helpers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"go.uber.org/zap"
+)
+
+// jobRepo keeps scheduled_jobs in memory; any other call panics on the nil embed.
+// Transactions are not modelled: every write applies immediately.
+type jobRepo struct {
+	repository.Repository
+
+	mu            sync.Mutex
+	lockedByOther bool
+	jobs          map[string]models.ScheduledJob
+	runs          []models.ScheduledJobRun
+}
+
+func newJobRepo(rows ...models.ScheduledJob) *jobRepo {
+	r := &jobRepo{jobs: make(map[string]models.ScheduledJob)}
+	for _, row := range rows {
+		r.jobs[row.Name] = row
+	}
+	return r
+}
+
+func (r *jobRepo) StartTransaction(context.Context) (pgx.Tx, error) { return nil, nil }
+
+func (r *jobRepo) DeferRollback(context.Context, pgx.Tx) {}
+
+func (r *jobRepo) CommitTransaction(context.Context, pgx.Tx) error { return nil }
+
+func (r *jobRepo) TryAcquireAdvisoryXactLock(context.Context, pgx.Tx, int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.lockedByOther, nil
+}
+
+func (r *jobRepo) GetDueScheduledJob(
+	_ context.Context,
+	_ pgx.Tx,
+	now time.Time,
+	names []string,
+) (*models.ScheduledJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []models.ScheduledJob
+	for _, name := range names {
+		row, ok := r.jobs[name]
+		switch {
+		case !ok:
+		case row.Status == models.ScheduledJobPending && !row.RunAt.After(now):
+			due = append(due, row)
+		case row.Status == models.ScheduledJobRunning && row.LeaseUntil != nil && !row.LeaseUntil.After(now):
+			due = append(due, row)
+		}
+	}
+	if len(due) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].RunAt.Before(due[j].RunAt) })
+	return &due[0], nil
+}
+
+func (r *jobRepo) GetScheduledJobForUpdate(_ context.Context, _ pgx.Tx, name string) (*models.ScheduledJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row, ok := r.jobs[name]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &row, nil
+}
+
+func (r *jobRepo) UpdateScheduledJob(_ context.Context, _ pgx.Tx, job *models.ScheduledJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.Name] = *job
+	return nil
+}
+
+func (r *jobRepo) InsertScheduledJobRun(_ context.Context, _ pgx.Tx, run *models.ScheduledJobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, *run)
+	return nil
+}
+
+func (r *jobRepo) row(name string) models.ScheduledJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[name]
+}
+
+func newTestScheduler(repo *jobRepo, jobs ...Job) *Scheduler {
+	s := &Scheduler{
+		Repo:         repo,
+		Logger:       zap.NewNop(),
+		PollInterval: time.Minute,
+		MaxAttempts:  2,
+		RetryDelay:   time.Minute,
+		jobs:         make(map[string]Job),
+	}
+	for _, job := range jobs {
+		s.Register(job)
+	}
+	return s
+}
+
+func pendingRow(name string, runAt time.Time, interval time.Duration) models.ScheduledJob {
+	row := models.ScheduledJob{Name: name, Status: models.ScheduledJobPending, RunAt: runAt}
+	if interval > 0 {
+		seconds := int(interval / time.Second)
+		row.IntervalSeconds = &seconds
+	}
+	return row
+}
+
+func TestTickLeasesEachJobWhenItStarts(t *testing.T) {
+	t.Parallel()
+
+	slots := map[string]time.Time{
+		"first":  time.Now().UTC().Truncate(time.Hour),
+		"second": time.Now().UTC().Truncate(time.Hour).Add(time.Second),
+	}
+	repo := newJobRepo(pendingRow("first", slots["first"], time.Hour), pendingRow("second", slots["second"], time.Hour))
+
+	var secondBeforeRun models.ScheduledJob
+	sched := newTestScheduler(repo,
+		Job{Name: "first", Interval: time.Hour, Run: func(context.Context) error {
+			// The second job must not be claimed, and its lease must not start, before it runs.
+			secondBeforeRun = repo.row("second")
+			return nil
+		}},
+		Job{Name: "second", Interval: time.Hour, Run: func(context.Context) error { return nil }},
+	)
+
+	if err := sched.tick(context.Background()); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+
+	if secondBeforeRun.Status != models.ScheduledJobPending || secondBeforeRun.LeaseUntil != nil {
+		t.Fatalf("second job was claimed before it started: %+v", secondBeforeRun)
+	}
+	if len(repo.runs) != 2 || repo.runs[0].JobName != "first" || repo.runs[1].JobName != "second" {
+		t.Fatalf("unexpected runs %+v", repo.runs)
+	}
+	for name, slot := range slots {
+		row := repo.row(name)
+		if row.Status != models.ScheduledJobPending || row.LeaseUntil != nil {
+			t.Fatalf("%s: expected a released pending job, got %+v", name, row)
+		}
+		if !row.RunAt.After(slot) || row.RunAt.Sub(slot)%time.Hour != 0 {
+			t.Fatalf("%s: next run %s is off the hourly grid from %s", name, row.RunAt, slot)
+		}
+	}
+}
+
+func TestTickReclaimsExpiredLeaseOnItsGrid(t *testing.T) {
+	t.Parallel()
+
+	slot := time.Now().UTC().Truncate(time.Hour)
+	expired := time.Now().UTC().Add(-time.Second)
+	live := time.Now().UTC().Add(time.Hour)
+	crashed := pendingRow("crashed", slot, time.Hour)
+	crashed.Status = models.ScheduledJobRunning
+	crashed.LeaseUntil = &expired
+	busy := pendingRow("busy", slot, time.Hour)
+	busy.Status = models.ScheduledJobRunning
+	busy.LeaseUntil = &live
+	repo := newJobRepo(crashed, busy)
+
+	ran := map[string]int{}
+	var mu sync.Mutex
+	count := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			ran[name]++
+			return nil
+		}
+	}
+	sched := newTestScheduler(repo,
+		Job{Name: "crashed", Interval: time.Hour, Run: count("crashed")},
+		Job{Name: "busy", Interval: time.Hour, Run: count("busy")},
+	)
+
+	if err := sched.tick(context.Background()); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+
+	if ran["crashed"] != 1 || ran["busy"] != 0 {
+		t.Fatalf("expected only the expired claim to run, got %v", ran)
+	}
+	if got := repo.runs[0].ScheduledFor; got == nil || !got.Equal(slot) {
+		t.Fatalf("scheduled_for = %v, want the grid slot %s", got, slot)
+	}
+	row := repo.row("crashed")
+	if row.Status != models.ScheduledJobPending || row.LeaseUntil != nil || !row.RunAt.Equal(nextRunAt(slot, time.Hour, time.Now().UTC())) {
+		t.Fatalf("reclaimed job should be back on its grid, got %+v", row)
+	}
+	if repo.row("busy").Status != models.ScheduledJobRunning {
+		t.Fatal("job under a live lease must be left alone")
+	}
+}
+
+func TestTickRetriesThenFails(t *testing.T) {
+	t.Parallel()
+
+	slot := time.Now().UTC().Add(-time.Minute)
+	repo := newJobRepo(pendingRow("flaky", slot, time.Hour))
+	sched := newTestScheduler(repo, Job{Name: "flaky", Interval: time.Hour, Run: func(context.Context) error {
+		return errors.New("boom")
+	}})
+
+	if err := sched.tick(context.Background()); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	row := repo.row("flaky")
+	if row.Status != models.ScheduledJobPending || row.Attempts != 1 || !row.RunAt.After(time.Now()) {
+		t.Fatalf("expected a delayed retry after the first failure, got %+v", row)
+	}
+	if row.LastError == nil || *row.LastError != "boom" {
+		t.Fatalf("last_error = %v, want boom", row.LastError)
+	}
+
+	row.RunAt = slot
+	repo.jobs["flaky"] = row
+	if err := sched.tick(context.Background()); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if row = repo.row("flaky"); row.Status != models.ScheduledJobFailed || row.Attempts != 2 {
+		t.Fatalf("expected the job to fail after the attempt budget, got %+v", row)
+	}
+}
+
+func TestFinishKeepsALostClaim(t *testing.T) {
+	t.Parallel()
+
+	slot := time.Now().UTC().Add(-time.Minute)
+	repo := newJobRepo(pendingRow("slow", slot, time.Hour))
+	sched := newTestScheduler(repo, Job{Name: "slow", Interval: time.Hour, Run: func(context.Context) error { return nil }})
+
+	c, ok, err := sched.claimNext(context.Background())
+	if err != nil || !ok {
+		t.Fatalf("claimNext = (%v, %v)", ok, err)
+	}
+
+	// Another instance took the job over after the lease expired.
+	taken := repo.row("slow")
+	otherLease := c.leaseUntil.Add(time.Hour)
+	taken.LeaseUntil = &otherLease
+	repo.jobs["slow"] = taken
+
+	run := sched.execute(context.Background(), c.job, c.row.RunAt, c.trigger)
+	if err = sched.finish(context.Background(), c, run, func(row *models.ScheduledJob) {
+		row.Status = models.ScheduledJobPending
+	}); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+
+	if len(repo.runs) != 1 {
+		t.Fatalf("expected the run to be recorded, got %d runs", len(repo.runs))
+	}
+	if row := repo.row("slow"); row.Status != models.ScheduledJobRunning || !row.LeaseUntil.Equal(otherLease) {
+		t.Fatalf("the other instance's claim was overwritten: %+v", row)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	t.Parallel()
+
+	slot := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+	expired := time.Now().UTC().Add(-time.Second)
+	live := time.Now().UTC().Add(time.Hour)
+	boom := "boom"
+
+	tests := []struct {
+		name       string
+		row        func() models.ScheduledJob
+		interval   time.Duration
+		fail       bool
+		otherLock  bool
+		wantErr    error
+		wantStatus string
+		wantRunAt  time.Time
+	}{
+		{
+			name: "failed recurring job goes back on its grid",
+			row: func() models.ScheduledJob {
+				row := pendingRow("job", slot, time.Hour)
+				row.Status = models.ScheduledJobFailed
+				row.Attempts = 3
+				row.LastError = &boom
+				return row
+			},
+			interval:   time.Hour,
+			wantStatus: models.ScheduledJobPending,
+			wantRunAt:  nextRunAt(slot, time.Hour, time.Now().UTC()),
+		},
+		{
+			name:       "pending recurring job keeps its next slot",
+			row:        func() models.ScheduledJob { return pendingRow("job", live, time.Hour) },
+			interval:   time.Hour,
+			wantStatus: models.ScheduledJobPending,
+			wantRunAt:  live,
+		},
+		{
+			name:       "one-shot job completes",
+			row:        func() models.ScheduledJob { return pendingRow("job", live, 0) },
+			wantStatus: models.ScheduledJobCompleted,
+			wantRunAt:  live,
+		},
+		{
+			name: "failed run leaves a failed job failed",
+			row: func() models.ScheduledJob {
+				row := pendingRow("job", slot, time.Hour)
+				row.Status = models.ScheduledJobFailed
+				return row
+			},
+			interval:   time.Hour,
+			fail:       true,
+			wantStatus: models.ScheduledJobFailed,
+			wantRunAt:  slot,
+		},
+		{
+			name: "expired claim is taken over",
+			row: func() models.ScheduledJob {
+				row := pendingRow("job", slot, time.Hour)
+				row.Status = models.ScheduledJobRunning
+				row.LeaseUntil = &expired
+				return row
+			},
+			interval:   time.Hour,
+			fail:       true,
+			wantStatus: models.ScheduledJobPending,
+			wantRunAt:  slot,
+		},
+		{
+			name: "live claim is busy",
+			row: func() models.ScheduledJob {
+				row := pendingRow("job", slot, time.Hour)
+				row.Status = models.ScheduledJobRunning
+				row.LeaseUntil = &live
+				return row
+			},
+			interval: time.Hour,
+			wantErr:  ErrBusy,
+		},
+		{
+			name:      "scheduler lock held elsewhere",
+			row:       func() models.ScheduledJob { return pendingRow("job", live, time.Hour) },
+			interval:  time.Hour,
+			otherLock: true,
+			wantErr:   ErrBusy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newJobRepo(tt.row())
+			repo.lockedByOther = tt.otherLock
+			sched := newTestScheduler(repo, Job{Name: "job", Interval: tt.interval, Run: func(context.Context) error {
+				if tt.fail {
+					return errors.New("boom")
+				}
+				return nil
+			}})
+
+			run, err := sched.Trigger(context.Background(), "job")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Trigger: %v", err)
+			}
+			if run.Trigger != TriggerAdmin {
+				t.Fatalf("trigger = %q, want %q", run.Trigger, TriggerAdmin)
+			}
+
+			row := repo.row("job")
+			if row.Status != tt.wantStatus || !row.RunAt.Equal(tt.wantRunAt) || row.LeaseUntil != nil {
+				t.Fatalf("got status %q run_at %s lease %v, want %q at %s", row.Status, row.RunAt, row.LeaseUntil, tt.wantStatus, tt.wantRunAt)
+			}
+			if !tt.fail && (row.Attempts != 0 || row.LastError != nil) {
+				t.Fatalf("successful run should clear attempts and last_error, got %+v", row)
+			}
+		})
+	}
+
+	if _, err := newTestScheduler(newJobRepo()).Trigger(context.Background(), "missing"); !errors.Is(err, ErrUnknownJob) {
+		t.Fatalf("unknown job: err = %v, want ErrUnknownJob", err)
+	}
+}
+
+func TestNextRunAtSkipsMissedSlots(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 22, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "not yet due", now: base.Add(-time.Minute), want: base},
+		{name: "exactly due", now: base, want: base.Add(time.Hour)},
+		{name: "one slot late", now: base.Add(90 * time.Minute), want: base.Add(2 * time.Hour)},
+		{name: "long outage", now: base.Add(5*time.Hour + time.Second), want: base.Add(6 * time.Hour)},
+	}
+
+	for _, tc := range cases {
+		got := nextRunAt(base, time.Hour, tc.now)
+		if !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
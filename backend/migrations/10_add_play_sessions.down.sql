@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "public"."play_rejections";
+DROP TABLE IF EXISTS "public"."play_sessions";
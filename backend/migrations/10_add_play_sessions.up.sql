@@ -0,0 +1,40 @@
+CREATE TABLE "public"."play_sessions" (
+    "id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "level" integer NOT NULL,
+    "nonce" text NOT NULL,
+    "sheet_hash" text NOT NULL,
+    "expires_at" timestamp NOT NULL,
+    "consumed_at" timestamp,
+    "created_at" timestamp NOT NULL,
+    CONSTRAINT "pk_play_sessions_id" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_play_sessions_user_id" ON "public"."play_sessions" ("user_id");
+
+ALTER TABLE "public"."play_sessions"
+    ADD CONSTRAINT "fk_play_sessions_user_id_users_id"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users"("id");
+
+-- Hit logs that failed server-side replay are kept for manual review.
+CREATE TABLE "public"."play_rejections" (
+    "id" uuid NOT NULL,
+    "session_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "level" integer NOT NULL,
+    "reason" text NOT NULL,
+    "hit_log" jsonb NOT NULL,
+    "created_at" timestamp NOT NULL,
+    CONSTRAINT "pk_play_rejections_id" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_play_rejections_user_id" ON "public"."play_rejections" ("user_id");
+CREATE INDEX "idx_play_rejections_created_at" ON "public"."play_rejections" ("created_at" DESC);
+
+ALTER TABLE "public"."play_rejections"
+    ADD CONSTRAINT "fk_play_rejections_session_id_play_sessions_id"
+    FOREIGN KEY ("session_id") REFERENCES "public"."play_sessions"("id");
+
+ALTER TABLE "public"."play_rejections"
+    ADD CONSTRAINT "fk_play_rejections_user_id_users_id"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users"("id");
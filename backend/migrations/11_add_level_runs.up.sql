@@ -0,0 +1,30 @@
+-- Every accepted submission is stored as a run so players can compare attempts on the same level.
+-- Runs with misses are stored with cleared false; only cleared runs count as personal bests.
+CREATE TABLE "public"."level_runs" (
+    "id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "session_id" uuid NOT NULL,
+    "level" integer NOT NULL,
+    "score" integer NOT NULL,
+    "accuracy" double precision NOT NULL,
+    "max_combo" integer NOT NULL,
+    "perfect_count" integer NOT NULL,
+    "great_count" integer NOT NULL,
+    "miss_count" integer NOT NULL,
+    "cleared" boolean NOT NULL,
+    "created_at" timestamp NOT NULL,
+    CONSTRAINT "pk_level_runs_id" PRIMARY KEY ("id"),
+    CONSTRAINT "uq_level_runs_session_id" UNIQUE ("session_id")
+);
+
+CREATE INDEX "idx_level_runs_user_id_level_score" ON "public"."level_runs" ("user_id", "level", "score" DESC, "created_at" ASC)
+    WHERE "cleared";
+CREATE INDEX "idx_level_runs_user_id_level_created_at" ON "public"."level_runs" ("user_id", "level", "created_at" DESC);
+
+ALTER TABLE "public"."level_runs"
+    ADD CONSTRAINT "fk_level_runs_user_id_users_id"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users"("id");
+
+ALTER TABLE "public"."level_runs"
+    ADD CONSTRAINT "fk_level_runs_session_id_play_sessions_id"
+    FOREIGN KEY ("session_id") REFERENCES "public"."play_sessions"("id");
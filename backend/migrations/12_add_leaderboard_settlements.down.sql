@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "public"."leaderboard_settlement_entries";
+DROP TABLE IF EXISTS "public"."leaderboard_settlements";
+DROP TABLE IF EXISTS "public"."leaderboard_freeze_entries";
+DROP TABLE IF EXISTS "public"."leaderboard_freezes";
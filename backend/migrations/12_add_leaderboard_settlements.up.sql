@@ -0,0 +1,67 @@
+-- A freeze pins a board's standings; settlement and the public board read the frozen copy.
+CREATE TABLE "public"."leaderboard_freezes" (
+    "id" uuid NOT NULL,
+    "board" text NOT NULL,
+    "frozen_at" timestamp NOT NULL,
+    CONSTRAINT "pk_leaderboard_freezes_id" PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_leaderboard_freezes_board" ON "public"."leaderboard_freezes" ("board");
+
+CREATE TABLE "public"."leaderboard_freeze_entries" (
+    "freeze_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "position" integer NOT NULL,
+    "rank" integer NOT NULL,
+    "score" integer NOT NULL,
+    "current_level" integer NOT NULL,
+    "last_pass_time" timestamp NOT NULL,
+    CONSTRAINT "pk_leaderboard_freeze_entries_freeze_user" PRIMARY KEY ("freeze_id", "user_id")
+);
+
+CREATE INDEX "idx_leaderboard_freeze_entries_freeze_id_position" ON "public"."leaderboard_freeze_entries" ("freeze_id", "position");
+
+ALTER TABLE "public"."leaderboard_freeze_entries"
+    ADD CONSTRAINT "fk_leaderboard_freeze_entries_freeze_id_leaderboard_freezes_id"
+    FOREIGN KEY ("freeze_id") REFERENCES "public"."leaderboard_freezes"("id") ON DELETE CASCADE;
+
+ALTER TABLE "public"."leaderboard_freeze_entries"
+    ADD CONSTRAINT "fk_leaderboard_freeze_entries_user_id_users_id"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users"("id");
+
+-- Every settlement run is kept, including re-settles, so we can tell who got what and when.
+CREATE TABLE "public"."leaderboard_settlements" (
+    "id" uuid NOT NULL,
+    "board" text NOT NULL,
+    "discount_id" text NOT NULL,
+    "amount" integer NOT NULL,
+    "rank_limit" integer NOT NULL,
+    "tie_policy" text NOT NULL,
+    "frozen_at" timestamp,
+    "trigger" text NOT NULL,
+    "eligible_count" integer NOT NULL,
+    "issued_count" integer NOT NULL,
+    "settled_at" timestamp NOT NULL,
+    CONSTRAINT "pk_leaderboard_settlements_id" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_leaderboard_settlements_settled_at" ON "public"."leaderboard_settlements" ("settled_at" DESC);
+
+CREATE TABLE "public"."leaderboard_settlement_entries" (
+    "settlement_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "rank" integer NOT NULL,
+    "score" integer NOT NULL,
+    "tie_size" integer NOT NULL,
+    "outcome" text NOT NULL,
+    "coupon_id" uuid,
+    CONSTRAINT "pk_leaderboard_settlement_entries_settlement_user" PRIMARY KEY ("settlement_id", "user_id")
+);
+
+ALTER TABLE "public"."leaderboard_settlement_entries"
+    ADD CONSTRAINT "fk_leaderboard_settlement_entries_settlement_id_leaderboard_settlements_id"
+    FOREIGN KEY ("settlement_id") REFERENCES "public"."leaderboard_settlements"("id") ON DELETE CASCADE;
+
+ALTER TABLE "public"."leaderboard_settlement_entries"
+    ADD CONSTRAINT "fk_leaderboard_settlement_entries_user_id_users_id"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users"("id");
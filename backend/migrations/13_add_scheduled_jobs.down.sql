@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "public"."scheduled_job_runs";
+DROP TABLE IF EXISTS "public"."scheduled_jobs";
@@ -0,0 +1,36 @@
+-- Background jobs survive restarts: a job whose run_at passed while no instance was up runs on next start.
+-- run_at is always the scheduled time; lease_until is set while an instance runs the job, and a running
+-- job whose lease passed is picked up again.
+CREATE TABLE "public"."scheduled_jobs" (
+    "name" text NOT NULL,
+    "status" text NOT NULL,
+    "run_at" timestamp NOT NULL,
+    "lease_until" timestamp,
+    "interval_seconds" integer,
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_run_at" timestamp,
+    "last_error" text,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL,
+    CONSTRAINT "pk_scheduled_jobs_name" PRIMARY KEY ("name")
+);
+
+CREATE INDEX "idx_scheduled_jobs_status_run_at" ON "public"."scheduled_jobs" ("status", "run_at");
+
+CREATE TABLE "public"."scheduled_job_runs" (
+    "id" uuid NOT NULL,
+    "job_name" text NOT NULL,
+    "trigger" text NOT NULL,
+    "scheduled_for" timestamp,
+    "started_at" timestamp NOT NULL,
+    "finished_at" timestamp NOT NULL,
+    "status" text NOT NULL,
+    "error" text,
+    CONSTRAINT "pk_scheduled_job_runs_id" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_scheduled_job_runs_job_name_started_at" ON "public"."scheduled_job_runs" ("job_name", "started_at" DESC);
+
+ALTER TABLE "public"."scheduled_job_runs"
+    ADD CONSTRAINT "fk_scheduled_job_runs_job_name_scheduled_jobs_name"
+    FOREIGN KEY ("job_name") REFERENCES "public"."scheduled_jobs"("name") ON DELETE CASCADE;
@@ -0,0 +1,41 @@
+-- Coupon rules move out of the Go rule table so amounts, thresholds and stock can change without a deploy.
+-- "id" is the discount_id written to discount_coupons.
+CREATE TABLE "public"."coupon_rules" (
+    "id" text NOT NULL,
+    "trigger" text NOT NULL,
+    "threshold" integer NOT NULL DEFAULT 0,
+    "activity_types" text[],
+    "activity_id" uuid,
+    "amount" integer NOT NULL,
+    "stock_limit" integer,
+    "valid_from" timestamp,
+    "valid_until" timestamp,
+    "description" text NOT NULL,
+    "sort_order" integer NOT NULL DEFAULT 0,
+    "enabled" boolean NOT NULL DEFAULT true,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL,
+    CONSTRAINT "pk_coupon_rules_id" PRIMARY KEY ("id"),
+    CONSTRAINT "chk_coupon_rules_trigger" CHECK (
+        "trigger" IN ('level', 'activity_visits', 'activity', 'friends', 'leaderboard_rank', 'group_check_ins', 'manual')
+    ),
+    CONSTRAINT "chk_coupon_rules_amount" CHECK ("amount" > 0),
+    CONSTRAINT "chk_coupon_rules_stock_limit" CHECK ("stock_limit" IS NULL OR "stock_limit" >= 0)
+);
+
+ALTER TABLE "public"."coupon_rules"
+    ADD CONSTRAINT "fk_coupon_rules_activity_id_activities_id"
+    FOREIGN KEY ("activity_id") REFERENCES "public"."activities"("id") ON DELETE SET NULL;
+
+-- Seed the rules that used to be hard-coded. The tour-group rule is bound to the activity by id;
+-- on a fresh deploy the activity is not imported yet, so cmd/import fills activity_id later.
+INSERT INTO "public"."coupon_rules"
+    ("id", "trigger", "threshold", "activity_types", "activity_id", "amount", "description", "sort_order", "created_at", "updated_at")
+VALUES
+    ('check-in-all-booth-and-check', 'activity_visits', 23, ARRAY['booth', 'check'], NULL, 25, '解鎖 23 個攤位和打卡點', 1, NOW(), NOW()),
+    ('tour-group-challenge', 'activity', 0, NULL,
+        (SELECT "id" FROM "public"."activities" WHERE "type" = 'challenge' AND "name" = '導遊團' ORDER BY "created_at" LIMIT 1),
+        35, '參加導遊團', 2, NOW(), NOW()),
+    ('level-50', 'level', 50, NULL, NULL, 25, '完成 50 關', 3, NOW(), NOW()),
+    ('sitcon-sns-coupon', 'manual', 0, NULL, NULL, 35, '限時動態或貼文分享（點擊可見詳情）', 4, NOW(), NOW()),
+    ('leaderboard-top-10', 'leaderboard_rank', 10, NULL, NULL, 50, '排行榜前 10 名（16:00 結算）', 5, NOW(), NOW());
@@ -0,0 +1,5 @@
+ALTER TABLE "public"."coupon_rules"
+    DROP CONSTRAINT IF EXISTS "chk_coupon_rules_budget",
+    DROP COLUMN IF EXISTS "issued_amount",
+    DROP COLUMN IF EXISTS "issued_count",
+    DROP COLUMN IF EXISTS "budget";
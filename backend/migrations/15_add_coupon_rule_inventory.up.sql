@@ -0,0 +1,23 @@
+-- Sponsors fund each rule with a fixed number of coupons and/or a fixed NT$ budget.
+-- issued_count / issued_amount are reserved atomically when a coupon or gift is created,
+-- so concurrent issuance can never overshoot stock_limit or budget.
+ALTER TABLE "public"."coupon_rules"
+    ADD COLUMN "budget" integer,
+    ADD COLUMN "issued_count" integer NOT NULL DEFAULT 0,
+    ADD COLUMN "issued_amount" integer NOT NULL DEFAULT 0,
+    ADD CONSTRAINT "chk_coupon_rules_budget" CHECK ("budget" IS NULL OR "budget" >= 0);
+
+-- Unredeemed gifts already hold their value, so they count against the rule too.
+UPDATE "public"."coupon_rules" cr
+SET "issued_count" = totals."issued_count",
+    "issued_amount" = totals."issued_amount"
+FROM (
+    SELECT "discount_id", COUNT(*)::int AS "issued_count", COALESCE(SUM("price"), 0)::int AS "issued_amount"
+    FROM (
+        SELECT "discount_id", "price" FROM "public"."discount_coupons"
+        UNION ALL
+        SELECT "discount_id", "price" FROM "public"."discount_coupon_gift"
+    ) issued
+    GROUP BY "discount_id"
+) totals
+WHERE totals."discount_id" = cr."id";
@@ -0,0 +1,24 @@
+-- A void reverses one redemption: its coupons go back to unused and the original
+-- coupon_histories row is kept, linked here, so reports can net it out.
+CREATE TABLE "public"."coupon_history_voids" (
+    "id" uuid NOT NULL,
+    "history_id" uuid NOT NULL,
+    "actor" text NOT NULL,
+    "staff_id" uuid,
+    "reason" text NOT NULL,
+    "total" integer NOT NULL,
+    "coupon_ids" uuid[] NOT NULL,
+    "voided_at" timestamp NOT NULL,
+    CONSTRAINT "pk_coupon_history_voids_id" PRIMARY KEY ("id"),
+    CONSTRAINT "chk_coupon_history_voids_actor" CHECK ("actor" IN ('staff', 'admin')),
+    CONSTRAINT "chk_coupon_history_voids_staff" CHECK ("actor" <> 'staff' OR "staff_id" IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX "idx_coupon_history_voids_history_id" ON "public"."coupon_history_voids" ("history_id");
+
+ALTER TABLE "public"."coupon_history_voids"
+    ADD CONSTRAINT "fk_coupon_history_voids_history_id_coupon_histories_id"
+    FOREIGN KEY ("history_id") REFERENCES "public"."coupon_histories"("id");
+ALTER TABLE "public"."coupon_history_voids"
+    ADD CONSTRAINT "fk_coupon_history_voids_staff_id_staffs_id"
+    FOREIGN KEY ("staff_id") REFERENCES "public"."staffs"("id");
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "public"."qr_token_nonces";
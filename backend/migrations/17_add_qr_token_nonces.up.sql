@@ -0,0 +1,16 @@
+-- Nonces of consumed one-time user QR tokens (qru2). A token is accepted once per purpose;
+-- rows can be deleted after expires_at since the token no longer verifies by then.
+CREATE TABLE "public"."qr_token_nonces" (
+    "user_id" uuid NOT NULL,
+    "purpose" text NOT NULL,
+    "nonce" text NOT NULL,
+    "used_at" timestamp NOT NULL,
+    "expires_at" timestamp NOT NULL,
+    CONSTRAINT "pk_qr_token_nonces" PRIMARY KEY ("user_id", "purpose", "nonce")
+);
+
+CREATE INDEX "idx_qr_token_nonces_expires_at" ON "public"."qr_token_nonces" ("expires_at");
+
+ALTER TABLE "public"."qr_token_nonces"
+    ADD CONSTRAINT "fk_qr_token_nonces_user_id_users_id"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users"("id") ON DELETE CASCADE;
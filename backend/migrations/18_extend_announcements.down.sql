@@ -0,0 +1,14 @@
+ALTER TABLE "public"."announcements"
+    DROP CONSTRAINT IF EXISTS "chk_announcements_window",
+    DROP CONSTRAINT IF EXISTS "chk_announcements_audience",
+    DROP CONSTRAINT IF EXISTS "chk_announcements_severity",
+    DROP COLUMN IF EXISTS "updated_at",
+    DROP COLUMN IF EXISTS "expire_at",
+    DROP COLUMN IF EXISTS "publish_at",
+    DROP COLUMN IF EXISTS "audience_min_level",
+    DROP COLUMN IF EXISTS "audience_group",
+    DROP COLUMN IF EXISTS "audience",
+    DROP COLUMN IF EXISTS "pinned",
+    DROP COLUMN IF EXISTS "severity",
+    DROP COLUMN IF EXISTS "localizations",
+    DROP COLUMN IF EXISTS "title";
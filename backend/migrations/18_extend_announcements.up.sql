@@ -0,0 +1,28 @@
+-- Announcements gain a title, per-locale variants, a publish/expire window, pinning, a severity
+-- and an audience: everyone, one group, or users whose current_level is at least audience_min_level.
+-- "content" stays the default-locale body; "localizations" maps a locale to {"title", "content"}.
+ALTER TABLE "public"."announcements"
+    ADD COLUMN "title" text NOT NULL DEFAULT '',
+    ADD COLUMN "localizations" jsonb NOT NULL DEFAULT '{}'::jsonb,
+    ADD COLUMN "severity" text NOT NULL DEFAULT 'info',
+    ADD COLUMN "pinned" boolean NOT NULL DEFAULT false,
+    ADD COLUMN "audience" text NOT NULL DEFAULT 'all',
+    ADD COLUMN "audience_group" text,
+    ADD COLUMN "audience_min_level" integer,
+    ADD COLUMN "publish_at" timestamp,
+    ADD COLUMN "expire_at" timestamp,
+    ADD COLUMN "updated_at" timestamp,
+    ADD CONSTRAINT "chk_announcements_severity" CHECK ("severity" IN ('info', 'warning', 'critical')),
+    ADD CONSTRAINT "chk_announcements_audience" CHECK (
+        ("audience" = 'all')
+        OR ("audience" = 'group' AND "audience_group" IS NOT NULL)
+        OR ("audience" = 'min_level' AND "audience_min_level" IS NOT NULL)
+    ),
+    ADD CONSTRAINT "chk_announcements_window" CHECK (
+        "publish_at" IS NULL OR "expire_at" IS NULL OR "expire_at" > "publish_at"
+    );
+
+UPDATE "public"."announcements" SET "updated_at" = "created_at";
+
+ALTER TABLE "public"."announcements"
+    ALTER COLUMN "updated_at" SET NOT NULL;
@@ -0,0 +1,20 @@
+-- Per-user inbox: coupons received, friends added, group check-ins. Rows are kept for the event
+-- duration (NOTIFICATION_RETENTION) and then deleted by the notification_cleanup job.
+CREATE TABLE "public"."notifications" (
+    "id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "kind" text NOT NULL,
+    "data" jsonb NOT NULL DEFAULT '{}',
+    "read_at" timestamp,
+    "created_at" timestamp NOT NULL,
+    CONSTRAINT "pk_notifications_id" PRIMARY KEY ("id"),
+    CONSTRAINT "chk_notifications_kind" CHECK ("kind" IN ('coupon_issued', 'friend_added', 'group_check_in'))
+);
+
+CREATE INDEX "idx_notifications_user_id_created_at" ON "public"."notifications" ("user_id", "created_at" DESC);
+CREATE INDEX "idx_notifications_user_id_unread" ON "public"."notifications" ("user_id") WHERE "read_at" IS NULL;
+CREATE INDEX "idx_notifications_created_at" ON "public"."notifications" ("created_at");
+
+ALTER TABLE "public"."notifications"
+    ADD CONSTRAINT "fk_notifications_user_id_users_id"
+    FOREIGN KEY ("user_id") REFERENCES "public"."users"("id") ON DELETE CASCADE;
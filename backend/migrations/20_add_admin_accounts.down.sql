@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "public"."admin_sessions";
+DROP TABLE IF EXISTS "public"."admins";
@@ -0,0 +1,30 @@
+-- Named admin accounts replace the shared ADMIN_KEY. Each admin logs in with a personal login key;
+-- only its SHA-256 is stored. Sessions are stored the same way and expire at expires_at.
+CREATE TABLE "public"."admins" (
+    "id" uuid NOT NULL,
+    "username" text NOT NULL,
+    "role" text NOT NULL,
+    "key_hash" text NOT NULL,
+    "disabled" boolean NOT NULL DEFAULT false,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL,
+    CONSTRAINT "pk_admins_id" PRIMARY KEY ("id"),
+    CONSTRAINT "uq_admins_username" UNIQUE ("username"),
+    CONSTRAINT "uq_admins_key_hash" UNIQUE ("key_hash"),
+    CONSTRAINT "chk_admins_role" CHECK ("role" IN ('superadmin', 'coupon-manager', 'viewer', 'support'))
+);
+
+CREATE TABLE "public"."admin_sessions" (
+    "token_hash" text NOT NULL,
+    "admin_id" uuid NOT NULL,
+    "created_at" timestamp NOT NULL,
+    "expires_at" timestamp NOT NULL,
+    CONSTRAINT "pk_admin_sessions_token_hash" PRIMARY KEY ("token_hash")
+);
+
+CREATE INDEX "idx_admin_sessions_admin_id" ON "public"."admin_sessions" ("admin_id");
+CREATE INDEX "idx_admin_sessions_expires_at" ON "public"."admin_sessions" ("expires_at");
+
+ALTER TABLE "public"."admin_sessions"
+    ADD CONSTRAINT "fk_admin_sessions_admin_id_admins_id"
+    FOREIGN KEY ("admin_id") REFERENCES "public"."admins"("id") ON DELETE CASCADE;
@@ -0,0 +1,33 @@
+-- Append-only record of who changed what through admin, staff and booth endpoints.
+-- before/after hold the target's JSON with secrets (tokens, login keys) removed.
+CREATE TABLE "public"."audit_events" (
+    "id" uuid NOT NULL,
+    "actor_type" text NOT NULL,
+    "actor_id" text NOT NULL,
+    "actor_name" text NOT NULL,
+    "action" text NOT NULL,
+    "target_type" text NOT NULL,
+    "target_id" text NOT NULL,
+    "before" jsonb,
+    "after" jsonb,
+    "request_id" text NOT NULL DEFAULT '',
+    "trace_id" text NOT NULL DEFAULT '',
+    "created_at" timestamp NOT NULL,
+    CONSTRAINT "pk_audit_events_id" PRIMARY KEY ("id"),
+    CONSTRAINT "chk_audit_events_actor_type" CHECK ("actor_type" IN ('admin', 'staff', 'booth'))
+);
+
+CREATE INDEX "idx_audit_events_created_at" ON "public"."audit_events" ("created_at" DESC, "id" DESC);
+CREATE INDEX "idx_audit_events_actor" ON "public"."audit_events" ("actor_type", "actor_id", "created_at" DESC);
+CREATE INDEX "idx_audit_events_action" ON "public"."audit_events" ("action", "created_at" DESC);
+CREATE INDEX "idx_audit_events_target" ON "public"."audit_events" ("target_type", "target_id", "created_at" DESC);
+
+CREATE FUNCTION "public"."reject_audit_event_change"() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "trg_audit_events_append_only"
+    BEFORE UPDATE OR DELETE ON "public"."audit_events"
+    FOR EACH ROW EXECUTE FUNCTION "public"."reject_audit_event_change"();
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "public"."booth_sessions";
+DROP TABLE IF EXISTS "public"."staff_sessions";
@@ -0,0 +1,40 @@
+-- Staff and booth logins open a session instead of putting the long-lived staffs.token /
+-- activities.token in the cookie. Only the SHA-256 of the session token is stored; id is the
+-- public handle admins use to list and revoke sessions.
+CREATE TABLE "public"."staff_sessions" (
+    "id" uuid NOT NULL,
+    "token_hash" text NOT NULL,
+    "staff_id" uuid NOT NULL,
+    "device_label" text NOT NULL DEFAULT '',
+    "created_at" timestamp NOT NULL,
+    "last_seen_at" timestamp NOT NULL,
+    "expires_at" timestamp NOT NULL,
+    CONSTRAINT "pk_staff_sessions_id" PRIMARY KEY ("id"),
+    CONSTRAINT "uq_staff_sessions_token_hash" UNIQUE ("token_hash")
+);
+
+CREATE INDEX "idx_staff_sessions_staff_id" ON "public"."staff_sessions" ("staff_id");
+CREATE INDEX "idx_staff_sessions_expires_at" ON "public"."staff_sessions" ("expires_at");
+
+ALTER TABLE "public"."staff_sessions"
+    ADD CONSTRAINT "fk_staff_sessions_staff_id_staffs_id"
+    FOREIGN KEY ("staff_id") REFERENCES "public"."staffs"("id") ON DELETE CASCADE;
+
+CREATE TABLE "public"."booth_sessions" (
+    "id" uuid NOT NULL,
+    "token_hash" text NOT NULL,
+    "activity_id" uuid NOT NULL,
+    "device_label" text NOT NULL DEFAULT '',
+    "created_at" timestamp NOT NULL,
+    "last_seen_at" timestamp NOT NULL,
+    "expires_at" timestamp NOT NULL,
+    CONSTRAINT "pk_booth_sessions_id" PRIMARY KEY ("id"),
+    CONSTRAINT "uq_booth_sessions_token_hash" UNIQUE ("token_hash")
+);
+
+CREATE INDEX "idx_booth_sessions_activity_id" ON "public"."booth_sessions" ("activity_id");
+CREATE INDEX "idx_booth_sessions_expires_at" ON "public"."booth_sessions" ("expires_at");
+
+ALTER TABLE "public"."booth_sessions"
+    ADD CONSTRAINT "fk_booth_sessions_activity_id_activities_id"
+    FOREIGN KEY ("activity_id") REFERENCES "public"."activities"("id") ON DELETE CASCADE;
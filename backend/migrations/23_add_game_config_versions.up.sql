@@ -0,0 +1,17 @@
+-- Every imported level / sheet-music config is kept as a numbered version with its raw CSV, so all
+-- instances load the same bytes and admins can roll back. The served version is the one activated last.
+CREATE TABLE "public"."game_config_versions" (
+    "version" integer NOT NULL,
+    "source" text NOT NULL,
+    "levels_csv" text NOT NULL,
+    "sheet_csv" text NOT NULL,
+    "created_by" text NOT NULL,
+    "created_at" timestamp NOT NULL,
+    "activated_at" timestamp,
+    CONSTRAINT "pk_game_config_versions_version" PRIMARY KEY ("version"),
+    CONSTRAINT "chk_game_config_versions_source" CHECK ("source" IN ('url', 'file', 'upload'))
+);
+
+CREATE INDEX "idx_game_config_versions_activated_at"
+    ON "public"."game_config_versions" ("activated_at" DESC)
+    WHERE "activated_at" IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Only the default track survives a rollback; versions without one get an empty sheet.
+ALTER TABLE "public"."game_config_versions" ADD COLUMN "sheet_csv" text NOT NULL DEFAULT '';
+
+UPDATE "public"."game_config_versions" v
+SET "sheet_csv" = t."sheet_csv"
+FROM "public"."game_config_tracks" t
+WHERE t."version" = v."version" AND t."track_id" = 'default';
+
+ALTER TABLE "public"."game_config_versions" ALTER COLUMN "sheet_csv" DROP DEFAULT;
+
+DROP TABLE IF EXISTS "public"."game_config_tracks";
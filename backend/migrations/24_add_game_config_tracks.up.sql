@@ -0,0 +1,22 @@
+-- A game config version now holds several named sheet-music tracks, each with its own tempo and
+-- time signature; level ranges pick a track by id. Existing versions keep their single sheet as
+-- the "default" track.
+CREATE TABLE "public"."game_config_tracks" (
+    "version" integer NOT NULL,
+    "track_id" text NOT NULL,
+    "tempo" integer NOT NULL,
+    "time_signature" text NOT NULL,
+    "sheet_csv" text NOT NULL,
+    CONSTRAINT "pk_game_config_tracks_version_track_id" PRIMARY KEY ("version", "track_id"),
+    CONSTRAINT "chk_game_config_tracks_tempo" CHECK ("tempo" > 0)
+);
+
+ALTER TABLE "public"."game_config_tracks"
+    ADD CONSTRAINT "fk_game_config_tracks_version_game_config_versions_version"
+    FOREIGN KEY ("version") REFERENCES "public"."game_config_versions"("version") ON DELETE CASCADE;
+
+INSERT INTO "public"."game_config_tracks" ("version", "track_id", "tempo", "time_signature", "sheet_csv")
+SELECT "version", 'default', 120, '4/4', "sheet_csv"
+FROM "public"."game_config_versions";
+
+ALTER TABLE "public"."game_config_versions" DROP COLUMN "sheet_csv";
@@ -1,6 +1,10 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"sync"
 	"time"
 
@@ -17,6 +21,14 @@ const (
 	AppEnvDev            AppEnv = "dev"
 	AppEnvProd           AppEnv = "prod"
 	defaultLokiBatchWait        = 2 * time.Second
+
+	defaultLeaderboardRefreshInterval = 2 * time.Second
+	defaultSchedulerPollInterval      = 15 * time.Second
+	defaultBoothOfflineMaxBatch       = 500
+	defaultAdminSessionTTL            = 12 * time.Hour
+	defaultScannerSessionTTL          = 24 * time.Hour
+	devBoothOfflineSecret             = "dev-booth-offline-secret"
+	devUserQRSigningSeed              = "dev-user-qr-signing-key"
 )
 
 // EnvConfig holds all environment variables for the application.
@@ -28,7 +40,11 @@ type EnvConfig struct {
 	AppAutoMigrate bool   `env:"APP_AUTO_MIGRATE" envDefault:"false"`
 	AppDocs        bool   `env:"APP_DOCS" envDefault:"false"`
 
-	CouponStopTime string `env:"COUPON_STOP_TIME"`
+	CouponStopTime                 string `env:"COUPON_STOP_TIME"`
+	CouponStaticTokenUntil         string `env:"COUPON_STATIC_TOKEN_UNTIL"`
+	LegacyUserQRUntil              string `env:"LEGACY_USER_QR_UNTIL"`
+	LeaderboardSettlementBoard     string `env:"LEADERBOARD_SETTLEMENT_BOARD" envDefault:"overall"`
+	LeaderboardSettlementTiePolicy string `env:"LEADERBOARD_SETTLEMENT_TIE_POLICY" envDefault:"include"`
 
 	// OpenTelemetry settings
 	OTelEnabled    bool    `env:"OTEL_ENABLED" envDefault:"false"`
@@ -46,9 +62,20 @@ type EnvConfig struct {
 	LokiBatchSize int           `env:"LOKI_BATCH_SIZE" envDefault:"500"`
 	LokiBatchWait time.Duration `env:"LOKI_BATCH_WAIT" envDefault:"2s"`
 
-	// Gameplay data URLs
+	// Gameplay data URLs (http, https or file://). The first instance to start imports them as game config version 1;
+	// later changes are imported through /admin/game-config/versions.
 	LevelCSVURL      string `env:"LEVEL_CSV_URL"`
 	SheetMusicCSVURL string `env:"SHEET_MUSIC_CSV_URL"`
+	// Optional "track,tempo,time signature,sheet" manifest of extra named tracks; SHEET_MUSIC_CSV_URL
+	// is the "default" track and may be left empty when the manifest defines one.
+	SheetTracksCSVURL string `env:"SHEET_TRACKS_CSV_URL"`
+	// Local copies that admins can import with source "file".
+	LevelCSVFile       string `env:"LEVEL_CSV_FILE"`
+	SheetMusicCSVFile  string `env:"SHEET_MUSIC_CSV_FILE"`
+	SheetTracksCSVFile string `env:"SHEET_TRACKS_CSV_FILE"`
+	// Last-known-good copy of the served config, used at startup when the database has no usable
+	// version and the URLs are unreachable. Empty disables the cache.
+	GameConfigCacheFile string `env:"GAME_CONFIG_CACHE_FILE"`
 
 	// CORS
 	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" envSeparator:"," envDefault:"http://localhost:3000"`
@@ -63,16 +90,52 @@ type EnvConfig struct {
 
 	// Other
 	OPassURL string `env:"OPASS_URL" envDefault:"https://ccip.opass.app/"`
-	AdminKey string `env:"ADMIN_KEY" envDefault:"dev-admin-key"`
+
+	// Admin accounts. The bootstrap superadmin is created at startup only while no admin account exists.
+	AdminBootstrapUsername string        `env:"ADMIN_BOOTSTRAP_USERNAME" envDefault:"admin"`
+	AdminBootstrapKey      string        `env:"ADMIN_BOOTSTRAP_KEY"`
+	AdminSessionTTL        time.Duration `env:"ADMIN_SESSION_TTL" envDefault:"12h"`
+	// Deprecated: the shared key from before admin accounts. It is used as ADMIN_BOOTSTRAP_KEY
+	// when that is unset, so existing deployments keep a way in.
+	AdminKey string `env:"ADMIN_KEY"`
+
+	// Staff and booth login sessions; staff and booths log in again with their token after expiry.
+	StaffSessionTTL time.Duration `env:"STAFF_SESSION_TTL" envDefault:"24h"`
+	BoothSessionTTL time.Duration `env:"BOOTH_SESSION_TTL" envDefault:"24h"`
 
 	// Gameplay tuning
-	FriendCapacityMultiplier int `env:"FRIEND_CAPACITY_MULTIPLIER" envDefault:"3"`
+	FriendCapacityMultiplier int           `env:"FRIEND_CAPACITY_MULTIPLIER" envDefault:"3"`
+	GameSessionTTL           time.Duration `env:"GAME_SESSION_TTL" envDefault:"10m"`
+
+	// Leaderboard cache
+	LeaderboardRefreshInterval time.Duration `env:"LEADERBOARD_REFRESH_INTERVAL" envDefault:"2s"`
+	LeaderboardMaxAge          time.Duration `env:"LEADERBOARD_MAX_AGE" envDefault:"30s"`
+
+	// Scheduler
+	SchedulerPollInterval time.Duration `env:"SCHEDULER_POLL_INTERVAL" envDefault:"15s"`
+	SchedulerMaxAttempts  int           `env:"SCHEDULER_MAX_ATTEMPTS" envDefault:"3"`
+	SchedulerRetryDelay   time.Duration `env:"SCHEDULER_RETRY_DELAY" envDefault:"1m"`
+
+	// Offline booth scanning. BOOTH_OFFLINE_SECRET is required outside dev.
+	BoothOfflineSecret      string        `env:"BOOTH_OFFLINE_SECRET"`
+	BoothOfflineBundleTTL   time.Duration `env:"BOOTH_OFFLINE_BUNDLE_TTL" envDefault:"12h"`
+	BoothOfflineMaxLateness time.Duration `env:"BOOTH_OFFLINE_MAX_LATENESS" envDefault:"2h"`
+	BoothOfflineMaxBatch    int           `env:"BOOTH_OFFLINE_MAX_BATCH" envDefault:"500"`
+
+	// Ed25519 seed (32 bytes, base64) of the key that signs qru2 user QR tokens; required outside dev.
+	UserQRSigningSeed string `env:"USER_QR_SIGNING_SEED"`
+
+	// Notification inbox; keep this at least as long as the event runs.
+	NotificationRetention time.Duration `env:"NOTIFICATION_RETENTION" envDefault:"72h"`
 
 	// Rate limiting
 	RateLimitRequestsPerWindow int           `env:"RATE_LIMIT_REQUESTS_PER_WINDOW" envDefault:"20"`
 	RateLimitWindow            time.Duration `env:"RATE_LIMIT_WINDOW" envDefault:"5s"`
 
-	couponStopAt time.Time `env:"-"`
+	couponStopAt           time.Time          `env:"-"`
+	couponStaticTokenUntil time.Time          `env:"-"`
+	legacyUserQRUntil      time.Time          `env:"-"`
+	userQRSigningKey       ed25519.PrivateKey `env:"-"`
 }
 
 var (
@@ -93,6 +156,31 @@ func load() (*EnvConfig, error) {
 		}
 		cfg.couponStopAt = couponStopAt
 	}
+	if cfg.CouponStaticTokenUntil == "" && cfg.AppEnv != AppEnvDev {
+		return nil, fmt.Errorf("COUPON_STATIC_TOKEN_UNTIL is required when APP_ENV is %q", cfg.AppEnv)
+	}
+	if cfg.CouponStaticTokenUntil != "" {
+		staticUntil, err := time.Parse(time.RFC3339, cfg.CouponStaticTokenUntil)
+		if err != nil {
+			return nil, err
+		}
+		cfg.couponStaticTokenUntil = staticUntil
+	}
+	if cfg.LegacyUserQRUntil == "" && cfg.AppEnv != AppEnvDev {
+		return nil, fmt.Errorf("LEGACY_USER_QR_UNTIL is required when APP_ENV is %q", cfg.AppEnv)
+	}
+	if cfg.LegacyUserQRUntil != "" {
+		legacyUntil, err := time.Parse(time.RFC3339, cfg.LegacyUserQRUntil)
+		if err != nil {
+			return nil, err
+		}
+		cfg.legacyUserQRUntil = legacyUntil
+	}
+	switch cfg.LeaderboardSettlementTiePolicy {
+	case "include", "exclude":
+	default:
+		return nil, fmt.Errorf("invalid LEADERBOARD_SETTLEMENT_TIE_POLICY %q", cfg.LeaderboardSettlementTiePolicy)
+	}
 	if cfg.LokiEnv == "" {
 		cfg.LokiEnv = string(cfg.AppEnv)
 	}
@@ -102,9 +190,61 @@ func load() (*EnvConfig, error) {
 	if cfg.LokiBatchWait <= 0 {
 		cfg.LokiBatchWait = defaultLokiBatchWait
 	}
+	if cfg.LeaderboardRefreshInterval <= 0 {
+		cfg.LeaderboardRefreshInterval = defaultLeaderboardRefreshInterval
+	}
+	if cfg.SchedulerPollInterval <= 0 {
+		cfg.SchedulerPollInterval = defaultSchedulerPollInterval
+	}
+	if cfg.SchedulerMaxAttempts <= 0 {
+		cfg.SchedulerMaxAttempts = 1
+	}
+	if cfg.AdminBootstrapKey == "" {
+		cfg.AdminBootstrapKey = cfg.AdminKey
+	}
+	if cfg.BoothOfflineSecret == "" {
+		if cfg.AppEnv != AppEnvDev {
+			return nil, fmt.Errorf("BOOTH_OFFLINE_SECRET is required when APP_ENV is %q", cfg.AppEnv)
+		}
+		cfg.BoothOfflineSecret = devBoothOfflineSecret
+	}
+	if cfg.UserQRSigningSeed == "" && cfg.AppEnv != AppEnvDev {
+		return nil, fmt.Errorf("USER_QR_SIGNING_SEED is required when APP_ENV is %q", cfg.AppEnv)
+	}
+	signingKey, err := parseUserQRSigningKey(cfg.UserQRSigningSeed)
+	if err != nil {
+		return nil, err
+	}
+	cfg.userQRSigningKey = signingKey
+	if cfg.BoothOfflineMaxBatch <= 0 {
+		cfg.BoothOfflineMaxBatch = defaultBoothOfflineMaxBatch
+	}
+	if cfg.AdminSessionTTL <= 0 {
+		cfg.AdminSessionTTL = defaultAdminSessionTTL
+	}
+	if cfg.StaffSessionTTL <= 0 {
+		cfg.StaffSessionTTL = defaultScannerSessionTTL
+	}
+	if cfg.BoothSessionTTL <= 0 {
+		cfg.BoothSessionTTL = defaultScannerSessionTTL
+	}
 	return cfg, nil
 }
 
+// parseUserQRSigningKey derives the signing key from USER_QR_SIGNING_SEED. An empty seed, only
+// allowed in dev, gives a fixed development key.
+func parseUserQRSigningKey(raw string) (ed25519.PrivateKey, error) {
+	if raw == "" {
+		seed := sha256.Sum256([]byte(devUserQRSigningSeed))
+		return ed25519.NewKeyFromSeed(seed[:]), nil
+	}
+	seed, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("USER_QR_SIGNING_SEED must be %d base64-encoded bytes", ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
 // Init initializes the config only once.
 func Init() (*EnvConfig, error) {
 	var err error
@@ -122,6 +262,17 @@ func Env() *EnvConfig {
 	return appConfig
 }
 
+// UserQRSigningKey returns the private key that signs qru2 user QR tokens.
+func (c *EnvConfig) UserQRSigningKey() ed25519.PrivateKey {
+	return c.userQRSigningKey
+}
+
+// UserQRPublicKey returns the key that verifies qru2 user QR tokens. It is safe to hand to booths.
+func (c *EnvConfig) UserQRPublicKey() ed25519.PublicKey {
+	public, _ := c.userQRSigningKey.Public().(ed25519.PublicKey)
+	return public
+}
+
 // CouponStopAt returns the configured coupon stop time.
 func (c *EnvConfig) CouponStopAt() (time.Time, bool) {
 	if c == nil || c.couponStopAt.IsZero() {
@@ -140,3 +291,27 @@ func IsCouponEarningStopped(now time.Time) bool {
 
 	return !now.Before(stopAt)
 }
+
+// IsStaticCouponTokenAccepted reports whether the legacy static users.coupon_token is still
+// accepted for redemption. It is accepted until COUPON_STATIC_TOKEN_UNTIL, which is required
+// outside dev; in dev it may be left unset to keep accepting the static token.
+func IsStaticCouponTokenAccepted(now time.Time) bool {
+	until := Env().couponStaticTokenUntil
+	if until.IsZero() {
+		return true
+	}
+
+	return now.Before(until)
+}
+
+// IsLegacyUserQRAccepted reports whether purpose-less qru1 user QR tokens are still issued and
+// accepted. They are accepted until LEGACY_USER_QR_UNTIL, which is required outside dev; in dev
+// it may be left unset to keep accepting them.
+func IsLegacyUserQRAccepted(now time.Time) bool {
+	until := Env().legacyUserQRUntil
+	if until.IsZero() {
+		return true
+	}
+
+	return now.Before(until)
+}
@@ -2,106 +2,242 @@ package config
 
 import (
 	"errors"
-	"sync"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
 	"github.com/sitcon-tw/2026-game/internal/models"
-	"github.com/sitcon-tw/2026-game/pkg/loader"
 )
 
-// Cached gameplay data
-//
-//nolint:gochecknoglobals // Package-level cache intentionally shared across calls.
-var (
-	levelsOnce sync.Once
-	levels     []models.Level
-	errLevels  error
-
-	sheetOnce sync.Once
-	sheet     []string
-	errSheet  error
-
-	levelInfosOnce sync.Once
-	levelInfos     map[int]models.LevelInfo
-	errLevelInfos  error
+const (
+	// maxTimeSignatureValue bounds both halves of a time signature.
+	maxTimeSignatureValue = 32
+	minChordPitches       = 2
+	// beatTolerance absorbs float rounding when a note ends exactly where the next one starts.
+	beatTolerance = 1e-9
 )
 
-// Levels returns the parsed list of levels from the CSV URL configured via env.
-func Levels() ([]models.Level, error) {
-	levelsOnce.Do(func() {
-		levels, errLevels = loader.LoadLevels(Env().LevelCSVURL)
-	})
-	return levels, errLevels
+// ErrGameConfigNotLoaded is returned before the first game config version is activated.
+var ErrGameConfigNotLoaded = errors.New("game config not loaded")
+
+// GameConfig is one validated, immutable version of the level and sheet-music config.
+type GameConfig struct {
+	Version int
+	Levels  []models.Level
+	// Tracks are keyed by track ID.
+	Tracks map[string]models.SheetMusic
+
+	infos map[int]models.LevelInfo
 }
 
-// SheetMusic returns the ordered notes from the CSV URL configured via env.
-func SheetMusic() ([]string, error) {
-	sheetOnce.Do(func() {
-		sheet, errSheet = loader.LoadSheetMusic(Env().SheetMusicCSVURL)
-	})
-	return sheet, errSheet
+// activeGameConfig is swapped as a whole so requests never see a half-applied reload.
+//
+//nolint:gochecknoglobals // process-wide active config, replaced atomically on reload
+var activeGameConfig atomic.Pointer[GameConfig]
+
+// NewGameConfig validates levels and tracks and precomputes every level's sheet window.
+func NewGameConfig(version int, levels []models.Level, tracks []models.SheetMusic) (*GameConfig, error) {
+	if len(levels) == 0 {
+		return nil, errors.New("level config is empty")
+	}
+	byID, err := indexTracks(tracks)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := buildLevelInfos(levels, byID)
+	if err != nil {
+		return nil, err
+	}
+	return &GameConfig{
+		Version: version,
+		Levels:  levels,
+		Tracks:  byID,
+		infos:   infos,
+	}, nil
 }
 
-// LevelInfo returns precomputed level metadata with per-level sheet window.
-func LevelInfo(level int) (models.LevelInfo, bool, error) {
-	levelInfosOnce.Do(func() {
-		var levelsData []models.Level
-		levelsData, errLevelInfos = Levels()
-		if errLevelInfos != nil {
-			return
-		}
+// LevelInfo returns the metadata of one level in this version.
+func (c *GameConfig) LevelInfo(level int) (models.LevelInfo, bool) {
+	info, ok := c.infos[level]
+	return info, ok
+}
 
-		var sheetData []string
-		sheetData, errLevelInfos = SheetMusic()
-		if errLevelInfos != nil {
-			return
-		}
+// Track returns one sheet-music track of this version.
+func (c *GameConfig) Track(id string) (models.SheetMusic, bool) {
+	track, ok := c.Tracks[id]
+	return track, ok
+}
+
+// NoteCount returns the number of notes across all tracks.
+func (c *GameConfig) NoteCount() int {
+	n := 0
+	for _, track := range c.Tracks {
+		n += len(track.Notes)
+	}
+	return n
+}
 
-		levelInfos, errLevelInfos = buildLevelInfos(levelsData, sheetData)
-	})
-	if errLevelInfos != nil {
-		return models.LevelInfo{}, false, errLevelInfos
+// ActiveGameConfig returns the game config currently served.
+func ActiveGameConfig() (*GameConfig, error) {
+	cfg := activeGameConfig.Load()
+	if cfg == nil {
+		return nil, ErrGameConfigNotLoaded
 	}
+	return cfg, nil
+}
 
-	info, ok := levelInfos[level]
+// SetActiveGameConfig makes cfg the served game config.
+func SetActiveGameConfig(cfg *GameConfig) {
+	activeGameConfig.Store(cfg)
+}
+
+// LevelInfo returns precomputed level metadata with per-level sheet window from the active config.
+func LevelInfo(level int) (models.LevelInfo, bool, error) {
+	cfg, err := ActiveGameConfig()
+	if err != nil {
+		return models.LevelInfo{}, false, err
+	}
+	info, ok := cfg.LevelInfo(level)
 	return info, ok, nil
 }
 
-func buildLevelInfos(levels []models.Level, sheet []string) (map[int]models.LevelInfo, error) {
-	if len(sheet) == 0 {
-		return nil, errors.New("sheet music is empty")
+func indexTracks(tracks []models.SheetMusic) (map[string]models.SheetMusic, error) {
+	byID := make(map[string]models.SheetMusic, len(tracks))
+	for _, track := range tracks {
+		if track.TrackID == "" {
+			return nil, errors.New("sheet music track id is empty")
+		}
+		if _, exists := byID[track.TrackID]; exists {
+			return nil, fmt.Errorf("duplicate sheet music track %q", track.TrackID)
+		}
+		if len(track.Notes) == 0 {
+			return nil, fmt.Errorf("sheet music track %q is empty", track.TrackID)
+		}
+		if err := validateChart(track.Notes); err != nil {
+			return nil, fmt.Errorf("sheet music track %q: %w", track.TrackID, err)
+		}
+		if track.Tempo <= 0 {
+			return nil, fmt.Errorf("sheet music track %q has invalid tempo %d", track.TrackID, track.Tempo)
+		}
+		if !validTimeSignature(track.TimeSignature) {
+			return nil, fmt.Errorf("sheet music track %q has invalid time signature %q", track.TrackID, track.TimeSignature)
+		}
+		byID[track.TrackID] = track
+	}
+	return byID, nil
+}
+
+// validTimeSignature accepts "beats/unit" with 1-32 beats and a power-of-two unit up to 32.
+func validTimeSignature(raw string) bool {
+	beatsRaw, unitRaw, ok := strings.Cut(raw, "/")
+	if !ok {
+		return false
+	}
+	beats, err := strconv.Atoi(beatsRaw)
+	if err != nil || beats < 1 || beats > maxTimeSignatureValue {
+		return false
+	}
+	unit, err := strconv.Atoi(unitRaw)
+	if err != nil || unit < 1 || unit > maxTimeSignatureValue {
+		return false
 	}
+	return unit&(unit-1) == 0
+}
 
+// validateChart checks every note's shape and that each note starts after the previous one ends.
+func validateChart(notes []models.Note) error {
+	for i, note := range notes {
+		if note.Beat < 0 || note.Duration <= 0 {
+			return fmt.Errorf("note %d has invalid beat %v or duration %v", i+1, note.Beat, note.Duration)
+		}
+		if slices.Contains(note.Pitches, "") {
+			return fmt.Errorf("note %d has an empty pitch", i+1)
+		}
+		switch note.Type {
+		case models.NoteTap, models.NoteHold:
+			if len(note.Pitches) != 1 {
+				return fmt.Errorf("%s note %d needs exactly one pitch, got %d", note.Type, i+1, len(note.Pitches))
+			}
+		case models.NoteChord:
+			if len(note.Pitches) < minChordPitches {
+				return fmt.Errorf("chord note %d needs at least two pitches, got %d", i+1, len(note.Pitches))
+			}
+		default:
+			return fmt.Errorf("note %d has unknown type %q", i+1, note.Type)
+		}
+		if i > 0 && note.Beat < notes[i-1].End()-beatTolerance {
+			return fmt.Errorf("note %d starts at beat %v before note %d ends", i+1, note.Beat, i)
+		}
+	}
+	return nil
+}
+
+// buildLevelInfos gives every level the next Notes notes of its track, with beats counted from the
+// level's first note. Each track keeps its own position, so ranges on different tracks do not shift
+// each other's melody.
+func buildLevelInfos(levels []models.Level, tracks map[string]models.SheetMusic) (map[int]models.LevelInfo, error) {
 	totalLevelCount := 0
 	for _, lvl := range levels {
 		if lvl.StartLevel <= 0 || lvl.EndLevel <= 0 || lvl.EndLevel < lvl.StartLevel || lvl.Speed <= 0 || lvl.Notes <= 0 {
 			return nil, errors.New("invalid level config value")
 		}
+		if _, ok := tracks[levelTrack(lvl)]; !ok {
+			return nil, fmt.Errorf("level %d-%d uses unknown sheet music track %q",
+				lvl.StartLevel, lvl.EndLevel, levelTrack(lvl))
+		}
 		totalLevelCount += lvl.EndLevel - lvl.StartLevel + 1
 	}
 
 	out := make(map[int]models.LevelInfo, totalLevelCount)
-	start := 0
+	starts := make(map[string]int, len(tracks))
 
 	for _, lvl := range levels {
+		trackID := levelTrack(lvl)
+		track := tracks[trackID]
+		length := track.Length()
 		for levelNum := lvl.StartLevel; levelNum <= lvl.EndLevel; levelNum++ {
 			if _, exists := out[levelNum]; exists {
 				return nil, errors.New("duplicate level in config ranges")
 			}
 
-			notes := make([]string, lvl.Notes)
+			start := starts[trackID]
+			chart := make([]models.Note, lvl.Notes)
+			labels := make([]string, lvl.Notes)
+			var origin float64
 			for i := range lvl.Notes {
-				notes[i] = sheet[(start+i)%len(sheet)]
+				pos := start + i
+				note := track.Notes[pos%len(track.Notes)]
+				// Each pass through the track continues where the previous one ended.
+				beat := note.Beat + float64(pos/len(track.Notes))*length
+				if i == 0 {
+					origin = beat
+				}
+				note.Beat = beat - origin
+				chart[i] = note
+				labels[i] = note.Label()
 			}
 
 			out[levelNum] = models.LevelInfo{
-				Level: levelNum,
-				Speed: lvl.Speed,
-				Notes: lvl.Notes,
-				Sheet: notes,
+				Level:   levelNum,
+				Speed:   lvl.Speed,
+				Notes:   lvl.Notes,
+				TrackID: trackID,
+				Sheet:   labels,
+				Chart:   chart,
+				Beats:   chart[len(chart)-1].End(),
 			}
-			start += lvl.Notes
+			starts[trackID] = start + lvl.Notes
 		}
 	}
 
 	return out, nil
 }
+
+func levelTrack(lvl models.Level) string {
+	if lvl.Track == "" {
+		return models.DefaultTrackID
+	}
+	return lvl.Track
+}
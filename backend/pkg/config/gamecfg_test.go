@@ -0,0 +1,165 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+)
+
+// track builds a track of one-beat taps, like a legacy one-column sheet.
+func track(id string, pitches ...string) models.SheetMusic {
+	notes := make([]models.Note, len(pitches))
+	for i, pitch := range pitches {
+		notes[i] = models.Note{Pitches: []string{pitch}, Beat: float64(i), Duration: 1, Type: models.NoteTap}
+	}
+	return models.SheetMusic{TrackID: id, Tempo: 120, TimeSignature: "4/4", Notes: notes}
+}
+
+func TestNewGameConfig(t *testing.T) {
+	t.Parallel()
+
+	levels := []models.Level{
+		{StartLevel: 1, EndLevel: 2, Speed: 60, Notes: 2},
+		{StartLevel: 3, EndLevel: 3, Speed: 90, Notes: 3},
+	}
+	cfg, err := config.NewGameConfig(7, levels, []models.SheetMusic{track(models.DefaultTrackID, "C4", "D4", "E4")})
+	if err != nil {
+		t.Fatalf("NewGameConfig: %v", err)
+	}
+	if cfg.Version != 7 {
+		t.Errorf("version: got %d", cfg.Version)
+	}
+
+	// Levels continue through the sheet where the previous level stopped, wrapping around.
+	info, ok := cfg.LevelInfo(3)
+	if !ok {
+		t.Fatal("level 3 missing")
+	}
+	assertSheet(t, info.Sheet, "D4", "E4", "C4")
+	if info.TrackID != models.DefaultTrackID {
+		t.Errorf("track: got %q", info.TrackID)
+	}
+	if _, ok = cfg.LevelInfo(4); ok {
+		t.Error("level 4 should not exist")
+	}
+
+	overlapping := append(levels, models.Level{StartLevel: 2, EndLevel: 4, Speed: 60, Notes: 1})
+	if _, err = config.NewGameConfig(8, overlapping, []models.SheetMusic{track(models.DefaultTrackID, "C4")}); err == nil {
+		t.Error("overlapping ranges accepted")
+	}
+	if _, err = config.NewGameConfig(8, levels, []models.SheetMusic{track(models.DefaultTrackID)}); err == nil {
+		t.Error("empty sheet accepted")
+	}
+	if _, err = config.NewGameConfig(8, nil, []models.SheetMusic{track(models.DefaultTrackID, "C4")}); err == nil {
+		t.Error("empty levels accepted")
+	}
+}
+
+func TestNewGameConfigTracks(t *testing.T) {
+	t.Parallel()
+
+	levels := []models.Level{
+		{StartLevel: 1, EndLevel: 1, Speed: 60, Notes: 2},
+		{StartLevel: 2, EndLevel: 2, Speed: 60, Notes: 2, Track: "sponsor"},
+		{StartLevel: 3, EndLevel: 3, Speed: 60, Notes: 2},
+	}
+	tracks := []models.SheetMusic{
+		track(models.DefaultTrackID, "C4", "D4", "E4", "F4"),
+		track("sponsor", "A5", "B5"),
+	}
+	cfg, err := config.NewGameConfig(1, levels, tracks)
+	if err != nil {
+		t.Fatalf("NewGameConfig: %v", err)
+	}
+
+	// A themed range does not move the default track's position.
+	info, _ := cfg.LevelInfo(2)
+	if info.TrackID != "sponsor" {
+		t.Errorf("level 2 track: got %q", info.TrackID)
+	}
+	assertSheet(t, info.Sheet, "A5", "B5")
+	info, _ = cfg.LevelInfo(3)
+	assertSheet(t, info.Sheet, "E4", "F4")
+
+	unknown := append(levels, models.Level{StartLevel: 4, EndLevel: 4, Speed: 60, Notes: 1, Track: "missing"})
+	if _, err = config.NewGameConfig(1, unknown, tracks); err == nil {
+		t.Error("unknown track accepted")
+	}
+	if _, err = config.NewGameConfig(1, levels, append(tracks, track("sponsor", "C4"))); err == nil {
+		t.Error("duplicate track accepted")
+	}
+
+	for _, sig := range []string{"4", "0/4", "3/5", "4/64", "x/4"} {
+		bad := track("sponsor", "A5")
+		bad.TimeSignature = sig
+		if _, err = config.NewGameConfig(1, levels, []models.SheetMusic{tracks[0], bad}); err == nil {
+			t.Errorf("time signature %q accepted", sig)
+		}
+	}
+}
+
+func TestNewGameConfigChart(t *testing.T) {
+	t.Parallel()
+
+	chart := models.SheetMusic{
+		TrackID:       models.DefaultTrackID,
+		Tempo:         120,
+		TimeSignature: "4/4",
+		Notes: []models.Note{
+			{Pitches: []string{"C4"}, Beat: 0, Duration: 0.5, Type: models.NoteTap},
+			{Pitches: []string{"C4", "E4", "G4"}, Beat: 0.5, Duration: 1.5, Type: models.NoteChord},
+			{Pitches: []string{"G4"}, Beat: 2, Duration: 4, Type: models.NoteHold},
+		},
+	}
+	levels := []models.Level{
+		{StartLevel: 1, EndLevel: 1, Speed: 60, Notes: 2},
+		{StartLevel: 2, EndLevel: 2, Speed: 60, Notes: 3},
+	}
+	cfg, err := config.NewGameConfig(1, levels, []models.SheetMusic{chart})
+	if err != nil {
+		t.Fatalf("NewGameConfig: %v", err)
+	}
+
+	// Level 2 starts on the hold and wraps into the next pass, which begins after the hold ends.
+	info, _ := cfg.LevelInfo(2)
+	assertSheet(t, info.Sheet, "G4", "C4", "C4+E4+G4")
+	wantBeats := []float64{0, 4, 4.5}
+	for i, note := range info.Chart {
+		if note.Beat != wantBeats[i] {
+			t.Errorf("level 2 note %d beat: got %v, want %v", i, note.Beat, wantBeats[i])
+		}
+	}
+	if info.Beats != 6 {
+		t.Errorf("level 2 beats: got %v, want 6", info.Beats)
+	}
+
+	bad := []models.Note{
+		{Pitches: []string{"C4"}, Beat: 0, Duration: 2, Type: models.NoteHold},
+		{Pitches: []string{"D4"}, Beat: 1, Duration: 1, Type: models.NoteTap},
+	}
+	for name, notes := range map[string][]models.Note{
+		"overlapping notes":  bad,
+		"single-pitch chord": {{Pitches: []string{"C4"}, Beat: 0, Duration: 1, Type: models.NoteChord}},
+		"two-pitch tap":      {{Pitches: []string{"C4", "E4"}, Beat: 0, Duration: 1, Type: models.NoteTap}},
+		"zero duration":      {{Pitches: []string{"C4"}, Beat: 0, Duration: 0, Type: models.NoteTap}},
+		"unknown note type":  {{Pitches: []string{"C4"}, Beat: 0, Duration: 1, Type: "slide"}},
+	} {
+		chart.Notes = notes
+		if _, err = config.NewGameConfig(1, levels, []models.SheetMusic{chart}); err == nil {
+			t.Errorf("%s accepted", name)
+		}
+	}
+}
+
+func assertSheet(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("sheet: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sheet: got %v, want %v", got, want)
+		}
+	}
+}
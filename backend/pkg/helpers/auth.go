@@ -1,6 +1,10 @@
 package helpers
 
-import "strings"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
 
 // BearerToken extracts the token part from an Authorization header.
 // Returns empty string if the header is missing or not in Bearer format.
@@ -14,3 +18,10 @@ func BearerToken(header string) string {
 	}
 	return strings.TrimSpace(strings.TrimPrefix(header, prefix))
 }
+
+// HashSecret returns the hex SHA-256 of a server-generated secret (login key or session token) for storage.
+// The secrets are long random tokens, so a fast hash is enough and keeps them searchable by hash.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BoothBatchScan is one queued offline scan as signed by the booth device.
+type BoothBatchScan struct {
+	UserQRCode string
+	ScannedAt  time.Time
+}
+
+// BoothBundleKey derives the key a booth uses to sign queued scans uploaded under one
+// offline bundle. It is recomputed from the bundle header on upload, so bundles need no
+// server-side storage.
+func BoothBundleKey(serverSecret string, boothID string, bundleID string, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, []byte(serverSecret))
+	_, _ = mac.Write([]byte("booth-bundle:" + boothID + ":" + bundleID + ":"))
+	_, _ = mac.Write([]byte(strconv.FormatInt(expiresAt.UTC().Unix(), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignBoothBatch signs scans with a bundle key. The signed message is one line per scan,
+// "<user_qr_code>\n<scanned_at unix seconds>\n", in upload order.
+func SignBoothBatch(bundleKey string, scans []BoothBatchScan) string {
+	mac := hmac.New(sha256.New, []byte(bundleKey))
+	_, _ = mac.Write([]byte(boothBatchMessage(scans)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyBoothBatch reports whether signature matches SignBoothBatch(bundleKey, scans).
+func VerifyBoothBatch(bundleKey string, scans []BoothBatchScan, signature string) bool {
+	provided, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(SignBoothBatch(bundleKey, scans))
+	return hmac.Equal(provided, expected)
+}
+
+func boothBatchMessage(scans []BoothBatchScan) string {
+	var b strings.Builder
+	for _, scan := range scans {
+		b.WriteString(scan.UserQRCode)
+		b.WriteByte('\n')
+		b.WriteString(strconv.FormatInt(scan.ScannedAt.UTC().Unix(), 10))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package helpers //nolint:testpackage // tests need access to unexported token internals
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoothBatchSignature(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2026, 3, 21, 18, 0, 0, 0, time.UTC)
+	key := BoothBundleKey("server", "booth-1", "bundle-1", expiresAt)
+	if key == BoothBundleKey("server", "booth-2", "bundle-1", expiresAt) {
+		t.Fatal("bundle key must be bound to the booth")
+	}
+	if key == BoothBundleKey("server", "booth-1", "bundle-2", expiresAt) {
+		t.Fatal("bundle key must be bound to the bundle")
+	}
+	if key == BoothBundleKey("server", "booth-1", "bundle-1", expiresAt.Add(time.Hour)) {
+		t.Fatal("bundle key must be bound to the expiry")
+	}
+
+	scans := []BoothBatchScan{
+		{UserQRCode: "qru2.a", ScannedAt: expiresAt.Add(-time.Hour)},
+		{UserQRCode: "qru2.b", ScannedAt: expiresAt.Add(-time.Minute)},
+	}
+	signature := SignBoothBatch(key, scans)
+	if !VerifyBoothBatch(key, scans, signature) {
+		t.Fatal("expected signature to verify")
+	}
+
+	scans[1].ScannedAt = expiresAt
+	if VerifyBoothBatch(key, scans, signature) {
+		t.Fatal("tampered scan time must not verify")
+	}
+	if VerifyBoothBatch(key, scans, "not-hex") {
+		t.Fatal("malformed signature must not verify")
+	}
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -27,38 +28,197 @@ func QRTokenTTL() time.Duration {
 	return time.Duration(qrTokenStepSeconds) * time.Second
 }
 
+// QRTokenWindowSteps returns how many steps before or after the current one a token may come from.
+func QRTokenWindowSteps() int {
+	return int(qrTokenWindow)
+}
+
 // QRTokenExpiry returns the expiration time of the current token step.
 func QRTokenExpiry(now time.Time) time.Time {
 	step := now.UTC().Unix() / qrTokenStepSeconds
 	return time.Unix((step+1)*qrTokenStepSeconds, 0).UTC()
 }
 
-// BuildUserOneTimeQRToken creates a short-lived user QR token in the form:
+// QR token prefixes. Each prefix is bound to one purpose so a token shown for one flow
+// cannot be scanned into another.
+const (
+	userQRTokenPrefix       = "qru2"
+	legacyUserQRTokenPrefix = "qru1"
+	couponQRTokenPrefix     = "qrc1"
+	couponQRPurpose         = "coupon"
+	qrNonceLength           = 12
+)
+
+// QRPurpose names the flow a one-time user QR token may be scanned into.
+type QRPurpose string
+
+const (
+	QRPurposeFriend           QRPurpose = "friend"
+	QRPurposeGroupCheckIn     QRPurpose = "group_check_in"
+	QRPurposeBoothCheckIn     QRPurpose = "booth_check_in"
+	QRPurposeCouponAssignment QRPurpose = "coupon_assignment"
+)
+
+// ParseQRPurpose validates a purpose supplied by a client.
+func ParseQRPurpose(s string) (QRPurpose, bool) {
+	switch p := QRPurpose(s); p {
+	case QRPurposeFriend, QRPurposeGroupCheckIn, QRPurposeBoothCheckIn, QRPurposeCouponAssignment:
+		return p, true
+	}
+	return "", false
+}
+
+// OneTimeQRClaims are the verified contents of a qru2 token. Callers record Nonce until
+// ExpiresAt so the token can be consumed only once per purpose.
+type OneTimeQRClaims struct {
+	UserID    string
+	Purpose   QRPurpose
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+// NewQRNonce returns a random nonce for BuildUserOneTimeQRToken.
+func NewQRNonce() (string, error) {
+	return RandomAlphabetToken(qrNonceLength)
+}
+
+// BuildUserOneTimeQRToken creates a short-lived, single-use user QR token in the form:
+// qru2.<purpose>.<base64url(userID)>.<nonce>.<step>.<base64url(signature)>
+// The signature is Ed25519 over everything before the last dot, so anyone holding the public
+// key, such as an offline booth, can verify the token without being able to mint one.
+func BuildUserOneTimeQRToken(userID string, key ed25519.PrivateKey, purpose QRPurpose, nonce string, now time.Time) string {
+	step := now.UTC().Unix() / qrTokenStepSeconds
+	userPart := base64.RawURLEncoding.EncodeToString([]byte(userID))
+	payload := fmt.Sprintf("%s.%s.%s.%s.%d", userQRTokenPrefix, purpose, userPart, nonce, step)
+	signature := ed25519.Sign(key, []byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// VerifyOneTimeQRToken validates token freshness, signature and purpose.
+// It does not check whether the nonce was already used or the user exists; that is the caller's job.
+func VerifyOneTimeQRToken(
+	token string,
+	purpose QRPurpose,
+	now time.Time,
+	publicKey ed25519.PublicKey,
+) (*OneTimeQRClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 6 || parts[0] != userQRTokenPrefix {
+		return nil, errors.New("invalid token format")
+	}
+	if QRPurpose(parts[1]) != purpose {
+		return nil, errors.New("token issued for another purpose")
+	}
+	nonce := parts[3]
+	if nonce == "" {
+		return nil, errors.New("missing token nonce")
+	}
+
+	userID, err := decodeQRUserID(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	step, err := strconv.ParseInt(parts[4], 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid token step")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, errors.New("invalid token signature encoding")
+	}
+
+	payload := token[:strings.LastIndexByte(token, '.')]
+	if !ed25519.Verify(publicKey, []byte(payload), signature) {
+		return nil, errors.New("invalid token signature")
+	}
+	stepNow := now.UTC().Unix() / qrTokenStepSeconds
+	if step < stepNow-qrTokenWindow || step > stepNow+qrTokenWindow {
+		return nil, errors.New("token expired")
+	}
+	return &OneTimeQRClaims{
+		UserID:    userID,
+		Purpose:   purpose,
+		Nonce:     nonce,
+		ExpiresAt: time.Unix((step+1+qrTokenWindow)*qrTokenStepSeconds, 0).UTC(),
+	}, nil
+}
+
+// BuildLegacyUserQRToken creates a purpose-less user QR token in the old form:
 // qru1.<base64url(userID)>.<6-digit-code>
-func BuildUserOneTimeQRToken(userID string, secret string, now time.Time) string {
+// It is only issued to clients that do not send a purpose yet.
+func BuildLegacyUserQRToken(userID string, secret string, now time.Time) string {
 	step := now.UTC().Unix() / qrTokenStepSeconds
 	userPart := base64.RawURLEncoding.EncodeToString([]byte(userID))
-	code := qrStepCode(secret, step)
-	return fmt.Sprintf("qru1.%s.%06d", userPart, code)
+	code := qrStepCode(secret, "", step)
+	return fmt.Sprintf("%s.%s.%06d", legacyUserQRTokenPrefix, userPart, code)
 }
 
-// VerifyAndExtractUserIDFromOneTimeQRToken validates token freshness and signature.
-// It returns the embedded user ID when the token is valid.
-func VerifyAndExtractUserIDFromOneTimeQRToken(token string, now time.Time, lookupSecret func(string) (string, error)) (string, error) {
+// VerifyLegacyUserQRToken validates a qru1 token scanned into purpose. A qru1 token has no
+// nonce, so the claims carry one derived from its step: the token is consumed once per
+// purpose like a qru2 token, but every client showing it during that step shares it.
+func VerifyLegacyUserQRToken(
+	token string,
+	purpose QRPurpose,
+	now time.Time,
+	lookupSecret func(string) (string, error),
+) (*OneTimeQRClaims, error) {
 	parts := strings.Split(token, ".")
-	if len(parts) != 3 || parts[0] != "qru1" {
-		return "", errors.New("invalid token format")
+	if len(parts) != 3 || parts[0] != legacyUserQRTokenPrefix {
+		return nil, errors.New("invalid token format")
 	}
 
-	userIDBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	userID, err := decodeQRUserID(parts[1])
 	if err != nil {
-		return "", errors.New("invalid user id encoding")
+		return nil, err
 	}
-	userID := string(userIDBytes)
-	if userID == "" {
-		return "", errors.New("missing user id")
+	providedCode, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, errors.New("invalid token code")
+	}
+
+	secret, err := lookupSecret(userID)
+	if err != nil {
+		return nil, err
 	}
 
+	step, ok := matchStepCode(secret, "", providedCode, now)
+	if !ok {
+		return nil, errors.New("token expired or invalid")
+	}
+	return &OneTimeQRClaims{
+		UserID:    userID,
+		Purpose:   purpose,
+		Nonce:     legacyUserQRTokenPrefix + ":" + strconv.FormatInt(step, 10),
+		ExpiresAt: time.Unix((step+1+qrTokenWindow)*qrTokenStepSeconds, 0).UTC(),
+	}, nil
+}
+
+// IsLegacyUserQRToken reports whether token has the qru1 prefix. It does not verify the token.
+func IsLegacyUserQRToken(token string) bool {
+	return strings.HasPrefix(token, legacyUserQRTokenPrefix+".")
+}
+
+// BuildUserCouponQRToken creates a short-lived coupon redemption token in the form:
+// qrc1.<base64url(userID)>.<6-digit-code>
+// The code is bound to the coupon purpose, so it never matches a user QR code.
+func BuildUserCouponQRToken(userID string, secret string, now time.Time) string {
+	step := now.UTC().Unix() / qrTokenStepSeconds
+	userPart := base64.RawURLEncoding.EncodeToString([]byte(userID))
+	code := qrStepCode(secret, couponQRPurpose, step)
+	return fmt.Sprintf("%s.%s.%06d", couponQRTokenPrefix, userPart, code)
+}
+
+// VerifyAndExtractUserIDFromCouponQRToken validates a qrc1 token and returns the embedded user ID.
+func VerifyAndExtractUserIDFromCouponQRToken(token string, now time.Time, lookupSecret func(string) (string, error)) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != couponQRTokenPrefix {
+		return "", errors.New("invalid token format")
+	}
+
+	userID, err := decodeQRUserID(parts[1])
+	if err != nil {
+		return "", err
+	}
 	providedCode, err := strconv.Atoi(parts[2])
 	if err != nil {
 		return "", errors.New("invalid token code")
@@ -69,19 +229,43 @@ func VerifyAndExtractUserIDFromOneTimeQRToken(token string, now time.Time, looku
 		return "", err
 	}
 
+	if _, ok := matchStepCode(secret, couponQRPurpose, providedCode, now); !ok {
+		return "", errors.New("token expired or invalid")
+	}
+	return userID, nil
+}
+
+// IsCouponQRToken reports whether token has the coupon QR prefix. It does not verify the token.
+func IsCouponQRToken(token string) bool {
+	return strings.HasPrefix(token, couponQRTokenPrefix+".")
+}
+
+func decodeQRUserID(part string) (string, error) {
+	userIDBytes, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return "", errors.New("invalid user id encoding")
+	}
+	if len(userIDBytes) == 0 {
+		return "", errors.New("missing user id")
+	}
+	return string(userIDBytes), nil
+}
+
+// matchStepCode returns the step within the allowed window whose code equals providedCode.
+func matchStepCode(secret string, binding string, providedCode int, now time.Time) (int64, bool) {
 	stepNow := now.UTC().Unix() / qrTokenStepSeconds
 	for offset := -qrTokenWindow; offset <= qrTokenWindow; offset++ {
-		expectedCode := qrStepCode(secret, stepNow+offset)
-		if expectedCode == providedCode {
-			return userID, nil
+		if qrStepCode(secret, binding, stepNow+offset) == providedCode {
+			return stepNow + offset, true
 		}
 	}
-
-	return "", errors.New("token expired or invalid")
+	return 0, false
 }
 
-func qrStepCode(secret string, step int64) int {
+// qrStepCode derives the code for a step, bound to the token's purpose.
+func qrStepCode(secret string, binding string, step int64) int {
 	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(binding + ":"))
 	_, _ = mac.Write([]byte(strconv.FormatInt(step, 10)))
 	sum := mac.Sum(nil)
 
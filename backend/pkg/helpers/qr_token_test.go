@@ -0,0 +1,114 @@
+package helpers //nolint:testpackage // tests need access to unexported token internals
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCouponQRTokenRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	lookup := func(string) (string, error) { return "secret", nil }
+
+	token := BuildUserCouponQRToken("user-1", "secret", now)
+	if !IsCouponQRToken(token) {
+		t.Fatalf("token %q lacks coupon prefix", token)
+	}
+
+	userID, err := VerifyAndExtractUserIDFromCouponQRToken(token, now.Add(QRTokenTTL()), lookup)
+	if err != nil || userID != "user-1" {
+		t.Fatalf("verify within window = (%q, %v), want user-1", userID, err)
+	}
+
+	if _, err = VerifyAndExtractUserIDFromCouponQRToken(token, now.Add(3*QRTokenTTL()), lookup); err == nil {
+		t.Fatal("expected token outside the window to be rejected")
+	}
+}
+
+func TestOneTimeQRTokenIsPurposeBound(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	lookup := func(string) (string, error) { return "secret", nil }
+	key := testQRSigningKey("server")
+	public, _ := key.Public().(ed25519.PublicKey)
+
+	token := BuildUserOneTimeQRToken("user-1", key, QRPurposeFriend, "nonce1", now)
+	claims, err := VerifyOneTimeQRToken(token, QRPurposeFriend, now, public)
+	if err != nil {
+		t.Fatalf("verify friend token: %v", err)
+	}
+	if claims.UserID != "user-1" || claims.Nonce != "nonce1" || !claims.ExpiresAt.After(now) {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+
+	if _, err = VerifyOneTimeQRToken(token, QRPurposeBoothCheckIn, now, public); err == nil {
+		t.Fatal("friend token must not verify for booth check-in")
+	}
+
+	// Relabelling the purpose or swapping the nonce breaks the signature.
+	relabelled := strings.Replace(token, string(QRPurposeFriend), string(QRPurposeBoothCheckIn), 1)
+	if _, err = VerifyOneTimeQRToken(relabelled, QRPurposeBoothCheckIn, now, public); err == nil {
+		t.Fatal("relabelled token must not verify")
+	}
+	renonced := strings.Replace(token, "nonce1", "nonce2", 1)
+	if _, err = VerifyOneTimeQRToken(renonced, QRPurposeFriend, now, public); err == nil {
+		t.Fatal("token with a swapped nonce must not verify")
+	}
+	if _, err = VerifyOneTimeQRToken(token, QRPurposeFriend, now.Add(3*QRTokenTTL()), public); err == nil {
+		t.Fatal("expected token outside the window to be rejected")
+	}
+	other, _ := testQRSigningKey("other").Public().(ed25519.PublicKey)
+	if _, err = VerifyOneTimeQRToken(token, QRPurposeFriend, now, other); err == nil {
+		t.Fatal("token must not verify under another key")
+	}
+
+	couponToken := BuildUserCouponQRToken("user-1", "secret", now)
+	if _, err = VerifyOneTimeQRToken(couponToken, QRPurposeFriend, now, public); err == nil {
+		t.Fatal("coupon QR token must not verify as a user token")
+	}
+	if _, err = VerifyAndExtractUserIDFromCouponQRToken(token, now, lookup); err == nil {
+		t.Fatal("user QR token must not verify as a coupon token")
+	}
+}
+
+func TestLegacyUserQRToken(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	lookup := func(string) (string, error) { return "secret", nil }
+
+	token := BuildLegacyUserQRToken("user-1", "secret", now)
+	if !IsLegacyUserQRToken(token) {
+		t.Fatalf("token %q lacks legacy prefix", token)
+	}
+
+	friend, err := VerifyLegacyUserQRToken(token, QRPurposeFriend, now.Add(QRTokenTTL()), lookup)
+	if err != nil || friend.UserID != "user-1" || friend.Purpose != QRPurposeFriend {
+		t.Fatalf("verify within window = (%+v, %v), want user-1 for friend", friend, err)
+	}
+	booth, err := VerifyLegacyUserQRToken(token, QRPurposeBoothCheckIn, now, lookup)
+	if err != nil || booth.Nonce != friend.Nonce {
+		t.Fatalf("verify for booth = (%+v, %v), want the same nonce as for friend", booth, err)
+	}
+
+	if _, err = VerifyLegacyUserQRToken(token, QRPurposeFriend, now.Add(3*QRTokenTTL()), lookup); err == nil {
+		t.Fatal("expected token outside the window to be rejected")
+	}
+	public, _ := testQRSigningKey("server").Public().(ed25519.PublicKey)
+	if _, err = VerifyOneTimeQRToken(token, QRPurposeFriend, now, public); err == nil {
+		t.Fatal("qru1 token must not verify as a qru2 token")
+	}
+	if _, err = VerifyAndExtractUserIDFromCouponQRToken(token, now, lookup); err == nil {
+		t.Fatal("qru1 token must not verify as a coupon token")
+	}
+}
+
+func testQRSigningKey(name string) ed25519.PrivateKey {
+	seed := sha256.Sum256([]byte(name))
+	return ed25519.NewKeyFromSeed(seed[:])
+}
@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CachedConfig is the last game config an instance served from the database, kept on disk so the
+// next start can still serve it when neither the database nor the CSV URLs are reachable.
+type CachedConfig struct {
+	Version   int           `json:"version"`
+	LevelsCSV string        `json:"levels_csv"`
+	Tracks    []CachedTrack `json:"tracks"`
+	SavedAt   time.Time     `json:"saved_at"`
+}
+
+// CachedTrack is one sheet-music track of a CachedConfig.
+type CachedTrack struct {
+	TrackID       string `json:"track_id"`
+	Tempo         int    `json:"tempo"`
+	TimeSignature string `json:"time_signature"`
+	SheetCSV      string `json:"sheet_csv"`
+}
+
+// WriteCache replaces the cache at path. The file is written next to path and renamed over it,
+// so a crash never leaves a truncated cache behind.
+func WriteCache(path string, cfg CachedConfig) error {
+	body, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // already renamed on success
+
+	if _, err = tmp.Write(body); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write cache file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("write cache file: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace cache file: %w", err)
+	}
+	return nil
+}
+
+// ReadCache reads a cache written by WriteCache.
+func ReadCache(path string) (*CachedConfig, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cache file: %w", err)
+	}
+	var cfg CachedConfig
+	if err = json.Unmarshal(body, &cfg); err != nil {
+		return nil, fmt.Errorf("parse cache file %q: %w", path, err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,187 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+const chartColumns = 4
+
+// chartHeader is the header of a structured sheet music CSV.
+//
+//nolint:gochecknoglobals // fixed CSV header shared by the reader and writer
+var chartHeader = []string{"pitch", "beat", "duration", "type"}
+
+// ParseSheetMusic reads a chart from a sheet music CSV in one of two layouts:
+//
+//   - a "pitch,beat,duration,type" header followed by one note per row. Chord pitches are joined
+//     with models.ChordSeparator; an empty type means chord for several pitches and tap otherwise.
+//   - the legacy layout without header, one note name per row in the first column. Each row is a
+//     one-beat tap on the beat after the previous one.
+//
+// Ordering and overlap are checked when the config is built, not here.
+func ParseSheetMusic(src io.Reader) ([]models.Note, error) {
+	r := csv.NewReader(src)
+
+	first, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return []models.Note{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read row: %w", err)
+	}
+	if strings.ToLower(strings.TrimSpace(strings.TrimPrefix(first[0], "\ufeff"))) != chartHeader[0] {
+		return readLegacySheet(r, first)
+	}
+	if err = validateChartHeader(first); err != nil {
+		return nil, err
+	}
+	return readChart(r)
+}
+
+func readLegacySheet(r *csv.Reader, first []string) ([]models.Note, error) {
+	notes := []models.Note{legacyNote(first[0], 0)}
+
+	for {
+		row, readErr := r.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read row: %w", readErr)
+		}
+		if len(row) == 0 {
+			continue
+		}
+		notes = append(notes, legacyNote(row[0], len(notes)))
+	}
+
+	return notes, nil
+}
+
+func legacyNote(pitch string, index int) models.Note {
+	return models.Note{
+		Pitches:  []string{pitch},
+		Beat:     float64(index),
+		Duration: 1,
+		Type:     models.NoteTap,
+	}
+}
+
+func readChart(r *csv.Reader) ([]models.Note, error) {
+	notes := []models.Note{}
+
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read row: %w", err)
+		}
+
+		note, err := parseChartRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("sheet music row %d: %w", len(notes)+1, err)
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
+
+func parseChartRow(row []string) (models.Note, error) {
+	pitches := strings.Split(row[0], models.ChordSeparator)
+	for i := range pitches {
+		pitches[i] = strings.TrimSpace(pitches[i])
+		if pitches[i] == "" {
+			return models.Note{}, fmt.Errorf("empty pitch in %q", row[0])
+		}
+	}
+	beat, err := parseBeats(row[1], "beat")
+	if err != nil {
+		return models.Note{}, err
+	}
+	duration, err := parseBeats(row[2], "duration")
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	noteType := models.NoteType(strings.ToLower(strings.TrimSpace(row[3])))
+	switch noteType {
+	case models.NoteTap, models.NoteHold, models.NoteChord:
+	case "":
+		noteType = models.NoteTap
+		if len(pitches) > 1 {
+			noteType = models.NoteChord
+		}
+	default:
+		return models.Note{}, fmt.Errorf("unknown note type %q", row[3])
+	}
+
+	return models.Note{
+		Pitches:  pitches,
+		Beat:     beat,
+		Duration: duration,
+		Type:     noteType,
+	}, nil
+}
+
+func parseBeats(raw, field string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s %q: %w", field, raw, err)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return 0, fmt.Errorf("invalid %s: %s", field, raw)
+	}
+	return value, nil
+}
+
+func validateChartHeader(header []string) error {
+	if len(header) != chartColumns {
+		return fmt.Errorf("sheet music csv has %d columns, want exactly %d", len(header), chartColumns)
+	}
+
+	for i := range chartHeader {
+		actual := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+		if actual != chartHeader[i] {
+			return fmt.Errorf("invalid sheet music header at column %d: got %q, want %q", i+1, header[i], chartHeader[i])
+		}
+	}
+	return nil
+}
+
+// FormatSheetMusic writes notes as a structured sheet music CSV that ParseSheetMusic reads back.
+func FormatSheetMusic(notes []models.Note) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(chartHeader); err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		row := []string{
+			note.Label(),
+			strconv.FormatFloat(note.Beat, 'f', -1, 64),
+			strconv.FormatFloat(note.Duration, 'f', -1, 64),
+			string(note.Type),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,21 @@
+package loader
+
+import "embed"
+
+//go:embed defaults/levels.csv defaults/sheet_music.csv
+//nolint:gochecknoglobals // embedded files
+var defaults embed.FS
+
+// DefaultCSV returns the level and sheet-music CSV built into the binary. It is a short playable
+// config served only when neither the database, the configured URL nor the on-disk cache works.
+func DefaultCSV() ([]byte, []byte) {
+	levelsCSV, err := defaults.ReadFile("defaults/levels.csv")
+	if err != nil {
+		panic(err) // embedded at build time
+	}
+	sheetCSV, err := defaults.ReadFile("defaults/sheet_music.csv")
+	if err != nil {
+		panic(err)
+	}
+	return levelsCSV, sheetCSV
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -17,9 +18,14 @@ import (
 const (
 	levelColumns   = 4
 	requestTimeout = 10 * time.Second
+	fileScheme     = "file://"
+	// levelTrackColumns is levelColumns plus the optional track column.
+	levelTrackColumns = levelColumns + 1
+	// MaxCSVBytes bounds a fetched or uploaded CSV; real configs are a few kilobytes.
+	MaxCSVBytes = 4 << 20
 )
 
-// LoadLevels reads level configuration from a CSV URL.
+// LoadLevels reads level configuration from a CSV URL (http, https or file://).
 func LoadLevels(csvURL string) ([]models.Level, error) {
 	f, err := fetchCSV(csvURL)
 	if err != nil {
@@ -27,7 +33,14 @@ func LoadLevels(csvURL string) ([]models.Level, error) {
 	}
 	defer f.Close()
 
-	r := csv.NewReader(f)
+	return ParseLevels(f)
+}
+
+// ParseLevels reads level configuration from CSV with a
+// "level start,level end,speed,notes" header and an optional fifth "track" column naming the
+// sheet-music track of each range. Ranges without a track play models.DefaultTrackID.
+func ParseLevels(src io.Reader) ([]models.Level, error) {
+	r := csv.NewReader(src)
 
 	header, err := r.Read()
 	if err != nil {
@@ -37,10 +50,10 @@ func LoadLevels(csvURL string) ([]models.Level, error) {
 		return nil, err
 	}
 
-	return readLevels(r)
+	return readLevels(r, len(header))
 }
 
-func readLevels(r *csv.Reader) ([]models.Level, error) {
+func readLevels(r *csv.Reader, columns int) ([]models.Level, error) {
 	levels := []models.Level{}
 
 	for {
@@ -52,7 +65,7 @@ func readLevels(r *csv.Reader) ([]models.Level, error) {
 			return nil, fmt.Errorf("read row: %w", err)
 		}
 
-		level, err := parseLevelRow(row)
+		level, err := parseLevelRow(row, columns)
 		if err != nil {
 			return nil, err
 		}
@@ -62,12 +75,19 @@ func readLevels(r *csv.Reader) ([]models.Level, error) {
 	return levels, nil
 }
 
-func parseLevelRow(row []string) (models.Level, error) {
-	if len(row) != levelColumns {
-		return models.Level{}, fmt.Errorf("row has %d columns, want exactly %d", len(row), levelColumns)
+func parseLevelRow(row []string, columns int) (models.Level, error) {
+	if len(row) != columns {
+		return models.Level{}, fmt.Errorf("row has %d columns, want exactly %d", len(row), columns)
 	}
 
-	return parseRangeLevelRow(row)
+	level, err := parseRangeLevelRow(row[:levelColumns])
+	if err != nil {
+		return models.Level{}, err
+	}
+	if columns == levelTrackColumns {
+		level.Track = strings.TrimSpace(row[levelColumns])
+	}
+	return level, nil
 }
 
 func parseRangeLevelRow(row []string) (models.Level, error) {
@@ -111,11 +131,11 @@ func parsePositiveInt(raw, field string) (int, error) {
 }
 
 func validateLevelHeader(header []string) error {
-	if len(header) != levelColumns {
-		return fmt.Errorf("level csv has %d columns, want exactly %d", len(header), levelColumns)
+	if len(header) != levelColumns && len(header) != levelTrackColumns {
+		return fmt.Errorf("level csv has %d columns, want %d or %d", len(header), levelColumns, levelTrackColumns)
 	}
 
-	expected := []string{"level start", "level end", "speed", "notes"}
+	expected := []string{"level start", "level end", "speed", "notes", "track"}[:len(header)]
 	for i := range expected {
 		actual := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
 		if actual != expected[i] {
@@ -125,38 +145,69 @@ func validateLevelHeader(header []string) error {
 	return nil
 }
 
-// LoadSheetMusic reads a list of note names from a one-column CSV URL.
-func LoadSheetMusic(csvURL string) ([]string, error) {
+// LoadSheetMusic reads a chart from a sheet music CSV URL (http, https or file://).
+func LoadSheetMusic(csvURL string) ([]models.Note, error) {
 	f, err := fetchCSV(csvURL)
 	if err != nil {
 		return nil, fmt.Errorf("fetch sheet music csv: %w", err)
 	}
 	defer f.Close()
 
-	r := csv.NewReader(f)
-	notes := []string{}
+	return ParseSheetMusic(f)
+}
 
-	for {
-		row, readErr := r.Read()
-		if errors.Is(readErr, io.EOF) {
-			break
-		}
-		if readErr != nil {
-			return nil, fmt.Errorf("read row: %w", readErr)
-		}
-		if len(row) == 0 {
-			continue
-		}
-		notes = append(notes, row[0])
+// Fetch reads a CSV URL (http, https or file://) and returns its body, at most MaxCSVBytes long.
+func Fetch(csvURL string) ([]byte, error) {
+	f, err := fetchCSV(csvURL)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readLimited(f, fmt.Sprintf("csv url %q", csvURL))
+}
+
+// ReadFile reads a local CSV file, at most MaxCSVBytes long.
+func ReadFile(path string) ([]byte, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("csv file path is empty")
 	}
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
 
-	return notes, nil
+	return readLimited(f, fmt.Sprintf("csv file %q", path))
+}
+
+func openFile(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open csv file: %w", err)
+	}
+	return f, nil
+}
+
+func readLimited(src io.Reader, name string) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(src, MaxCSVBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	if len(body) > MaxCSVBytes {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, MaxCSVBytes)
+	}
+	return body, nil
 }
 
 func fetchCSV(csvURL string) (io.ReadCloser, error) {
 	if strings.TrimSpace(csvURL) == "" {
 		return nil, errors.New("csv url is empty")
 	}
+	// file:// keeps local development and offline fallbacks on the same setting as the live URL.
+	if path, ok := strings.CutPrefix(csvURL, fileScheme); ok {
+		return openFile(path)
+	}
 
 	client := &http.Client{
 		Timeout: requestTimeout,
@@ -0,0 +1,293 @@
+package loader_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/loader"
+)
+
+func TestDefaultCSVIsValid(t *testing.T) {
+	t.Parallel()
+
+	levelsCSV, sheetCSV := loader.DefaultCSV()
+	levels, err := loader.ParseLevels(bytes.NewReader(levelsCSV))
+	if err != nil {
+		t.Fatalf("ParseLevels: %v", err)
+	}
+	sheet, err := loader.ParseSheetMusic(bytes.NewReader(sheetCSV))
+	if err != nil {
+		t.Fatalf("ParseSheetMusic: %v", err)
+	}
+	tracks := []models.SheetMusic{{
+		TrackID:       models.DefaultTrackID,
+		Tempo:         loader.DefaultTempo,
+		TimeSignature: loader.DefaultTimeSignature,
+		Notes:         sheet,
+	}}
+	if _, err = config.NewGameConfig(0, levels, tracks); err != nil {
+		t.Fatalf("NewGameConfig: %v", err)
+	}
+}
+
+func TestParseSheetMusicChart(t *testing.T) {
+	t.Parallel()
+
+	notes, err := loader.ParseSheetMusic(strings.NewReader(
+		"pitch,beat,duration,type\nC4,0,0.5,\nC4+E4+G4,0.5,1.5,\nG4,2,4,hold\n"))
+	if err != nil {
+		t.Fatalf("ParseSheetMusic: %v", err)
+	}
+	want := []models.NoteType{models.NoteTap, models.NoteChord, models.NoteHold}
+	if len(notes) != len(want) {
+		t.Fatalf("notes: got %+v", notes)
+	}
+	for i := range want {
+		if notes[i].Type != want[i] {
+			t.Errorf("note %d type: got %q, want %q", i, notes[i].Type, want[i])
+		}
+	}
+	if notes[1].Label() != "C4+E4+G4" || notes[2].Beat != 2 || notes[2].Duration != 4 {
+		t.Errorf("notes: got %+v", notes)
+	}
+
+	formatted, err := loader.FormatSheetMusic(notes)
+	if err != nil {
+		t.Fatalf("FormatSheetMusic: %v", err)
+	}
+	again, err := loader.ParseSheetMusic(bytes.NewReader(formatted))
+	if err != nil || len(again) != len(notes) || again[1].Label() != notes[1].Label() {
+		t.Errorf("round trip: got %+v, %v", again, err)
+	}
+
+	legacy, err := loader.ParseSheetMusic(strings.NewReader("C4\nD4\n"))
+	if err != nil || len(legacy) != 2 || legacy[1].Beat != 1 || legacy[1].Type != models.NoteTap {
+		t.Errorf("legacy sheet: got %+v, %v", legacy, err)
+	}
+
+	if _, err = loader.ParseSheetMusic(strings.NewReader("pitch,beat,duration,type\nC4,0,1,slide\n")); err == nil {
+		t.Error("unknown note type accepted")
+	}
+}
+
+func TestParseMIDI(t *testing.T) {
+	t.Parallel()
+
+	// 480 ticks per beat: a one-beat C4, a C major chord, then A4 held for two beats.
+	track := []byte{
+		0x00, 0x90, 60, 100, 0x83, 0x60, 0x80, 60, 0,
+		0x00, 0x90, 60, 100, 0x00, 64, 100, 0x00, 67, 100,
+		0x83, 0x60, 60, 0, 0x00, 64, 0, 0x00, 67, 0,
+		0x00, 0x99, 36, 100, 0x00, 36, 0,
+		0x00, 0x90, 69, 100, 0x87, 0x40, 69, 0,
+		0x00, 0xFF, 0x2F, 0x00,
+	}
+	notes, err := loader.ParseMIDI(bytes.NewReader(smf(0, track)))
+	if err != nil {
+		t.Fatalf("ParseMIDI: %v", err)
+	}
+	want := []models.Note{
+		{Pitches: []string{"C4"}, Beat: 0, Duration: 1, Type: models.NoteTap},
+		{Pitches: []string{"C4", "E4", "G4"}, Beat: 1, Duration: 1, Type: models.NoteChord},
+		{Pitches: []string{"A4"}, Beat: 2, Duration: 2, Type: models.NoteHold},
+	}
+	if len(notes) != len(want) {
+		t.Fatalf("notes: got %+v", notes)
+	}
+	for i := range want {
+		got := notes[i]
+		if got.Label() != want[i].Label() || got.Beat != want[i].Beat ||
+			got.Duration != want[i].Duration || got.Type != want[i].Type {
+			t.Errorf("note %d: got %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	if _, err = loader.ParseMIDI(strings.NewReader("level start,level end")); err == nil {
+		t.Error("csv accepted as midi")
+	}
+	if !loader.IsMIDI("https://example.com/sponsor.MID?raw=1") || loader.IsMIDI("file:///srv/sheet.csv") {
+		t.Error("IsMIDI")
+	}
+}
+
+func TestImportMIDIOptions(t *testing.T) {
+	t.Parallel()
+
+	// A type 1 file: a conductor track at 96 bpm in 3/4, then a named melody and a bass line.
+	conductor := []byte{
+		0x00, 0xFF, 0x51, 0x03, 0x09, 0x89, 0x68,
+		0x00, 0xFF, 0x58, 0x04, 0x03, 0x02, 0x18, 0x08,
+		0x00, 0xFF, 0x2F, 0x00,
+	}
+	melody := []byte{
+		0x00, 0xFF, 0x03, 0x06, 'M', 'e', 'l', 'o', 'd', 'y',
+		0x00, 0x90, 60, 100, 0x83, 0x56, 0x80, 60, 0,
+		0x0F, 0x90, 62, 100, 0x83, 0x60, 0x80, 62, 0,
+		0x00, 0xFF, 0x2F, 0x00,
+	}
+	bass := []byte{0x00, 0x91, 36, 100, 0x8F, 0x00, 0x81, 36, 0, 0x00, 0xFF, 0x2F, 0x00}
+	file := smf(1, conductor, melody, bass)
+
+	tracks, err := loader.ListMIDITracks(bytes.NewReader(file))
+	if err != nil {
+		t.Fatalf("ListMIDITracks: %v", err)
+	}
+	if len(tracks) != 3 || tracks[1].Name != "Melody" || tracks[1].Notes != 2 || tracks[2].Notes != 1 {
+		t.Errorf("tracks: got %+v", tracks)
+	}
+
+	chart, err := loader.ImportMIDI(bytes.NewReader(file), loader.MIDIOptions{Track: 1, Quantize: 4, Transpose: 2})
+	if err != nil {
+		t.Fatalf("ImportMIDI: %v", err)
+	}
+	if chart.Tempo != 96 || chart.TimeSignature != "3/4" {
+		t.Errorf("tempo: got %d %s", chart.Tempo, chart.TimeSignature)
+	}
+	// The second note starts 15 ticks late and snaps back onto beat 1.
+	if len(chart.Notes) != 2 || chart.Notes[0].Label() != "D4" || chart.Notes[1].Label() != "E4" ||
+		chart.Notes[1].Beat != 1 || chart.Notes[0].Duration != 1 {
+		t.Errorf("notes: got %+v", chart.Notes)
+	}
+
+	if _, err = loader.ImportMIDI(bytes.NewReader(file), loader.MIDIOptions{Track: 3}); err == nil {
+		t.Error("missing track accepted")
+	}
+	if _, err = loader.ImportMIDI(bytes.NewReader(file), loader.MIDIOptions{Track: 0}); err == nil {
+		t.Error("track without notes accepted")
+	}
+	if _, err = loader.ImportMIDI(bytes.NewReader(file), loader.MIDIOptions{Track: 2, Transpose: -30}); err == nil {
+		t.Error("key transposed below C0 accepted")
+	}
+}
+
+func TestParseMIDILegato(t *testing.T) {
+	t.Parallel()
+
+	// 96 ticks per beat: C4 from tick 7 to 30 overlaps D4, which starts at tick 20. Converting the
+	// clamped end to beats separately used to overshoot D4's beat by one ulp.
+	track := []byte{
+		0x07, 0x90, 60, 100,
+		0x0D, 0x90, 62, 100,
+		0x0A, 0x80, 60, 0,
+		0x40, 0x80, 62, 0,
+		0x00, 0xFF, 0x2F, 0x00,
+	}
+	file := smf(0, track)
+	file[12], file[13] = 0x00, 0x60
+
+	notes, err := loader.ParseMIDI(bytes.NewReader(file))
+	if err != nil {
+		t.Fatalf("ParseMIDI: %v", err)
+	}
+	if len(notes) != 2 || notes[0].End() > notes[1].Beat {
+		t.Fatalf("notes: got %+v", notes)
+	}
+	levels := []models.Level{{StartLevel: 1, EndLevel: 1, Speed: 60, Notes: 2}}
+	tracks := []models.SheetMusic{{
+		TrackID:       models.DefaultTrackID,
+		Tempo:         loader.DefaultTempo,
+		TimeSignature: loader.DefaultTimeSignature,
+		Notes:         notes,
+	}}
+	if _, err = config.NewGameConfig(0, levels, tracks); err != nil {
+		t.Errorf("NewGameConfig: %v", err)
+	}
+}
+
+// smf builds a Standard MIDI File with 480 ticks per quarter note from raw track chunk bodies.
+func smf(format byte, tracks ...[]byte) []byte {
+	out := []byte{'M', 'T', 'h', 'd', 0, 0, 0, 6, 0, format, 0, byte(len(tracks)), 0x01, 0xE0}
+	for _, track := range tracks {
+		out = append(out, 'M', 'T', 'r', 'k')
+		out = append(out, byte(len(track)>>24), byte(len(track)>>16), byte(len(track)>>8), byte(len(track)))
+		out = append(out, track...)
+	}
+	return out
+}
+
+func TestParseLevelsTrackColumn(t *testing.T) {
+	t.Parallel()
+
+	levels, err := loader.ParseLevels(strings.NewReader(
+		"level start,level end,speed,notes,track\n1,5,60,4,\n6,8,80,6, sponsor \n"))
+	if err != nil {
+		t.Fatalf("ParseLevels: %v", err)
+	}
+	if levels[0].Track != "" || levels[1].Track != "sponsor" {
+		t.Errorf("tracks: got %q, %q", levels[0].Track, levels[1].Track)
+	}
+}
+
+func TestParseTrackManifest(t *testing.T) {
+	t.Parallel()
+
+	tracks, err := loader.ParseTrackManifest(strings.NewReader(
+		"track,tempo,time signature,sheet\nsponsor,96,3/4,file:///srv/sponsor.csv\n"))
+	if err != nil {
+		t.Fatalf("ParseTrackManifest: %v", err)
+	}
+	want := loader.TrackSource{TrackID: "sponsor", Tempo: 96, TimeSignature: "3/4", SheetURL: "file:///srv/sponsor.csv"}
+	if len(tracks) != 1 || tracks[0] != want {
+		t.Errorf("tracks: got %+v", tracks)
+	}
+
+	if _, err = loader.ParseTrackManifest(strings.NewReader("track,tempo,time signature,sheet\nx,fast,4/4,a\n")); err == nil {
+		t.Error("non-numeric tempo accepted")
+	}
+}
+
+func TestFileURL(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "levels.csv")
+	if err := os.WriteFile(path, []byte("level start,level end,speed,notes\n1,3,60,4\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	levels, err := loader.LoadLevels("file://" + path)
+	if err != nil {
+		t.Fatalf("LoadLevels: %v", err)
+	}
+	if len(levels) != 1 || levels[0].EndLevel != 3 {
+		t.Errorf("levels: got %+v", levels)
+	}
+	if _, err = loader.Fetch("file://" + path + ".missing"); err == nil {
+		t.Error("missing file accepted")
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "cache.json")
+	want := loader.CachedConfig{
+		Version:   4,
+		LevelsCSV: "levels",
+		Tracks:    []loader.CachedTrack{{TrackID: "default", Tempo: 120, TimeSignature: "4/4", SheetCSV: "sheet"}},
+	}
+	if err := loader.WriteCache(path, want); err != nil {
+		t.Fatalf("WriteCache: %v", err)
+	}
+	want.Version = 5
+	if err := loader.WriteCache(path, want); err != nil {
+		t.Fatalf("WriteCache overwrite: %v", err)
+	}
+
+	got, err := loader.ReadCache(path)
+	if err != nil {
+		t.Fatalf("ReadCache: %v", err)
+	}
+	if got.Version != 5 || got.LevelsCSV != "levels" || len(got.Tracks) != 1 || got.Tracks[0] != want.Tracks[0] {
+		t.Errorf("cache: got %+v", got)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temp files left behind: %d entries", len(entries))
+	}
+}
@@ -0,0 +1,454 @@
+package loader
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+)
+
+const (
+	midiChunkHeaderSize = 8
+	midiHeaderSize      = 6
+	midiMaxVarLenBytes  = 4
+	// midiPercussionChannel is General MIDI channel 10; drum hits have no pitch to play back.
+	midiPercussionChannel = 9
+	// midiLowestKey is C0. Lower keys would need a negative octave, which note names cannot express.
+	midiLowestKey  = 12
+	midiHighestKey = 127
+	// midiHoldBeats is the shortest single note imported as a hold instead of a tap.
+	midiHoldBeats = 2
+
+	midiStatusNoteOff       = 0x80
+	midiStatusNoteOn        = 0x90
+	midiStatusProgramChange = 0xC0
+	midiStatusChannelPress  = 0xD0
+	midiStatusSysEx         = 0xF0
+	midiStatusSysExEscape   = 0xF7
+	midiStatusMeta          = 0xFF
+	midiMetaTrackName       = 0x03
+	midiMetaEndOfTrack      = 0x2F
+	midiMetaSetTempo        = 0x51
+	midiMetaTimeSignature   = 0x58
+	midiTempoBytes          = 3
+	midiTimeSignatureBytes  = 2
+	microsecondsPerMinute   = 60_000_000
+
+	// AllMIDITracks makes ImportMIDI merge the notes of every track.
+	AllMIDITracks = -1
+)
+
+//nolint:gochecknoglobals // fixed lookup table
+var pitchClasses = [...]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// midiNote is one sounded key, in ticks from the start of its track.
+type midiNote struct {
+	start, end int64
+	key        int
+}
+
+// midiTrack is one parsed track chunk. tempo (microseconds per beat) and timeSignature are the
+// track's first set-tempo and time-signature events, zero if it has none.
+type midiTrack struct {
+	name          string
+	notes         []midiNote
+	tempo         int
+	timeSignature string
+}
+
+// MIDIOptions selects and reshapes the notes ImportMIDI reads.
+type MIDIOptions struct {
+	// Track is the index of the track chunk to import, counted from 0, or AllMIDITracks.
+	Track int
+	// Quantize snaps note starts and ends to 1/Quantize of a beat, e.g. 4 for sixteenth notes.
+	// Zero keeps the recorded timing.
+	Quantize int
+	// Transpose shifts every key by this many semitones.
+	Transpose int
+}
+
+// MIDIChart is a chart read from a MIDI file.
+type MIDIChart struct {
+	Notes []models.Note
+	// Tempo is the file's first tempo in beats per minute, or DefaultTempo if it sets none.
+	Tempo int
+	// TimeSignature is the file's first time signature, or DefaultTimeSignature if it sets none.
+	TimeSignature string
+}
+
+// MIDITrack describes one track chunk of a MIDI file, for picking MIDIOptions.Track.
+type MIDITrack struct {
+	Index int
+	Name  string
+	// Notes counts the track's pitched notes; percussion is not counted.
+	Notes int
+}
+
+// IsMIDI reports whether a sheet URL or path names a Standard MIDI File rather than a CSV.
+func IsMIDI(name string) bool {
+	if i := strings.IndexAny(name, "?#"); i >= 0 && strings.Contains(name, "://") {
+		name = name[:i]
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".mid", ".midi":
+		return true
+	default:
+		return false
+	}
+}
+
+// MIDIToSheetCSV converts a Standard MIDI File into the structured sheet music CSV, so configs
+// are always stored and audited in one format.
+func MIDIToSheetCSV(body []byte) ([]byte, error) {
+	notes, err := ParseMIDI(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return FormatSheetMusic(notes)
+}
+
+// ParseMIDI reads a Standard MIDI File (type 0 or 1) into a chart. Notes of every track except the
+// percussion channel are merged; keys that start on the same tick become a chord, and single keys
+// held for at least two beats become holds. Beats come from the file's ticks per quarter note,
+// so the chart does not depend on the tempo it was recorded at.
+func ParseMIDI(src io.Reader) ([]models.Note, error) {
+	chart, err := ImportMIDI(src, MIDIOptions{Track: AllMIDITracks})
+	if err != nil {
+		return nil, err
+	}
+	return chart.Notes, nil
+}
+
+// ImportMIDI reads a Standard MIDI File (type 0 or 1) like ParseMIDI, but only from the track
+// chosen in opts, with optional quantization and transposition. Tempo and time signature are
+// taken from any track, since type 1 files keep them in a separate conductor track.
+func ImportMIDI(src io.Reader, opts MIDIOptions) (MIDIChart, error) {
+	if opts.Quantize < 0 {
+		return MIDIChart{}, fmt.Errorf("invalid quantize %d", opts.Quantize)
+	}
+	body, err := readLimited(src, "midi file")
+	if err != nil {
+		return MIDIChart{}, err
+	}
+	division, tracks, err := parseSMF(body)
+	if err != nil {
+		return MIDIChart{}, err
+	}
+
+	chart := MIDIChart{Tempo: DefaultTempo, TimeSignature: DefaultTimeSignature}
+	if i := slices.IndexFunc(tracks, func(t midiTrack) bool { return t.tempo > 0 }); i >= 0 {
+		chart.Tempo = max(1, int(math.Round(float64(microsecondsPerMinute)/float64(tracks[i].tempo))))
+	}
+	if i := slices.IndexFunc(tracks, func(t midiTrack) bool { return t.timeSignature != "" }); i >= 0 {
+		chart.TimeSignature = tracks[i].timeSignature
+	}
+
+	var notes []midiNote
+	switch {
+	case opts.Track == AllMIDITracks:
+		for _, track := range tracks {
+			notes = append(notes, track.notes...)
+		}
+	case opts.Track >= 0 && opts.Track < len(tracks):
+		notes = tracks[opts.Track].notes
+	default:
+		return MIDIChart{}, fmt.Errorf("midi file has no track %d, it has %d", opts.Track, len(tracks))
+	}
+
+	if chart.Notes, err = midiChart(notes, division, opts); err != nil {
+		return MIDIChart{}, err
+	}
+	return chart, nil
+}
+
+// ListMIDITracks returns every track chunk of a Standard MIDI File with its name and note count.
+func ListMIDITracks(src io.Reader) ([]MIDITrack, error) {
+	body, err := readLimited(src, "midi file")
+	if err != nil {
+		return nil, err
+	}
+	_, tracks, err := parseSMF(body)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MIDITrack, len(tracks))
+	for i, track := range tracks {
+		out[i] = MIDITrack{Index: i, Name: track.name, Notes: len(track.notes)}
+	}
+	return out, nil
+}
+
+// parseSMF splits a Standard MIDI File into its tracks and returns its ticks per quarter note.
+func parseSMF(body []byte) (int, []midiTrack, error) {
+	chunkType, header, rest, err := readMIDIChunk(body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if chunkType != "MThd" || len(header) < midiHeaderSize {
+		return 0, nil, errors.New("not a standard midi file")
+	}
+	format := binary.BigEndian.Uint16(header[0:2])
+	trackCount := int(binary.BigEndian.Uint16(header[2:4]))
+	division := binary.BigEndian.Uint16(header[4:6])
+	if format > 1 {
+		return 0, nil, fmt.Errorf("unsupported midi format %d, want 0 or 1", format)
+	}
+	if division&0x8000 != 0 || division == 0 {
+		return 0, nil, errors.New("unsupported midi time division, want ticks per quarter note")
+	}
+
+	tracks := make([]midiTrack, 0, trackCount)
+	for len(rest) > 0 && len(tracks) < trackCount {
+		var data []byte
+		if chunkType, data, rest, err = readMIDIChunk(rest); err != nil {
+			return 0, nil, err
+		}
+		if chunkType != "MTrk" {
+			continue
+		}
+		track, trackErr := parseMIDITrack(data)
+		if trackErr != nil {
+			return 0, nil, fmt.Errorf("midi track %d: %w", len(tracks), trackErr)
+		}
+		tracks = append(tracks, track)
+	}
+	if len(tracks) != trackCount {
+		return 0, nil, fmt.Errorf("midi file has %d tracks, header declares %d", len(tracks), trackCount)
+	}
+	return int(division), tracks, nil
+}
+
+func readMIDIChunk(body []byte) (string, []byte, []byte, error) {
+	if len(body) < midiChunkHeaderSize {
+		return "", nil, nil, errors.New("truncated midi chunk header")
+	}
+	size := binary.BigEndian.Uint32(body[4:8])
+	if uint64(size) > uint64(len(body)-midiChunkHeaderSize) {
+		return "", nil, nil, errors.New("truncated midi chunk")
+	}
+	end := midiChunkHeaderSize + int(size)
+	return string(body[:4]), body[midiChunkHeaderSize:end], body[end:], nil
+}
+
+// parseMIDITrack pairs note-on and note-off events of one track chunk and picks up its name,
+// tempo and time signature. Keys still sounding when the track ends are closed at its last tick.
+func parseMIDITrack(data []byte) (midiTrack, error) {
+	var (
+		track  midiTrack
+		open   = map[int][]int64{}
+		tick   int64
+		status byte
+		pos    int
+	)
+
+	for pos < len(data) {
+		delta, n, err := readVarLen(data[pos:])
+		if err != nil {
+			return midiTrack{}, err
+		}
+		pos += n
+		tick += int64(delta)
+		if pos >= len(data) {
+			return midiTrack{}, errors.New("truncated midi event")
+		}
+		if data[pos]&0x80 != 0 {
+			status = data[pos]
+			pos++
+		} else if status == 0 {
+			return midiTrack{}, errors.New("midi running status without a previous event")
+		}
+
+		switch {
+		case status == midiStatusMeta:
+			if pos >= len(data) {
+				return midiTrack{}, errors.New("truncated midi meta event")
+			}
+			metaType := data[pos]
+			var payload []byte
+			if payload, pos, err = readMIDIData(data, pos+1); err != nil {
+				return midiTrack{}, err
+			}
+			status = 0
+			if metaType == midiMetaEndOfTrack {
+				pos = len(data)
+			}
+			track.readMeta(metaType, payload)
+		case status == midiStatusSysEx || status == midiStatusSysExEscape:
+			if _, pos, err = readMIDIData(data, pos); err != nil {
+				return midiTrack{}, err
+			}
+			status = 0
+		case status > midiStatusSysEx:
+			return midiTrack{}, fmt.Errorf("unsupported midi status 0x%02X in track chunk", status)
+		default:
+			kind, channel := status&0xF0, int(status&0x0F)
+			size := 2
+			if kind == midiStatusProgramChange || kind == midiStatusChannelPress {
+				size = 1
+			}
+			if pos+size > len(data) {
+				return midiTrack{}, errors.New("truncated midi channel event")
+			}
+			key, velocity := int(data[pos]), data[pos+size-1]
+			pos += size
+			if channel == midiPercussionChannel || (kind != midiStatusNoteOn && kind != midiStatusNoteOff) {
+				continue
+			}
+
+			slot := channel<<7 | key
+			if kind == midiStatusNoteOn && velocity > 0 {
+				open[slot] = append(open[slot], tick)
+				continue
+			}
+			// A note-on with velocity 0 is a note-off.
+			if starts := open[slot]; len(starts) > 0 {
+				track.notes = append(track.notes, midiNote{start: starts[0], end: tick, key: key})
+				open[slot] = starts[1:]
+			}
+		}
+	}
+
+	for slot, starts := range open {
+		for _, start := range starts {
+			track.notes = append(track.notes, midiNote{start: start, end: tick, key: slot & 0x7F})
+		}
+	}
+	return track, nil
+}
+
+// readMeta keeps the first track name, tempo and time signature of a track.
+func (t *midiTrack) readMeta(metaType byte, payload []byte) {
+	switch {
+	case metaType == midiMetaTrackName && t.name == "":
+		t.name = strings.TrimSpace(string(payload))
+	case metaType == midiMetaSetTempo && t.tempo == 0 && len(payload) == midiTempoBytes:
+		t.tempo = int(payload[0])<<16 | int(payload[1])<<8 | int(payload[2])
+	case metaType == midiMetaTimeSignature && t.timeSignature == "" && len(payload) >= midiTimeSignatureBytes:
+		t.timeSignature = fmt.Sprintf("%d/%d", payload[0], 1<<payload[1])
+	}
+}
+
+// readMIDIData reads a length-prefixed meta or sysex payload starting at pos and returns it with
+// the position after it.
+func readMIDIData(data []byte, pos int) ([]byte, int, error) {
+	length, n, err := readVarLen(data[pos:])
+	if err != nil {
+		return nil, 0, err
+	}
+	pos += n
+	if length > uint32(len(data)-pos) {
+		return nil, 0, errors.New("truncated midi event data")
+	}
+	end := pos + int(length)
+	return data[pos:end], end, nil
+}
+
+// readVarLen decodes a MIDI variable-length quantity and returns it with the bytes it used.
+func readVarLen(data []byte) (uint32, int, error) {
+	var value uint32
+	for i := 0; i < midiMaxVarLenBytes && i < len(data); i++ {
+		value = value<<7 | uint32(data[i]&0x7F)
+		if data[i]&0x80 == 0 {
+			return value, i + 1, nil
+		}
+	}
+	return 0, 0, errors.New("invalid midi variable-length quantity")
+}
+
+// midiChart groups notes by start into taps, holds and chords after transposing and quantizing
+// them. A note never lasts past the start of the next one, so legato playing in the DAW does not
+// produce overlapping notes. Clamping happens on whole ticks (or grid steps when quantizing) so
+// a clamped note ends exactly where the next one starts once converted to beats.
+func midiChart(notes []midiNote, division int, opts MIDIOptions) ([]models.Note, error) {
+	if len(notes) == 0 {
+		return nil, errors.New("midi track has no notes")
+	}
+
+	// unitsPerBeat is the resolution positions are kept in: ticks, or grid steps when quantizing.
+	unitsPerBeat := int64(division)
+	if opts.Quantize > 0 {
+		unitsPerBeat = int64(opts.Quantize)
+	}
+	spans := make([]midiNote, len(notes))
+	for i, n := range notes {
+		key := n.key + opts.Transpose
+		if key < midiLowestKey || key > midiHighestKey {
+			return nil, fmt.Errorf("midi key %d transposed by %d is outside C0-G9", n.key, opts.Transpose)
+		}
+		start, end := n.start, n.end
+		if opts.Quantize > 0 {
+			start, end = quantizeTick(start, division, opts.Quantize), quantizeTick(end, division, opts.Quantize)
+		}
+		spans[i] = midiNote{start: start, end: end, key: key}
+	}
+	slices.SortFunc(spans, func(a, b midiNote) int {
+		if a.start != b.start {
+			return cmp.Compare(a.start, b.start)
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+
+	units := float64(unitsPerBeat)
+	chart := []models.Note{}
+	for i := 0; i < len(spans); {
+		start, end := spans[i].start, spans[i].end
+		var pitches []string
+		for ; i < len(spans) && spans[i].start == start; i++ {
+			name := pitchName(spans[i].key)
+			if !slices.Contains(pitches, name) {
+				pitches = append(pitches, name)
+			}
+			end = max(end, spans[i].end)
+		}
+		// Zero-length notes still take one unit so every note has a duration.
+		end = max(end, start+1)
+		if i < len(spans) {
+			end = min(end, spans[i].start)
+		}
+
+		beat := float64(start) / units
+		note := models.Note{
+			Pitches:  pitches,
+			Beat:     beat,
+			Duration: beatsBetween(beat, float64(end)/units),
+			Type:     models.NoteTap,
+		}
+		switch {
+		case len(pitches) > 1:
+			note.Type = models.NoteChord
+		case end-start >= midiHoldBeats*unitsPerBeat:
+			note.Type = models.NoteHold
+		}
+		chart = append(chart, note)
+	}
+	return chart, nil
+}
+
+// beatsBetween returns a duration that, added to beat, does not pass end. end-beat alone can
+// round up by one ulp, which would make a clamped note overlap the one after it.
+func beatsBetween(beat, end float64) float64 {
+	duration := end - beat
+	for duration > 0 && beat+duration > end {
+		duration = math.Nextafter(duration, 0)
+	}
+	return duration
+}
+
+// quantizeTick rounds a tick to the nearest 1/quantize of a beat and returns it in grid steps.
+func quantizeTick(tick int64, division, quantize int) int64 {
+	return int64(math.Round(float64(tick) * float64(quantize) / float64(division)))
+}
+
+// pitchName spells a MIDI key with sharps, where key 60 is C4.
+func pitchName(key int) string {
+	return fmt.Sprintf("%s%d", pitchClasses[key%len(pitchClasses)], key/len(pitchClasses)-1)
+}
@@ -0,0 +1,95 @@
+package loader
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	manifestColumns = 4
+	// DefaultTempo is the tempo of a sheet-music file configured without a track manifest.
+	DefaultTempo = 120
+	// DefaultTimeSignature is the time signature of a sheet-music file configured without a manifest.
+	DefaultTimeSignature = "4/4"
+)
+
+// TrackSource is one row of a track manifest: a sheet-music file and how it is played.
+type TrackSource struct {
+	TrackID       string
+	Tempo         int
+	TimeSignature string
+	// SheetURL is read with Fetch (http, https or file://).
+	SheetURL string
+}
+
+// LoadTrackManifest reads a track manifest from a CSV URL (http, https or file://).
+func LoadTrackManifest(csvURL string) ([]TrackSource, error) {
+	f, err := fetchCSV(csvURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch track manifest csv: %w", err)
+	}
+	defer f.Close()
+
+	return ParseTrackManifest(f)
+}
+
+// ParseTrackManifest reads a CSV with a "track,tempo,time signature,sheet" header. Each row names a
+// sheet-music track that level ranges can reference, and the URL of its sheet music CSV or MIDI file.
+// Tempo and time signature are checked when the config is built, not here.
+func ParseTrackManifest(src io.Reader) ([]TrackSource, error) {
+	r := csv.NewReader(src)
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if err = validateManifestHeader(header); err != nil {
+		return nil, err
+	}
+
+	tracks := []TrackSource{}
+	for {
+		row, readErr := r.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read row: %w", readErr)
+		}
+
+		tempo, parseErr := parsePositiveInt(row[1], "tempo")
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		track := TrackSource{
+			TrackID:       strings.TrimSpace(row[0]),
+			Tempo:         tempo,
+			TimeSignature: strings.TrimSpace(row[2]),
+			SheetURL:      strings.TrimSpace(row[3]),
+		}
+		if track.TrackID == "" || track.SheetURL == "" {
+			return nil, fmt.Errorf("track manifest row %d: track and sheet are required", len(tracks)+1)
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
+func validateManifestHeader(header []string) error {
+	if len(header) != manifestColumns {
+		return fmt.Errorf("track manifest csv has %d columns, want exactly %d", len(header), manifestColumns)
+	}
+
+	expected := []string{"track", "tempo", "time signature", "sheet"}
+	for i := range expected {
+		actual := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+		if actual != expected[i] {
+			return fmt.Errorf("invalid track manifest header at column %d: got %q, want %q", i+1, header[i], expected[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
+	"github.com/sitcon-tw/2026-game/pkg/res"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+)
+
+// AdminAuth verifies the admin_token cookie against unexpired admin sessions.
+// On success, it injects the *models.Admin into request context under adminContextKey.
+func AdminAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler) http.Handler {
+	authTracer := otel.Tracer("github.com/sitcon-tw/2026-game/auth")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := authTracer.Start(r.Context(), "auth.admin")
+			defer span.End()
+
+			cookie, err := r.Cookie("admin_token")
+			if err != nil || cookie.Value == "" {
+				span.SetAttributes(attribute.Bool("auth.authenticated", false))
+				res.Fail(w, r, http.StatusUnauthorized, errors.New("missing admin session"), "unauthorized")
+				return
+			}
+
+			tx, err := repo.StartTransaction(ctx)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "start tx failed")
+				logger.Error("admin auth: start tx failed", zap.Error(err))
+				res.Fail(w, r, http.StatusInternalServerError, err, "internal error")
+				return
+			}
+			defer repo.DeferRollback(ctx, tx)
+
+			admin, err := repo.GetAdminBySessionHash(ctx, tx, helpers.HashSecret(cookie.Value), time.Now().UTC())
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					span.SetAttributes(attribute.Bool("auth.authenticated", false))
+					res.Fail(w, r, http.StatusUnauthorized, errors.New("invalid admin session"), "unauthorized")
+					return
+				}
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "fetch admin failed")
+				logger.Error("admin auth: fetch admin failed", zap.Error(err))
+				res.Fail(w, r, http.StatusInternalServerError, err, "internal error")
+				return
+			}
+
+			if err = repo.CommitTransaction(ctx, tx); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "commit tx failed")
+				res.Fail(w, r, http.StatusInternalServerError, err, "internal error")
+				return
+			}
+
+			span.SetAttributes(
+				attribute.Bool("auth.authenticated", true),
+				attribute.String("auth.admin_id", admin.ID),
+				attribute.String("auth.admin_role", string(admin.Role)),
+			)
+			next.ServeHTTP(w, r.WithContext(contextWithAdmin(ctx, admin)))
+		})
+	}
+}
+
+// RequireAdminPermission rejects admins whose role does not grant p. Use it after AdminAuth.
+func RequireAdminPermission(p models.AdminPermission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			admin, ok := AdminFromContext(r.Context())
+			if !ok || admin == nil {
+				res.Fail(w, r, http.StatusUnauthorized, errors.New("missing admin session"), "unauthorized")
+				return
+			}
+			if !admin.Role.Can(p) {
+				res.Fail(w, r, http.StatusForbidden, errors.New("admin role lacks "+string(p)), "forbidden")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AdminFromContext retrieves the authenticated admin set by AdminAuth middleware.
+func AdminFromContext(ctx context.Context) (*models.Admin, bool) {
+	admin, ok := ctx.Value(adminContextKey).(*models.Admin)
+	return admin, ok
+}
+
+func contextWithAdmin(ctx context.Context, admin *models.Admin) context.Context {
+	return context.WithValue(ctx, adminContextKey, admin)
+}
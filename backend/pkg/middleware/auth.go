@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/sitcon-tw/2026-game/internal/models"
 	"github.com/sitcon-tw/2026-game/internal/repository"
+	"github.com/sitcon-tw/2026-game/pkg/helpers"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -85,8 +88,63 @@ func Auth(repo repository.Repository, logger *zap.Logger) func(http.Handler) htt
 	}
 }
 
-// StaffAuth verifies the staff_token cookie against the staff table.
-// On success, it injects the *models.Staff into request context under staffContextKey.
+// OptionalAuth is Auth for endpoints that also serve visitors who are not logged in.
+// A missing or unknown token cookie passes the request through without a user in context.
+func OptionalAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler) http.Handler {
+	authTracer := otel.Tracer("github.com/sitcon-tw/2026-game/auth")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie("token")
+			if err != nil || cookie.Value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, span := authTracer.Start(r.Context(), "auth.user.optional")
+			defer span.End()
+
+			tx, err := repo.StartTransaction(ctx)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "start tx failed")
+				logger.Error("optional auth: start tx failed", zap.Error(err))
+				res.Fail(w, r, http.StatusInternalServerError, err, "internal error")
+				return
+			}
+			defer repo.DeferRollback(ctx, tx)
+
+			user, err := repo.GetUserByToken(ctx, tx, cookie.Value)
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "fetch user failed")
+				logger.Error("optional auth: fetch user failed", zap.Error(err))
+				res.Fail(w, r, http.StatusInternalServerError, err, "internal error")
+				return
+			}
+			if err = repo.CommitTransaction(ctx, tx); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "commit tx failed")
+				res.Fail(w, r, http.StatusInternalServerError, err, "internal error")
+				return
+			}
+
+			if user == nil {
+				span.SetAttributes(attribute.Bool("auth.authenticated", false))
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			span.SetAttributes(
+				attribute.Bool("auth.authenticated", true),
+				attribute.String("auth.user_id", user.ID),
+			)
+			next.ServeHTTP(w, r.WithContext(contextWithUser(ctx, user)))
+		})
+	}
+}
+
+// StaffAuth verifies the staff_token cookie against unexpired staff sessions and records the
+// session's last use. On success, it injects the *models.Staff into request context under staffContextKey.
 func StaffAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler) http.Handler {
 	authTracer := otel.Tracer("github.com/sitcon-tw/2026-game/auth")
 
@@ -101,7 +159,8 @@ func StaffAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler
 				res.Fail(w, r, http.StatusUnauthorized, err, "unauthorized")
 				return
 			}
-			token := cookie.Value
+			tokenHash := helpers.HashSecret(cookie.Value)
+			now := time.Now().UTC()
 
 			tx, err := repo.StartTransaction(ctx)
 			if err != nil {
@@ -113,7 +172,7 @@ func StaffAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler
 			}
 			defer repo.DeferRollback(ctx, tx)
 
-			staff, err := repo.GetStaffByToken(ctx, tx, token)
+			staff, lastSeen, err := repo.GetStaffBySessionHash(ctx, tx, tokenHash, now)
 			if err != nil {
 				if errors.Is(err, repository.ErrNotFound) {
 					span.SetAttributes(attribute.Bool("auth.authenticated", false))
@@ -131,6 +190,13 @@ func StaffAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler
 				res.Fail(w, r, http.StatusUnauthorized, nil, "unauthorized")
 				return
 			}
+			if err = touchSession(ctx, tx, tokenHash, lastSeen, now, repo.TouchStaffSession); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "touch session failed")
+				logger.Error("staff auth: touch session failed", zap.Error(err))
+				res.Fail(w, r, http.StatusInternalServerError, err, "internal error")
+				return
+			}
 
 			err = repo.CommitTransaction(ctx, tx)
 			if err != nil {
@@ -150,7 +216,8 @@ func StaffAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler
 	}
 }
 
-// BoothAuth verifies the booth_token cookie against activities that can scan users.
+// BoothAuth verifies the booth_token cookie against unexpired booth sessions of activities that
+// can scan users, and records the session's last use.
 //
 //nolint:gocognit // multiple early exits keep middleware readable
 func BoothAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler) http.Handler {
@@ -178,7 +245,9 @@ func BoothAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler
 			}
 			defer repo.DeferRollback(ctx, tx)
 
-			booth, err := repo.GetActivityByToken(ctx, tx, cookie.Value)
+			tokenHash := helpers.HashSecret(cookie.Value)
+			now := time.Now().UTC()
+			booth, lastSeen, err := repo.GetActivityBySessionHash(ctx, tx, tokenHash, now)
 			if err != nil {
 				if errors.Is(err, repository.ErrNotFound) {
 					span.SetAttributes(attribute.Bool("auth.authenticated", false))
@@ -202,6 +271,13 @@ func BoothAuth(repo repository.Repository, logger *zap.Logger) func(http.Handler
 				res.Fail(w, r, http.StatusUnauthorized, nil, "unauthorized")
 				return
 			}
+			if err = touchSession(ctx, tx, tokenHash, lastSeen, now, repo.TouchBoothSession); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "touch session failed")
+				logger.Error("booth auth: touch session failed", zap.Error(err))
+				res.Fail(w, r, http.StatusInternalServerError, err, "internal error")
+				return
+			}
 
 			err = repo.CommitTransaction(ctx, tx)
 			if err != nil {
@@ -250,3 +326,18 @@ func StaffFromContext(ctx context.Context) (*models.Staff, bool) {
 func contextWithStaff(ctx context.Context, staff *models.Staff) context.Context {
 	return context.WithValue(ctx, staffContextKey, staff)
 }
+
+// touchSession records a session's use through touch, skipping the write while lastSeen is
+// newer than repository.DeviceSessionTouchInterval.
+func touchSession(
+	ctx context.Context,
+	tx pgx.Tx,
+	tokenHash string,
+	lastSeen, now time.Time,
+	touch func(context.Context, pgx.Tx, string, time.Time) error,
+) error {
+	if now.Sub(lastSeen) < repository.DeviceSessionTouchInterval {
+		return nil
+	}
+	return touch(ctx, tx, tokenHash, now)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/sitcon-tw/2026-game/pkg/res"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -21,6 +22,11 @@ func (w *wrapperWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 }
 
+// Unwrap lets http.ResponseController reach Flush and deadline control on the underlying writer.
+func (w *wrapperWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // Logger is a middleware that logs HTTP requests using the provided zap.Logger.
 func Logger(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -48,6 +54,9 @@ func Logger(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.Duration("latency", time.Since(start)),
 				zap.String("user_agent", r.UserAgent()),
 			}
+			if reqID := chimiddleware.GetReqID(r.Context()); reqID != "" {
+				fields = append(fields, zap.String("request_id", reqID))
+			}
 
 			spanCtx := trace.SpanContextFromContext(r.Context())
 			if spanCtx.IsValid() {
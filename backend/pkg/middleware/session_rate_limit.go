@@ -38,8 +38,8 @@ func sessionRateLimitKey(r *http.Request) (string, error) {
 		return fmt.Sprintf("booth:%s", booth.ID), nil
 	}
 
-	if AdminFromContext(r.Context()) {
-		return "admin", nil
+	if admin, ok := AdminFromContext(r.Context()); ok && admin != nil {
+		return fmt.Sprintf("admin:%s", admin.ID), nil
 	}
 
 	return "", errors.New("missing authenticated session for rate limit")
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/sitcon-tw/2026-game/internal/models"
+	"github.com/sitcon-tw/2026-game/pkg/config"
+	"github.com/sitcon-tw/2026-game/pkg/loader"
+)
+
+const (
+	defaultInputPath     = "data/sheet_music.mid"
+	defaultLevelsOutput  = "data/levels.csv"
+	defaultSheetOutput   = "data/sheet_music.csv"
+	defaultNotesPerLevel = 8
+	defaultStartLevel    = 1
+	secondsPerMinute     = 60
+)
+
+type options struct {
+	inputPath   string
+	levelsPath  string
+	sheetPath   string
+	trackID     string
+	startLevel  int
+	levels      int
+	notes       int
+	speed       int
+	listTracks  bool
+	midiOptions loader.MIDIOptions
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.inputPath, "in", defaultInputPath, "path to source MIDI file (SMF type 0 or 1)")
+	flag.StringVar(&opts.levelsPath, "levels-out", defaultLevelsOutput, "path to destination level CSV")
+	flag.StringVar(&opts.sheetPath, "sheet-out", defaultSheetOutput, "path to destination sheet music CSV")
+	flag.BoolVar(&opts.listTracks, "list", false, "list the MIDI tracks and exit")
+	flag.IntVar(&opts.midiOptions.Track, "track", loader.AllMIDITracks, "MIDI track index to import, -1 merges all tracks")
+	flag.IntVar(&opts.midiOptions.Quantize, "quantize", 0, "snap notes to 1/N beat, e.g. 4 for sixteenths; 0 keeps the recorded timing")
+	flag.IntVar(&opts.midiOptions.Transpose, "transpose", 0, "shift every note by this many semitones")
+	flag.StringVar(&opts.trackID, "track-id", "", "sheet-music track ID written to the level CSV track column; empty uses the default track")
+	flag.IntVar(&opts.startLevel, "start-level", defaultStartLevel, "first level of the generated range")
+	flag.IntVar(&opts.levels, "levels", 0, "number of levels to generate; 0 plays the whole chart once")
+	flag.IntVar(&opts.notes, "notes", defaultNotesPerLevel, "notes per level")
+	flag.IntVar(&opts.speed, "speed", 0, "level speed in beats per minute; 0 uses the MIDI tempo")
+	flag.Parse()
+
+	if err := run(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "convert midi sheet failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(opts options) error {
+	src, err := os.ReadFile(opts.inputPath)
+	if err != nil {
+		return fmt.Errorf("read input: %w", err)
+	}
+	if opts.listTracks {
+		return listTracks(src)
+	}
+	if opts.notes <= 0 || opts.startLevel <= 0 || opts.levels < 0 || opts.speed < 0 {
+		return errors.New("notes and start-level must be positive, levels and speed must not be negative")
+	}
+
+	chart, err := loader.ImportMIDI(bytes.NewReader(src), opts.midiOptions)
+	if err != nil {
+		return fmt.Errorf("import midi: %w", err)
+	}
+	speed := opts.speed
+	if speed == 0 {
+		speed = chart.Tempo
+	}
+	levelCount := opts.levels
+	if levelCount == 0 {
+		levelCount = (len(chart.Notes) + opts.notes - 1) / opts.notes
+	}
+
+	sheetCSV, err := loader.FormatSheetMusic(chart.Notes)
+	if err != nil {
+		return fmt.Errorf("encode sheet csv: %w", err)
+	}
+	levelsCSV, err := formatLevels(opts, levelCount, speed)
+	if err != nil {
+		return fmt.Errorf("encode level csv: %w", err)
+	}
+
+	cfg, err := validate(levelsCSV, sheetCSV, opts.trackID, chart)
+	if err != nil {
+		return fmt.Errorf("validate output: %w", err)
+	}
+
+	if err = os.WriteFile(opts.sheetPath, sheetCSV, 0o644); err != nil {
+		return fmt.Errorf("write sheet csv: %w", err)
+	}
+	if err = os.WriteFile(opts.levelsPath, levelsCSV, 0o644); err != nil {
+		return fmt.Errorf("write level csv: %w", err)
+	}
+
+	fmt.Fprintf(os.Stdout, "wrote %s (%d notes, tempo %d, %s)\n",
+		opts.sheetPath, len(chart.Notes), chart.Tempo, chart.TimeSignature)
+	fmt.Fprintf(os.Stdout, "wrote %s (levels %d-%d, speed %d, %d notes each)\n",
+		opts.levelsPath, opts.startLevel, opts.startLevel+levelCount-1, speed, opts.notes)
+	for _, level := range []int{opts.startLevel, opts.startLevel + levelCount - 1} {
+		info, _ := cfg.LevelInfo(level)
+		fmt.Fprintf(os.Stdout, "level %d: %v beats, %.1fs playback\n",
+			level, info.Beats, info.Beats*secondsPerMinute/float64(info.Speed))
+	}
+	if opts.trackID != "" {
+		fmt.Fprintf(os.Stdout, "track manifest row: %s,%d,%s,<url of %s>\n",
+			opts.trackID, chart.Tempo, chart.TimeSignature, opts.sheetPath)
+	}
+	return nil
+}
+
+func listTracks(src []byte) error {
+	tracks, err := loader.ListMIDITracks(bytes.NewReader(src))
+	if err != nil {
+		return fmt.Errorf("read midi: %w", err)
+	}
+	for _, track := range tracks {
+		fmt.Fprintf(os.Stdout, "%d\t%d notes\t%s\n", track.Index, track.Notes, track.Name)
+	}
+	return nil
+}
+
+// formatLevels writes a single level range in which each level plays the next opts.notes notes.
+func formatLevels(opts options, levelCount, speed int) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"level start", "level end", "speed", "notes"}
+	row := []string{
+		strconv.Itoa(opts.startLevel),
+		strconv.Itoa(opts.startLevel + levelCount - 1),
+		strconv.Itoa(speed),
+		strconv.Itoa(opts.notes),
+	}
+	if opts.trackID != "" {
+		header = append(header, "track")
+		row = append(row, opts.trackID)
+	}
+	if err := w.WriteAll([][]string{header, row}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// validate reads both CSVs back the way the backend does and builds every level from them.
+func validate(levelsCSV, sheetCSV []byte, trackID string, chart loader.MIDIChart) (*config.GameConfig, error) {
+	levels, err := loader.ParseLevels(bytes.NewReader(levelsCSV))
+	if err != nil {
+		return nil, fmt.Errorf("level csv: %w", err)
+	}
+	notes, err := loader.ParseSheetMusic(bytes.NewReader(sheetCSV))
+	if err != nil {
+		return nil, fmt.Errorf("sheet csv: %w", err)
+	}
+	if trackID == "" {
+		trackID = models.DefaultTrackID
+	}
+	return config.NewGameConfig(0, levels, []models.SheetMusic{{
+		TrackID:       trackID,
+		Tempo:         chart.Tempo,
+		TimeSignature: chart.TimeSignature,
+		Notes:         notes,
+	}})
+}
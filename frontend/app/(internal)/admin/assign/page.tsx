@@ -2,10 +2,10 @@
 
 import { useAdminAssignCoupon, useAdminSearchUsers } from "@/hooks/api";
 import { usePopupStore } from "@/stores";
-import type { User } from "@/types/api";
+import type { AdminUser } from "@/types/api";
 import { useState } from "react";
 
-function UserSearchResult({ user, onSelect, selected }: { user: User; onSelect: (user: User) => void; selected: boolean }) {
+function UserSearchResult({ user, onSelect, selected }: { user: AdminUser; onSelect: (user: AdminUser) => void; selected: boolean }) {
 	return (
 		<button
 			onClick={() => onSelect(user)}
@@ -25,7 +25,7 @@ function UserSearchResult({ user, onSelect, selected }: { user: User; onSelect:
 
 export default function AssignCouponPage() {
 	const [searchQuery, setSearchQuery] = useState("");
-	const [selectedUser, setSelectedUser] = useState<User | null>(null);
+	const [selectedUser, setSelectedUser] = useState<AdminUser | null>(null);
 	const [discountId, setDiscountId] = useState("");
 	const [price, setPrice] = useState(0);
 
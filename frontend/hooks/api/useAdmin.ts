@@ -1,6 +1,6 @@
 import { api } from "@/lib/api";
 import { queryKeys } from "@/lib/queryKeys";
-import type { AdminLoginResponse, DiscountCoupon, DiscountCouponGift, User } from "@/types/api";
+import type { AdminLoginResponse, AdminUser, DiscountCoupon, DiscountCouponGift } from "@/types/api";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
 
 /* ── Session ── */
@@ -59,7 +59,7 @@ export function useAdminDeleteGiftCoupon() {
 export function useAdminSearchUsers(q: string, limit = 20) {
 	return useQuery({
 		queryKey: queryKeys.admin.users(q),
-		queryFn: () => api.get<User[]>(`/admin/users?q=${encodeURIComponent(q)}&limit=${limit}`),
+		queryFn: () => api.get<AdminUser[]>(`/admin/users?q=${encodeURIComponent(q)}&limit=${limit}`),
 		enabled: q.length > 0
 	});
 }
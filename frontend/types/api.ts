@@ -213,6 +213,19 @@ export interface AdminLoginResponse {
 	authenticated: boolean;
 }
 
+/** User as returned by GET /admin/users; coupon_token is never included. */
+export interface AdminUser {
+	id: string;
+	nickname: string;
+	avatar?: string | null;
+	group?: string;
+	current_level: number;
+	unlock_level: number;
+	last_pass_time: string;
+	created_at: string;
+	updated_at: string;
+}
+
 export interface DiscountCouponGift {
 	id: string;
 	discount_id: string;